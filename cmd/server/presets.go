@@ -3,21 +3,58 @@ package main
 // all known presets should go right here so that they are available when
 // requested over HTTP.
 import (
+	_ "github.com/orlangure/gnomock/preset/arangodb"
+	_ "github.com/orlangure/gnomock/preset/artemis"
+	_ "github.com/orlangure/gnomock/preset/azurite"
+	_ "github.com/orlangure/gnomock/preset/bigtable"
 	_ "github.com/orlangure/gnomock/preset/cassandra"
+	_ "github.com/orlangure/gnomock/preset/clickhouse"
 	_ "github.com/orlangure/gnomock/preset/cockroachdb"
+	_ "github.com/orlangure/gnomock/preset/consul"
+	_ "github.com/orlangure/gnomock/preset/couchbase"
+	_ "github.com/orlangure/gnomock/preset/couchdb"
+	_ "github.com/orlangure/gnomock/preset/db2"
+	_ "github.com/orlangure/gnomock/preset/dex"
+	_ "github.com/orlangure/gnomock/preset/dynamodb"
 	_ "github.com/orlangure/gnomock/preset/elastic"
+	_ "github.com/orlangure/gnomock/preset/emqx"
+	_ "github.com/orlangure/gnomock/preset/etcd"
+	_ "github.com/orlangure/gnomock/preset/firestore"
+	_ "github.com/orlangure/gnomock/preset/grafana"
 	_ "github.com/orlangure/gnomock/preset/influxdb"
+	_ "github.com/orlangure/gnomock/preset/jaeger"
 	_ "github.com/orlangure/gnomock/preset/k3s"
 	_ "github.com/orlangure/gnomock/preset/kafka"
+	_ "github.com/orlangure/gnomock/preset/keycloak"
 	_ "github.com/orlangure/gnomock/preset/localstack"
 	_ "github.com/orlangure/gnomock/preset/mariadb"
 	_ "github.com/orlangure/gnomock/preset/memcached"
+	_ "github.com/orlangure/gnomock/preset/minio"
 	_ "github.com/orlangure/gnomock/preset/mongo"
+	_ "github.com/orlangure/gnomock/preset/mosquitto"
 	_ "github.com/orlangure/gnomock/preset/mssql"
 	_ "github.com/orlangure/gnomock/preset/mysql"
+	_ "github.com/orlangure/gnomock/preset/nats"
+	_ "github.com/orlangure/gnomock/preset/neo4j"
+	_ "github.com/orlangure/gnomock/preset/oracle"
+	_ "github.com/orlangure/gnomock/preset/otelcollector"
 	_ "github.com/orlangure/gnomock/preset/postgres"
+	_ "github.com/orlangure/gnomock/preset/prometheus"
+	_ "github.com/orlangure/gnomock/preset/pubsub"
+	_ "github.com/orlangure/gnomock/preset/pulsar"
+	_ "github.com/orlangure/gnomock/preset/questdb"
 	_ "github.com/orlangure/gnomock/preset/rabbitmq"
 	_ "github.com/orlangure/gnomock/preset/redis"
+	_ "github.com/orlangure/gnomock/preset/rethinkdb"
+	_ "github.com/orlangure/gnomock/preset/scylla"
+	_ "github.com/orlangure/gnomock/preset/spanner"
 	_ "github.com/orlangure/gnomock/preset/splunk"
+	_ "github.com/orlangure/gnomock/preset/tidb"
+	_ "github.com/orlangure/gnomock/preset/trino"
+	_ "github.com/orlangure/gnomock/preset/vault"
+	_ "github.com/orlangure/gnomock/preset/victoriametrics"
+	_ "github.com/orlangure/gnomock/preset/yugabyte"
+	_ "github.com/orlangure/gnomock/preset/zipkin"
+	_ "github.com/orlangure/gnomock/preset/zookeeper"
 	// new presets go here.
 )
@@ -311,6 +311,10 @@ func (d *docker) createContainer(ctx context.Context, image string, ports NamedP
 		containerConfig.Entrypoint = cfg.Entrypoint
 	}
 
+	if cfg.User != "" {
+		containerConfig.User = cfg.User
+	}
+
 	mounts := []mount.Mount{}
 	for src, dst := range cfg.HostMounts {
 		mounts = append(mounts, mount.Mount{
@@ -320,6 +324,14 @@ func (d *docker) createContainer(ctx context.Context, image string, ports NamedP
 		})
 	}
 
+	for name, dst := range cfg.Volumes {
+		mounts = append(mounts, mount.Mount{
+			Type:   mount.TypeVolume,
+			Source: name,
+			Target: dst,
+		})
+	}
+
 	portBindings := d.portBindings(exposedPorts, ports)
 	hostConfig := &container.HostConfig{
 		PortBindings: portBindings,
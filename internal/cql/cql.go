@@ -0,0 +1,125 @@
+// Package cql provides initial setup helpers shared by presets of databases
+// that use Cassandra Query Language.
+package cql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocql/gocql"
+	"github.com/orlangure/gnomock/internal/script"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+)
+
+// splitter splits CQL scripts, where both "--" and "//" start a comment.
+var splitter = script.Splitter{Language: "cql", Comments: []string{"--", "//"}}
+
+// Node is a single database node that accepts CQL queries.
+type Node struct {
+	// Addr is the host:port address of the node.
+	Addr string
+
+	// Authenticator is used to connect to nodes that require
+	// authentication, and can be nil.
+	Authenticator gocql.Authenticator
+}
+
+// Connect creates a session that uses the provided keyspace, or no keyspace
+// at all if it is empty.
+func (n Node) Connect(keyspace string) (*gocql.Session, error) {
+	cluster := gocql.NewCluster(n.Addr)
+	cluster.Keyspace = keyspace
+	cluster.Authenticator = n.Authenticator
+
+	// nodes advertise their container addresses, which are not reachable
+	// from the host, so the driver should only use the provided address
+	cluster.DisableInitialHostLookup = true
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a new session: %w", err)
+	}
+
+	return session, nil
+}
+
+// Healthcheck makes sure the node accepts CQL queries, which only happens
+// once all the startup phases are complete.
+func (n Node) Healthcheck(ctx context.Context) error {
+	session, err := n.Connect("")
+	if err != nil {
+		return err
+	}
+
+	defer session.Close()
+
+	var version string
+
+	err = session.Query(`select release_version from system.local`).WithContext(ctx).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("failed to query node version: %w", err)
+	}
+
+	return nil
+}
+
+// Init creates the keyspace, if it is not empty, and executes CQL statements
+// from the provided files and queries, in this order, using the keyspace.
+func (n Node) Init(keyspace string, files, queries []string) error {
+	statements, err := splitter.ReadFiles(files)
+	if err != nil {
+		return err
+	}
+
+	if keyspace != "" {
+		session, err := n.Connect("")
+		if err != nil {
+			return err
+		}
+
+		err = createKeyspace(session, keyspace, 1)
+
+		session.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	session, err := n.Connect(keyspace)
+	if err != nil {
+		return err
+	}
+
+	defer session.Close()
+
+	return execute(session, append(statements, queries...))
+}
+
+// createKeyspace creates a keyspace with the provided name and replication
+// factor using SimpleStrategy, unless it already exists.
+func createKeyspace(session *gocql.Session, name string, replicationFactor int) error {
+	q := fmt.Sprintf(
+		`create keyspace if not exists %s
+		with replication = {'class': 'SimpleStrategy', 'replication_factor': %d}`,
+		name, replicationFactor,
+	)
+
+	if err := session.Query(q).Exec(); err != nil {
+		return fmt.Errorf("can't create keyspace '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// execute executes the provided statements one by one, and stops on the
+// first failure.
+func execute(session *gocql.Session, statements []string) error {
+	for i, s := range statements {
+		if err := session.Query(s).Exec(); err != nil {
+			return fmt.Errorf("statement %d of %d failed (%s): %w", i+1, len(statements), sqlsetup.Snippet(s), err)
+		}
+	}
+
+	return nil
+}
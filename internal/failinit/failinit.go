@@ -0,0 +1,18 @@
+// Package failinit reports preset configuration errors found in Options.
+package failinit
+
+import (
+	"context"
+
+	"github.com/orlangure/gnomock"
+)
+
+// Func returns an init function that fails with the provided error. Presets
+// use it when they can't be configured: the container starts without any
+// setup, and is stopped as soon as the init function fails, instead of
+// failing the healthcheck until the timeout.
+func Func(err error) gnomock.InitFunc {
+	return func(context.Context, *gnomock.Container) error {
+		return err
+	}
+}
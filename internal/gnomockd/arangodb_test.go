@@ -0,0 +1,77 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	"github.com/orlangure/gnomock/preset/arangodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArangoDB(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/arangodb.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/arangodb", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	query, err := json.Marshal(map[string]string{"query": "RETURN LENGTH(items)"})
+	require.NoError(t, err)
+
+	addr := fmt.Sprintf("http://%s/_db/inventory/_api/cursor", c.DefaultAddress())
+
+	req, err := http.NewRequest(http.MethodPost, addr, bytes.NewReader(query)) // nolint:noctx
+	require.NoError(t, err)
+
+	req.SetBasicAuth(arangodb.DefaultUser, "secret")
+
+	cursorRes, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, cursorRes.Body.Close()) }()
+
+	require.Equal(t, http.StatusCreated, cursorRes.StatusCode)
+
+	var cursor struct {
+		Result []int `json:"result"`
+	}
+
+	require.NoError(t, json.NewDecoder(cursorRes.Body).Decode(&cursor))
+	require.Equal(t, []int{0}, cursor.Result)
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
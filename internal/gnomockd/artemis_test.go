@@ -0,0 +1,100 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	"github.com/orlangure/gnomock/preset/artemis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtemis(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/artemis.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/artemis", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	jolokia := func(request map[string]interface{}) []string {
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		addr := fmt.Sprintf("http://%s/console/jolokia", c.Address(artemis.ConsolePort))
+
+		req, err := http.NewRequest(http.MethodPost, addr, bytes.NewReader(body)) // nolint:noctx
+		require.NoError(t, err)
+
+		req.SetBasicAuth("admin", "secret")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, resp.Body.Close()) }()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var out struct {
+			Value []string `json:"value"`
+		}
+
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+		return out.Value
+	}
+
+	brokers := jolokia(map[string]interface{}{
+		"type":  "search",
+		"mbean": "org.apache.activemq.artemis:broker=*",
+	})
+	require.Len(t, brokers, 1)
+
+	queues := jolokia(map[string]interface{}{
+		"type":      "exec",
+		"mbean":     brokers[0],
+		"operation": "getQueueNames()",
+	})
+	require.Contains(t, queues, "orders")
+
+	addresses := jolokia(map[string]interface{}{
+		"type":      "exec",
+		"mbean":     brokers[0],
+		"operation": "getAddressNames()",
+	})
+	require.Contains(t, addresses, "events")
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,67 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azqueue"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	"github.com/orlangure/gnomock/preset/azurite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzurite(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/azurite.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/azurite", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	blobClient, err := azblob.NewClientFromConnectionString(azurite.ConnectionString(c), nil)
+	require.NoError(t, err)
+
+	_, err = blobClient.ServiceClient().NewContainerClient("images").GetProperties(context.Background(), nil)
+	require.NoError(t, err)
+
+	queueClient, err := azqueue.NewServiceClientFromConnectionString(azurite.ConnectionString(c), nil)
+	require.NoError(t, err)
+
+	_, err = queueClient.NewQueueClient("jobs").GetProperties(context.Background(), nil)
+	require.NoError(t, err)
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
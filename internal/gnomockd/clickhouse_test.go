@@ -0,0 +1,71 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	"github.com/orlangure/gnomock/preset/clickhouse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickHouse(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/clickhouse.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/clickhouse", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	q := url.Values{"query": []string{"exists table gnomock.events"}}.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, "http://"+c.Address(clickhouse.HTTPPort)+"/?"+q, nil)
+	require.NoError(t, err)
+
+	req.SetBasicAuth("gnomock", "gnomick")
+
+	queryRes, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, queryRes.Body.Close()) }()
+
+	out, err := io.ReadAll(queryRes.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, queryRes.StatusCode, string(out))
+	require.Equal(t, "1", strings.TrimSpace(string(out)))
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
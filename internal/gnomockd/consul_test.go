@@ -0,0 +1,77 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	_ "github.com/orlangure/gnomock/preset/consul"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsul(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/consul.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/consul", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	kvRes, err := http.Get(fmt.Sprintf("http://%s/v1/kv/config/app/name?raw", c.DefaultAddress())) // nolint:gosec,noctx
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, kvRes.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, kvRes.StatusCode)
+
+	value, err := io.ReadAll(kvRes.Body)
+	require.NoError(t, err)
+	require.Equal(t, "gnomock", string(value))
+
+	serviceRes, err := http.Get(fmt.Sprintf("http://%s/v1/catalog/service/web", c.DefaultAddress())) // nolint:gosec,noctx
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, serviceRes.Body.Close()) }()
+
+	var services []struct {
+		ServiceAddress string `json:"ServiceAddress"`
+	}
+
+	require.NoError(t, json.NewDecoder(serviceRes.Body).Decode(&services))
+	require.Len(t, services, 1)
+	require.Equal(t, "10.0.0.1", services[0].ServiceAddress)
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,78 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	"github.com/orlangure/gnomock/preset/couchbase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCouchbase(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/couchbase.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/couchbase", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	form := url.Values{"statement": []string{"select raw count(*) from shop.inventory.items"}}
+	addr := fmt.Sprintf("http://%s/query/service", c.Address(couchbase.QueryPort))
+
+	req, err := http.NewRequest(http.MethodPost, addr, strings.NewReader(form.Encode())) // nolint:noctx
+	require.NoError(t, err)
+
+	req.SetBasicAuth("admin", "secret")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	queryRes, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, queryRes.Body.Close()) }()
+
+	var result struct {
+		Results []interface{} `json:"results"`
+		Errors  []interface{} `json:"errors"`
+	}
+
+	require.NoError(t, json.NewDecoder(queryRes.Body).Decode(&result))
+	require.Empty(t, result.Errors)
+	require.Equal(t, []interface{}{float64(0)}, result.Results)
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
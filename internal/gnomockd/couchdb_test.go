@@ -0,0 +1,72 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	_ "github.com/orlangure/gnomock/preset/couchdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCouchDB(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/couchdb.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/couchdb", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/orders", c.DefaultAddress()), nil) // nolint:noctx
+	require.NoError(t, err)
+
+	req.SetBasicAuth("admin", "secret")
+
+	dbRes, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, dbRes.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, dbRes.StatusCode)
+
+	var info struct {
+		DocCount int `json:"doc_count"`
+	}
+
+	require.NoError(t, json.NewDecoder(dbRes.Body).Decode(&info))
+	require.Equal(t, 0, info.DocCount)
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,81 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	"github.com/orlangure/gnomock/preset/dex"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDex(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/dex.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/dex", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	form := url.Values{
+		"grant_type": {"password"},
+		"scope":      {"openid email"},
+		"username":   {"alice@example.com"},
+		"password":   {"alice-password"},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, dex.Issuer(c)+"/token", strings.NewReader(form.Encode())) // nolint:noctx
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("app", "app-secret")
+
+	tokenRes, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, tokenRes.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, tokenRes.StatusCode)
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+
+	require.NoError(t, json.NewDecoder(tokenRes.Body).Decode(&token))
+	require.NotEmpty(t, token.IDToken)
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,80 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	_ "github.com/orlangure/gnomock/preset/emqx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEMQX(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/emqx.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/emqx", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	opts := mqtt.NewClientOptions().
+		AddBroker("tcp://" + c.DefaultAddress()).
+		SetUsername("device").
+		SetPassword("secret")
+	client := mqtt.NewClient(opts)
+
+	token := client.Connect()
+	require.True(t, token.WaitTimeout(time.Second*5))
+	require.NoError(t, token.Error())
+
+	defer client.Disconnect(0)
+
+	messages := make(chan mqtt.Message, 1)
+
+	token = client.Subscribe("devices/1/state", 1, func(_ mqtt.Client, m mqtt.Message) { messages <- m })
+	require.True(t, token.WaitTimeout(time.Second*5))
+	require.NoError(t, token.Error())
+
+	select {
+	case m := <-messages:
+		require.True(t, m.Retained())
+		require.Equal(t, "on", string(m.Payload()))
+	case <-time.After(time.Second * 5):
+		require.FailNow(t, "retained message not received")
+	}
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,76 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	_ "github.com/orlangure/gnomock/preset/etcd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEtcd(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/etcd.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/etcd", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	key, err := json.Marshal(map[string][]byte{"key": []byte("/config/name")})
+	require.NoError(t, err)
+
+	rangeRes, err := http.Post( // nolint:gosec,noctx
+		fmt.Sprintf("http://%s/v3/kv/range", c.DefaultAddress()),
+		"application/json", bytes.NewReader(key),
+	)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, rangeRes.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, rangeRes.StatusCode)
+
+	var kvs struct {
+		KVs []struct {
+			Value []byte `json:"value"`
+		} `json:"kvs"`
+	}
+
+	require.NoError(t, json.NewDecoder(rangeRes.Body).Decode(&kvs))
+	require.Len(t, kvs.KVs, 1)
+	require.Equal(t, "gnomock", string(kvs.KVs[0].Value))
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,68 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	"github.com/orlangure/gnomock/preset/jaeger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJaeger(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/jaeger.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/jaeger", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	servicesRes, err := http.Get(fmt.Sprintf("http://%s/api/services", c.DefaultAddress())) // nolint:gosec,noctx
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, servicesRes.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, servicesRes.StatusCode)
+
+	var services struct {
+		Errors []interface{} `json:"errors"`
+	}
+
+	require.NoError(t, json.NewDecoder(servicesRes.Body).Decode(&services))
+	require.Empty(t, services.Errors)
+	require.NotEmpty(t, c.Address(jaeger.OTLPHTTPPort))
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,79 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	"github.com/orlangure/gnomock/preset/keycloak"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeycloak(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/keycloak.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/keycloak", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	form := url.Values{
+		"grant_type":    {"password"},
+		"client_id":     {"backend"},
+		"client_secret": {"backend-secret"},
+		"username":      {"alice"},
+		"password":      {"alice-password"},
+	}
+
+	tokenRes, err := http.Post( // nolint:gosec,noctx
+		keycloak.Issuer(c, "app")+"/protocol/openid-connect/token",
+		"application/x-www-form-urlencoded", strings.NewReader(form.Encode()),
+	)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, tokenRes.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, tokenRes.StatusCode)
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+
+	require.NoError(t, json.NewDecoder(tokenRes.Body).Decode(&token))
+	require.NotEmpty(t, token.AccessToken)
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -58,6 +58,14 @@ func TestMemcached(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "hal9000", string(itemC.Value))
 
+	itemD, err := client.Get("binary")
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, itemD.Value)
+
+	itemE, err := client.Get("session")
+	require.NoError(t, err)
+	require.Equal(t, "abc", string(itemE.Value))
+
 	bs, err = json.Marshal(c)
 	require.NoError(t, err)
 
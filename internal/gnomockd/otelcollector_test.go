@@ -0,0 +1,75 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	"github.com/orlangure/gnomock/preset/otelcollector"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTelCollector(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/otelcollector.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/otelcollector", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	metric := fmt.Sprintf(
+		`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"requests","gauge":{"dataPoints":[{"asInt":"42","timeUnixNano":"%d"}]}}]}]}]}`,
+		time.Now().UnixNano(),
+	)
+
+	exportRes, err := http.Post( // nolint:gosec,noctx
+		fmt.Sprintf("http://%s/v1/metrics", c.Address(otelcollector.OTLPHTTPPort)),
+		"application/json", strings.NewReader(metric),
+	)
+	require.NoError(t, err)
+	require.NoError(t, exportRes.Body.Close())
+	require.Equal(t, http.StatusOK, exportRes.StatusCode)
+
+	require.Eventually(t, func() bool {
+		metrics, err := otelcollector.MetricsText(context.Background(), c)
+
+		return err == nil && strings.Contains(metrics, "requests 42")
+	}, time.Second*10, time.Millisecond*250)
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
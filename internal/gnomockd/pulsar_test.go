@@ -0,0 +1,65 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	"github.com/orlangure/gnomock/preset/pulsar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPulsar(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/pulsar.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/pulsar", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	topicsRes, err := http.Get(fmt.Sprintf("http://%s/admin/v2/persistent/shop/orders", c.Address(pulsar.AdminPort))) // nolint:gosec,noctx
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, topicsRes.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, topicsRes.StatusCode)
+
+	var topics []string
+
+	require.NoError(t, json.NewDecoder(topicsRes.Body).Decode(&topics))
+	require.Equal(t, []string{"persistent://shop/orders/created"}, topics)
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,68 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	_ "github.com/orlangure/gnomock/preset/rethinkdb"
+	"github.com/stretchr/testify/require"
+	rdb "gopkg.in/rethinkdb/rethinkdb-go.v6"
+)
+
+func TestRethinkDB(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/rethinkdb.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/rethinkdb", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	session, err := rdb.Connect(rdb.ConnectOpts{Address: c.DefaultAddress()})
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, session.Close()) }()
+
+	cursor, err := rdb.DB("inventory").Table("items").Count().Run(session)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, cursor.Close()) }()
+
+	count := -1
+
+	require.NoError(t, cursor.One(&count))
+	require.Equal(t, 0, count)
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,78 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	_ "github.com/orlangure/gnomock/preset/victoriametrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVictoriaMetrics(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/victoriametrics.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/victoriametrics", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	params := url.Values{
+		"query": []string{`up{job="api"}`},
+		"time":  []string{"1672531200"},
+	}
+
+	queryRes, err := http.Get(fmt.Sprintf("http://%s/api/v1/query?%s", c.DefaultAddress(), params.Encode())) // nolint:gosec,noctx
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, queryRes.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, queryRes.StatusCode)
+
+	var result struct {
+		Data struct {
+			Result []struct {
+				Value []interface{} `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+
+	require.NoError(t, json.NewDecoder(queryRes.Body).Decode(&result))
+	require.Len(t, result.Data.Result, 1)
+	require.Equal(t, "1", result.Data.Result[0].Value[1])
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,78 @@
+package gnomockd_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/gnomockd"
+	"github.com/orlangure/gnomock/preset/yugabyte"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYugabyte(t *testing.T) {
+	t.Parallel()
+
+	h := gnomockd.Handler()
+	bs, err := os.ReadFile("./testdata/yugabyte.json")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(bs)
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/start/yugabyte", buf)
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, res.StatusCode, string(body))
+
+	var c *gnomock.Container
+
+	err = json.Unmarshal(body, &c)
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", yugabyte.ConnString(c, yugabyte.WithDatabase("gnomock")))
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, db.Close()) }()
+
+	count := -1
+
+	require.NoError(t, db.QueryRow("select count(*) from things").Scan(&count))
+	require.Equal(t, 0, count)
+
+	cluster := gocql.NewCluster(c.Address(yugabyte.YCQLPort))
+	cluster.Keyspace = "gnomock"
+	cluster.DisableInitialHostLookup = true
+
+	session, err := cluster.CreateSession()
+	require.NoError(t, err)
+
+	defer session.Close()
+
+	count = -1
+
+	require.NoError(t, session.Query("select count(*) from users").Scan(&count))
+	require.Equal(t, 0, count)
+
+	bs, err = json.Marshal(c)
+	require.NoError(t, err)
+
+	buf = bytes.NewBuffer(bs)
+	w, r = httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/stop", buf)
+	h.ServeHTTP(w, r)
+
+	res = w.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,16 @@
+// Package hostpath handles paths on the host that runs the containers.
+package hostpath
+
+import "path/filepath"
+
+// Abs returns an absolute version of the provided host path, since docker
+// only allows to mount absolute paths. The path is returned as is if it can't
+// be made absolute.
+func Abs(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	return abs
+}
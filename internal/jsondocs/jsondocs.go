@@ -0,0 +1,41 @@
+// Package jsondocs reads JSON documents used to seed document databases.
+package jsondocs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Read returns documents found in the provided reader, which includes either
+// a single array of documents, or a sequence of documents, such as one
+// document per line.
+func Read(r io.Reader) ([]json.RawMessage, error) {
+	decoder := json.NewDecoder(r)
+	docs := []json.RawMessage{}
+
+	for decoder.More() {
+		var doc json.RawMessage
+
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("can't read documents: %w", err)
+		}
+
+		if trimmed := bytes.TrimSpace(doc); len(trimmed) > 0 && trimmed[0] == '[' {
+			var array []json.RawMessage
+
+			if err := json.Unmarshal(trimmed, &array); err != nil {
+				return nil, fmt.Errorf("can't read documents: %w", err)
+			}
+
+			docs = append(docs, array...)
+
+			continue
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
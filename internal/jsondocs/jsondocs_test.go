@@ -0,0 +1,37 @@
+package jsondocs_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gnomock/internal/jsondocs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	t.Run("array", func(t *testing.T) {
+		docs, err := jsondocs.Read(strings.NewReader(`[{"a": 1}, {"b": 2}]`))
+		require.NoError(t, err)
+		require.Equal(t, []json.RawMessage{[]byte(`{"a": 1}`), []byte(`{"b": 2}`)}, docs)
+	})
+
+	t.Run("one per line", func(t *testing.T) {
+		docs, err := jsondocs.Read(strings.NewReader("{\"a\": 1}\n{\"b\": 2}\n"))
+		require.NoError(t, err)
+		require.Equal(t, []json.RawMessage{[]byte(`{"a": 1}`), []byte(`{"b": 2}`)}, docs)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		docs, err := jsondocs.Read(strings.NewReader(""))
+		require.NoError(t, err)
+		require.Empty(t, docs)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := jsondocs.Read(strings.NewReader(`{"a": `))
+		require.Error(t, err)
+	})
+}
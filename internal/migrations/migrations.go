@@ -0,0 +1,30 @@
+// Package migrations allows to apply database migrations using
+// golang-migrate during initial setup of SQL presets.
+package migrations
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	_ "github.com/golang-migrate/migrate/v4/source/file" // file:// source
+)
+
+// Apply runs all "up" migrations found at the provided source URL, such as
+// "file://migrations", against a database using the provided golang-migrate
+// driver. Database name is only used in error messages. It is not an error if
+// there are no migrations to apply.
+func Apply(sourceURL, dbName string, driver database.Driver) error {
+	m, err := migrate.NewWithDatabaseInstance(sourceURL, dbName, driver)
+	if err != nil {
+		return fmt.Errorf("can't read migrations from '%s': %w", sourceURL, err)
+	}
+
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("can't apply migrations from '%s': %w", sourceURL, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/database/stub"
+	"github.com/orlangure/gnomock/internal/migrations"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	t.Run("applies all migrations", func(t *testing.T) {
+		driver, err := stub.WithInstance(nil, &stub.Config{})
+		require.NoError(t, err)
+
+		require.NoError(t, migrations.Apply("file://./testdata", "stub", driver))
+
+		s := driver.(*stub.Stub)
+		require.Equal(t, 2, s.CurrentVersion)
+		require.Equal(t, []string{
+			"create table t (a int);\n",
+			"insert into t (a) values (1);\n",
+		}, s.MigrationSequence)
+
+		// nothing left to apply
+		require.NoError(t, migrations.Apply("file://./testdata", "stub", driver))
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		driver, err := stub.WithInstance(nil, &stub.Config{})
+		require.NoError(t, err)
+
+		err = migrations.Apply("file://./missing", "stub", driver)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "can't read migrations from 'file://./missing'")
+	})
+}
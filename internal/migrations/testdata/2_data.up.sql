@@ -0,0 +1 @@
+insert into t (a) values (1);
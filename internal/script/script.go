@@ -0,0 +1,72 @@
+// Package script splits query scripts, such as CQL or Cypher files, into
+// separate statements that can be executed one by one.
+package script
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Splitter splits scripts into statements separated by semicolons, and skips
+// whole line comments. Semicolons inside string literals are not supported.
+type Splitter struct {
+	// Language is the name of the query language used in error messages,
+	// for example "cql".
+	Language string
+
+	// Comments are prefixes of lines that should be ignored, for example
+	// "--" or "//".
+	Comments []string
+}
+
+// Split returns separate statements found in the provided script.
+func (s Splitter) Split(script string) []string {
+	lines := strings.Split(script, "\n")
+	kept := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		if !s.isComment(line) {
+			kept = append(kept, line)
+		}
+	}
+
+	statements := []string{}
+
+	for _, statement := range strings.Split(strings.Join(kept, "\n"), ";") {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+
+	return statements
+}
+
+// ReadFiles returns statements found in the provided files, in the same
+// order.
+func (s Splitter) ReadFiles(files []string) ([]string, error) {
+	statements := []string{}
+
+	for _, f := range files {
+		bs, err := os.ReadFile(f) // nolint:gosec
+		if err != nil {
+			return nil, fmt.Errorf("can't read %s file '%s': %w", s.Language, f, err)
+		}
+
+		statements = append(statements, s.Split(string(bs))...)
+	}
+
+	return statements, nil
+}
+
+func (s Splitter) isComment(line string) bool {
+	line = strings.TrimSpace(line)
+
+	for _, prefix := range s.Comments {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
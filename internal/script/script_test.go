@@ -0,0 +1,44 @@
+package script_test
+
+import (
+	"testing"
+
+	"github.com/orlangure/gnomock/internal/script"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	splitter := script.Splitter{Language: "cql", Comments: []string{"--", "//"}}
+	cql := `
+-- comment
+create table t (id int primary key);
+// another comment
+insert into t (id)
+values (1);
+
+insert into t (id) values (2)
+`
+
+	require.Equal(t, []string{
+		"create table t (id int primary key)",
+		"insert into t (id)\nvalues (1)",
+		"insert into t (id) values (2)",
+	}, splitter.Split(cql))
+	require.Empty(t, splitter.Split("  ;\n-- nothing here\n"))
+
+	// only the configured prefixes start comments
+	splitter = script.Splitter{Language: "cypher", Comments: []string{"//"}}
+	require.Equal(t, []string{"-- RETURN 1"}, splitter.Split("-- RETURN 1;\n  // RETURN 2\n"))
+}
+
+func TestReadFiles(t *testing.T) {
+	t.Parallel()
+
+	splitter := script.Splitter{Language: "cql", Comments: []string{"--"}}
+
+	_, err := splitter.ReadFiles([]string{"./missing.cql"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read cql file './missing.cql'")
+}
@@ -0,0 +1,11 @@
+// Package shell helps presets to build shell scripts that run inside their
+// containers.
+package shell
+
+import "strings"
+
+// Quote quotes the provided value for a POSIX shell, so that it is passed to
+// a command as a single argument without any expansion.
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
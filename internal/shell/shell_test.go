@@ -0,0 +1,19 @@
+package shell_test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/orlangure/gnomock/internal/shell"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuote(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"", "foo", "it's $HOME", `a "b" \c`, "line\nbreak"} {
+		out, err := exec.Command("/bin/sh", "-c", "printf '%s' "+shell.Quote(s)).Output()
+		require.NoError(t, err)
+		require.Equal(t, s, string(out))
+	}
+}
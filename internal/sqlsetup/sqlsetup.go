@@ -0,0 +1,112 @@
+// Package sqlsetup provides initial setup steps shared by SQL presets:
+// reading queries files, applying migrations and executing setup queries.
+// Presets use it to implement WithQueries, WithQueriesFile and WithMigrations
+// options the same way.
+package sqlsetup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/orlangure/gnomock/internal/migrations"
+)
+
+// Execer executes queries. Both *sql.DB and *sql.Tx implement it.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Setup describes initial state of a database.
+type Setup struct {
+	// Queries are executed after the queries read from QueriesFiles.
+	Queries []string
+
+	// QueriesFiles are read and executed in the same order, every file as a
+	// single query.
+	QueriesFiles []string
+
+	// Migrations is a golang-migrate source URL, such as
+	// "file://migrations". Migrations are applied before any queries.
+	Migrations string
+}
+
+// Apply applies migrations and executes setup queries against the provided
+// database. Driver is only called when migrations are configured, and should
+// return golang-migrate driver of the same database. Database name is used in
+// error messages.
+func (s Setup) Apply(ctx context.Context, db *sql.DB, dbName string, driver DriverFunc) error {
+	if err := s.Migrate(db, dbName, driver); err != nil {
+		return err
+	}
+
+	queries, err := s.AllQueries()
+	if err != nil {
+		return err
+	}
+
+	return Execute(ctx, db, queries)
+}
+
+// DriverFunc creates golang-migrate driver that uses the provided database.
+type DriverFunc func(*sql.DB) (database.Driver, error)
+
+// Migrate applies migrations, if they are configured, using the provided
+// driver. See Apply.
+func (s Setup) Migrate(db *sql.DB, dbName string, driver DriverFunc) error {
+	if s.Migrations == "" {
+		return nil
+	}
+
+	d, err := driver(db)
+	if err != nil {
+		return fmt.Errorf("can't create migrations driver: %w", err)
+	}
+
+	return migrations.Apply(s.Migrations, dbName, d)
+}
+
+// AllQueries returns the contents of queries files followed by the rest of
+// the queries.
+func (s Setup) AllQueries() ([]string, error) {
+	queries := make([]string, 0, len(s.QueriesFiles)+len(s.Queries))
+
+	for _, f := range s.QueriesFiles {
+		bs, err := os.ReadFile(f) // nolint:gosec
+		if err != nil {
+			return nil, fmt.Errorf("can't read queries file '%s': %w", f, err)
+		}
+
+		queries = append(queries, string(bs))
+	}
+
+	return append(queries, s.Queries...), nil
+}
+
+// Execute executes the provided queries one by one, and stops on the first
+// failure. The error includes a part of the failed query.
+func Execute(ctx context.Context, db Execer, queries []string) error {
+	for i, q := range queries {
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			return fmt.Errorf("query %d of %d failed (%s): %w", i+1, len(queries), Snippet(q), err)
+		}
+	}
+
+	return nil
+}
+
+// Snippet returns a short single-line version of the provided query to be
+// used in error messages.
+func Snippet(q string) string {
+	const maxLen = 60
+
+	q = strings.Join(strings.Fields(q), " ")
+	if len(q) > maxLen {
+		q = q[:maxLen] + "..."
+	}
+
+	return q
+}
@@ -0,0 +1,71 @@
+package sqlsetup_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	queries []string
+	fail    string
+}
+
+func (r *recorder) ExecContext(_ context.Context, q string, _ ...interface{}) (sql.Result, error) {
+	if q == r.fail {
+		return nil, errors.New("boom")
+	}
+
+	r.queries = append(r.queries, q)
+
+	return nil, nil
+}
+
+func TestAllQueries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("files before queries", func(t *testing.T) {
+		s := sqlsetup.Setup{
+			Queries:      []string{"insert into t values (1)"},
+			QueriesFiles: []string{"./testdata/queries.sql"},
+		}
+
+		queries, err := s.AllQueries()
+		require.NoError(t, err)
+		require.Equal(t, []string{"create table t (a int);\n", "insert into t values (1)"}, queries)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		s := sqlsetup.Setup{QueriesFiles: []string{"./testdata/missing.sql"}}
+
+		_, err := s.AllQueries()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "can't read queries file './testdata/missing.sql'")
+	})
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("executes all queries", func(t *testing.T) {
+		r := &recorder{}
+
+		require.NoError(t, sqlsetup.Execute(context.Background(), r, []string{"a", "b"}))
+		require.Equal(t, []string{"a", "b"}, r.queries)
+	})
+
+	t.Run("stops on failure", func(t *testing.T) {
+		long := "select\n" + strings.Repeat("x", 100)
+		r := &recorder{fail: long}
+
+		err := sqlsetup.Execute(context.Background(), r, []string{"a", long, "b"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query 2 of 3 failed (select "+strings.Repeat("x", 53)+"...)")
+		require.Equal(t, []string{"a"}, r.queries)
+	})
+}
@@ -0,0 +1 @@
+create table t (a int);
@@ -0,0 +1,25 @@
+// Package tlsutil generates self-signed certificates for presets that serve
+// TLS, and builds client TLS configuration that trusts them.
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+)
+
+// Config returns client TLS configuration that trusts the provided PEM encoded
+// certificate, and verifies that the server certificate is valid for the
+// provided host. An empty host leaves server name selection to the client.
+func Config(certPEM, host string) (*tls.Config, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(certPEM)) {
+		return nil, errors.New("can't parse tls certificate")
+	}
+
+	return &tls.Config{
+		RootCAs:    pool,
+		ServerName: host,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
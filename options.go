@@ -150,6 +150,14 @@ func WithEntrypoint(entrypoint string, args ...string) Option {
 	}
 }
 
+// WithUser sets the user (name or UID, optionally with a group) that runs the
+// container processes, overriding the user defined in docker image.
+func WithUser(user string) Option {
+	return func(o *Options) {
+		o.User = user
+	}
+}
+
 // WithHostMounts allows to bind host path (`src`) inside the container under
 // `dst` path.
 func WithHostMounts(src, dst string) Option {
@@ -162,6 +170,20 @@ func WithHostMounts(src, dst string) Option {
 	}
 }
 
+// WithVolume allows to mount a named docker volume (`name`) inside the
+// container under `dst` path. The volume is created if it does not exist yet.
+// Unlike the container itself, the volume is not removed when the container
+// stops, so its contents can be used by other containers later.
+func WithVolume(name, dst string) Option {
+	return func(o *Options) {
+		if o.Volumes == nil {
+			o.Volumes = make(map[string]string)
+		}
+
+		o.Volumes[name] = dst
+	}
+}
+
 // WithDisableAutoCleanup disables auto-removal of this container when the
 // tests complete. Automatic cleanup is a safety net for tests that for some
 // reason fail to run `gnomock.Stop()` in the end, for example due to an
@@ -271,9 +293,17 @@ type Options struct {
 	// is run. The difference between this and Cmd, is that Cmd will be given as an
 	// argument to Entrypoint.
 	Entrypoint []string `json:"entrypoint"`
+
+	// User is the user that runs the container processes. If empty, the user
+	// defined in docker image is used.
+	User string `json:"user"`
+
 	// HostMounts allows to mount local paths into the container.
 	HostMounts map[string]string `json:"host_mounts"`
 
+	// Volumes allows to mount named docker volumes into the container.
+	Volumes map[string]string `json:"volumes"`
+
 	// DisableAutoCleanup prevents the container from being automatically
 	// stopped and removed after the tests are complete. By default, Gnomock
 	// will try to stop containers created by it right after the tests exit.
@@ -0,0 +1,63 @@
+package arangodb
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithPassword sets the password of DefaultUser. If not used, the password is
+// empty.
+func WithPassword(password string) Option {
+	return func(o *P) {
+		o.Password = password
+	}
+}
+
+// WithDatabase creates a database with the provided name in the container.
+// Collections provided using WithCollections are created in this database.
+// If not used, "_system" database is used.
+func WithDatabase(db string) Option {
+	return func(o *P) {
+		o.DB = db
+	}
+}
+
+// WithCollections creates document collections with the provided names in
+// the database created using WithDatabase, or in "_system" database. This
+// option can be used multiple times.
+func WithCollections(names ...string) Option {
+	return func(o *P) {
+		o.Collections = append(o.Collections, names...)
+	}
+}
+
+// WithData sets up initial container state according to the directory
+// structure at the given path:
+//
+//   - path/first/one.json
+//   - path/first/two.json
+//   - path/second/three.json
+//
+// For such directory structure, two databases are created: "first" and
+// "second". Under "first" database there are two collections, "one" and "two",
+// and under "second" database - one collection "three".
+//
+// Files "one.json", "two.json" and "three.json" contain JSON documents to be
+// inserted into the collections, either one document per line, or a single
+// array of documents, such as the files created by "arangoexport". Collection
+// name is the file name without its extension.
+//
+// Top level files under "path" are ignored, only directories are used.
+// Similarly, directories located anywhere besides top-level "path", are also
+// ignored.
+func WithData(path string) Option {
+	return func(o *P) {
+		o.DataPath = path
+	}
+}
@@ -0,0 +1,300 @@
+// Package arangodb includes ArangoDB implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured ArangoDB container to use in tests.
+//
+// The default port serves ArangoDB HTTP API. Clients should authenticate as
+// DefaultUser with the password provided using WithPassword.
+package arangodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/jsondocs"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// DefaultUser is the root user of the container. Its password can be set
+// using WithPassword.
+const DefaultUser = "root"
+
+const (
+	defaultVersion  = "3.10.5"
+	defaultPort     = 8529
+	defaultDatabase = "_system"
+)
+
+func init() {
+	registry.Register("arangodb", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock ArangoDB preset. This preset includes an
+// ArangoDB specific healthcheck function and default ArangoDB image and port,
+// and allows to optionally set up initial state.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for ArangoDB.
+type P struct {
+	Version     string   `json:"version"`
+	Password    string   `json:"password"`
+	DB          string   `json:"db"`
+	Collections []string `json:"collections"`
+	DataPath    string   `json:"data_path"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/library/arangodb:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithEnv("ARANGO_ROOT_PASSWORD=" + p.Password),
+	}
+
+	if p.DB != defaultDatabase || len(p.Collections) > 0 || p.DataPath != "" {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.DB == "" {
+		p.DB = defaultDatabase
+	}
+}
+
+// healthcheck makes sure that the server responds to authenticated requests.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	_, err := p.request(ctx, c, http.MethodGet, "/_api/version", nil)
+
+	return err
+}
+
+// initf creates the database and the collections configured using options,
+// and then loads the data from the data path, if provided.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	if err := p.createDatabase(ctx, c, p.DB); err != nil {
+		return err
+	}
+
+	for _, name := range p.Collections {
+		if err := p.createCollection(ctx, c, p.DB, name); err != nil {
+			return err
+		}
+	}
+
+	if p.DataPath == "" {
+		return nil
+	}
+
+	topLevelDirs, err := os.ReadDir(p.DataPath)
+	if err != nil {
+		return fmt.Errorf("can't read test data path: %w", err)
+	}
+
+	for _, topLevelDir := range topLevelDirs {
+		if !topLevelDir.IsDir() {
+			continue
+		}
+
+		if err := p.setupDB(ctx, c, topLevelDir.Name()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *P) setupDB(ctx context.Context, c *gnomock.Container, db string) error {
+	dataFiles, err := os.ReadDir(path.Join(p.DataPath, db))
+	if err != nil {
+		return fmt.Errorf("can't read test data sub path '%s': %w", db, err)
+	}
+
+	if err := p.createDatabase(ctx, c, db); err != nil {
+		return err
+	}
+
+	for _, dataFile := range dataFiles {
+		if dataFile.IsDir() {
+			continue
+		}
+
+		fName := dataFile.Name()
+
+		if err := p.setupCollection(ctx, c, db, fName); err != nil {
+			return fmt.Errorf("can't setup collection from file '%s': %w", fName, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *P) setupCollection(ctx context.Context, c *gnomock.Container, db, dataFileName string) error {
+	collection := strings.TrimSuffix(dataFileName, path.Ext(dataFileName))
+
+	bs, err := os.ReadFile(path.Join(p.DataPath, db, dataFileName)) // nolint:gosec
+	if err != nil {
+		return fmt.Errorf("can't read file '%s': %w", dataFileName, err)
+	}
+
+	docs, err := jsondocs.Read(bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+
+	if err := p.createCollection(ctx, c, db, collection); err != nil {
+		return err
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(docs)
+	if err != nil {
+		return err
+	}
+
+	resBody, err := p.request(ctx, c, http.MethodPost, dbPath(db, "/_api/document/"+url.PathEscape(collection)), body)
+	if err != nil {
+		return fmt.Errorf("can't insert documents: %w", err)
+	}
+
+	var results []apiError
+
+	if err := json.Unmarshal(resBody, &results); err != nil {
+		return fmt.Errorf("can't decode insert results: %w", err)
+	}
+
+	for i, res := range results {
+		if res.Error {
+			return fmt.Errorf("can't insert document %d of %d: %s", i+1, len(results), res.ErrorMessage)
+		}
+	}
+
+	return nil
+}
+
+// createDatabase creates a database with the provided name, unless it already
+// exists.
+func (p *P) createDatabase(ctx context.Context, c *gnomock.Container, name string) error {
+	if name == defaultDatabase {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.request(ctx, c, http.MethodPost, "/_api/database", body)
+	if err != nil && !errors.Is(err, errConflict) {
+		return fmt.Errorf("can't create database '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// createCollection creates a document collection with the provided name in
+// the provided database, unless it already exists.
+func (p *P) createCollection(ctx context.Context, c *gnomock.Container, db, name string) error {
+	body, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.request(ctx, c, http.MethodPost, dbPath(db, "/_api/collection"), body)
+	if err != nil && !errors.Is(err, errConflict) {
+		return fmt.Errorf("can't create collection '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+var errConflict = errors.New("already exists")
+
+type apiError struct {
+	Error        bool   `json:"error"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// request sends an authenticated request to ArangoDB HTTP API, and returns the
+// response body.
+func (p *P) request(ctx context.Context, c *gnomock.Container, method, path string, body []byte) ([]byte, error) {
+	addr := fmt.Sprintf("http://%s%s", c.DefaultAddress(), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(DefaultUser, p.Password)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		return nil, errConflict
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr apiError
+
+		if err := json.Unmarshal(bs, &apiErr); err == nil && apiErr.ErrorMessage != "" {
+			return nil, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, apiErr.ErrorMessage)
+		}
+
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return bs, nil
+}
+
+// dbPath returns the provided API path in the context of the provided
+// database.
+func dbPath(db, path string) string {
+	return "/_db/" + url.PathEscape(db) + path
+}
@@ -0,0 +1,90 @@
+package arangodb_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/arangodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"3.9.10", "3.10.5"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := arangodb.Preset(
+			arangodb.WithVersion(version),
+			arangodb.WithPassword("secret"),
+			arangodb.WithDatabase("inventory"),
+			arangodb.WithCollections("items", "locations"),
+			arangodb.WithData("./testdata"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		require.Equal(t, 0, count(t, container, "inventory", "items"))
+		require.Equal(t, 0, count(t, container, "inventory", "locations"))
+		require.Equal(t, 2, count(t, container, "shop", "products"))
+		require.Equal(t, 3, count(t, container, "shop", "customers"))
+	}
+}
+
+func TestPreset_wrongDataPath(t *testing.T) {
+	t.Parallel()
+
+	p := arangodb.Preset(arangodb.WithData("./testdata/missing"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read test data path")
+}
+
+// count returns the number of documents in the provided collection using an
+// AQL query.
+func count(t *testing.T, c *gnomock.Container, db, collection string) int {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query":    "RETURN LENGTH(@@collection)",
+		"bindVars": map[string]string{"@collection": collection},
+	})
+	require.NoError(t, err)
+
+	addr := fmt.Sprintf("http://%s/_db/%s/_api/cursor", c.DefaultAddress(), db)
+
+	req, err := http.NewRequest(http.MethodPost, addr, bytes.NewReader(body)) // nolint:noctx
+	require.NoError(t, err)
+
+	req.SetBasicAuth(arangodb.DefaultUser, "secret")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var result struct {
+		Result []int `json:"result"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Result, 1)
+
+	return result.Result[0]
+}
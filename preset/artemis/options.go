@@ -0,0 +1,39 @@
+package artemis
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithUser sets the credentials of the broker user, which are required both
+// by messaging clients and by the management console. If not used, the
+// default credentials are gnomock:gnomick.
+func WithUser(user, password string) Option {
+	return func(o *P) {
+		o.User = user
+		o.Password = password
+	}
+}
+
+// WithAddresses creates addresses with the provided names, supporting both
+// anycast and multicast routing. This option can be used multiple times.
+func WithAddresses(addresses ...string) Option {
+	return func(o *P) {
+		o.Addresses = append(o.Addresses, addresses...)
+	}
+}
+
+// WithQueues creates durable queues once the addresses are created. Missing
+// addresses of the queues are created automatically. This option can be used
+// multiple times.
+func WithQueues(queues ...Queue) Option {
+	return func(o *P) {
+		o.Queues = append(o.Queues, queues...)
+	}
+}
@@ -0,0 +1,288 @@
+// Package artemis includes ActiveMQ Artemis implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured Artemis broker to use in tests.
+//
+// The default port, also available as OpenWirePort, accepts connections using
+// all the supported protocols, including Core and OpenWire. AMQP, STOMP and
+// MQTT clients can also use dedicated named ports, and ConsolePort serves the
+// management console and Jolokia API at "/console/jolokia".
+package artemis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// Named ports exposed by Artemis containers. OpenWirePort is the default
+// port, which accepts all the protocols.
+const (
+	OpenWirePort = gnomock.DefaultPort
+	AMQPPort     = "amqp"
+	STOMPPort    = "stomp"
+	MQTTPort     = "mqtt"
+	ConsolePort  = "console"
+)
+
+const (
+	defaultVersion  = "2.30.0"
+	defaultPort     = 61616
+	amqpPort        = 5672
+	stompPort       = 61613
+	mqttPort        = 1883
+	consolePort     = 8161
+	defaultUser     = "gnomock"
+	defaultPassword = "gnomick"
+	anycast         = "ANYCAST"
+)
+
+func init() {
+	registry.Register("artemis", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Artemis preset. This preset includes an
+// Artemis specific healthcheck function and default Artemis image and ports,
+// and allows to optionally create addresses and queues.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for ActiveMQ Artemis.
+type P struct {
+	Version   string   `json:"version"`
+	User      string   `json:"user"`
+	Password  string   `json:"password"`
+	Addresses []string `json:"addresses"`
+	Queues    []Queue  `json:"queues"`
+}
+
+// Queue is a durable queue created during container initialization.
+type Queue struct {
+	// Name is the name of the queue.
+	Name string `json:"name"`
+
+	// Address is the address the queue is bound to. If not set, an address
+	// with the same name as the queue is used. Missing addresses are created
+	// automatically.
+	Address string `json:"address"`
+
+	// RoutingType is either "ANYCAST" (point-to-point) or "MULTICAST"
+	// (publish-subscribe). If not set, "ANYCAST" is used.
+	RoutingType string `json:"routing_type"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/apache/activemq-artemis:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[AMQPPort] = gnomock.Port{Protocol: "tcp", Port: amqpPort}
+	namedPorts[STOMPPort] = gnomock.Port{Protocol: "tcp", Port: stompPort}
+	namedPorts[MQTTPort] = gnomock.Port{Protocol: "tcp", Port: mqttPort}
+	namedPorts[ConsolePort] = gnomock.Port{Protocol: "tcp", Port: consolePort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithEnv("ARTEMIS_USER=" + p.User),
+		gnomock.WithEnv("ARTEMIS_PASSWORD=" + p.Password),
+		gnomock.WithEnv("ANONYMOUS_LOGIN=false"),
+	}
+
+	if len(p.Addresses) > 0 || len(p.Queues) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.User == "" && p.Password == "" {
+		p.User = defaultUser
+		p.Password = defaultPassword
+	}
+}
+
+// healthcheck makes sure that the broker is started, and that its management
+// API accepts the configured credentials.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	broker, err := p.broker(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	var started bool
+
+	err = p.jolokia(ctx, c, map[string]interface{}{
+		"type":      "read",
+		"mbean":     broker,
+		"attribute": "Started",
+	}, &started)
+	if err != nil {
+		return err
+	}
+
+	if !started {
+		return fmt.Errorf("broker is not started")
+	}
+
+	return nil
+}
+
+// initf creates the configured addresses, and then the queues.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	broker, err := p.broker(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	for _, address := range p.Addresses {
+		err := p.jolokia(ctx, c, map[string]interface{}{
+			"type":      "exec",
+			"mbean":     broker,
+			"operation": "createAddress(java.lang.String,java.lang.String)",
+			"arguments": []string{address, "ANYCAST,MULTICAST"},
+		}, nil)
+
+		// AMQ229204 means that the address exists, for example when an
+		// existing container is reused
+		if err != nil && !strings.Contains(err.Error(), "AMQ229204") {
+			return fmt.Errorf("can't create address '%s': %w", address, err)
+		}
+	}
+
+	for _, q := range p.Queues {
+		if q.Address == "" {
+			q.Address = q.Name
+		}
+
+		if q.RoutingType == "" {
+			q.RoutingType = anycast
+		}
+
+		config, err := json.Marshal(map[string]interface{}{
+			"name":                q.Name,
+			"address":             q.Address,
+			"routing-type":        strings.ToUpper(q.RoutingType),
+			"durable":             true,
+			"auto-create-address": true,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = p.jolokia(ctx, c, map[string]interface{}{
+			"type":      "exec",
+			"mbean":     broker,
+			"operation": "createQueue(java.lang.String)",
+			"arguments": []string{string(config)},
+		}, nil)
+
+		// AMQ229019 means that the queue exists
+		if err != nil && !strings.Contains(err.Error(), "AMQ229019") {
+			return fmt.Errorf("can't create queue '%s': %w", q.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// broker returns the name of the broker MBean, such as
+// org.apache.activemq.artemis:broker="0.0.0.0".
+func (p *P) broker(ctx context.Context, c *gnomock.Container) (string, error) {
+	var brokers []string
+
+	err := p.jolokia(ctx, c, map[string]interface{}{
+		"type":  "search",
+		"mbean": "org.apache.activemq.artemis:broker=*",
+	}, &brokers)
+	if err != nil {
+		return "", err
+	}
+
+	if len(brokers) == 0 {
+		return "", fmt.Errorf("broker not found")
+	}
+
+	return brokers[0], nil
+}
+
+// jolokia sends the provided request to Jolokia API, and decodes the returned
+// value into v, unless it is nil. Jolokia reports errors, such as failed
+// operations, in the response body rather than using response status.
+func (p *P) jolokia(ctx context.Context, c *gnomock.Container, request map[string]interface{}, v interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	addr := fmt.Sprintf("http://%s/console/jolokia", c.Address(ConsolePort))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(p.User, p.Password)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	var res struct {
+		Status int             `json:"status"`
+		Error  string          `json:"error"`
+		Value  json.RawMessage `json:"value"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("can't decode response: %w", err)
+	}
+
+	if res.Status != http.StatusOK {
+		return fmt.Errorf("jolokia request failed (status %d): %s", res.Status, res.Error)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(res.Value, v); err != nil {
+		return fmt.Errorf("can't decode response value: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package artemis_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/artemis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"2.29.0", "2.30.0"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := artemis.Preset(
+			artemis.WithVersion(version),
+			artemis.WithUser("admin", "secret"),
+			artemis.WithAddresses("events"),
+			artemis.WithQueues(
+				artemis.Queue{Name: "orders"},
+				artemis.Queue{Name: "audit", Address: "events", RoutingType: "multicast"},
+			),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		require.Subset(t, names(t, container, "getAddressNames()"), []string{"events", "orders"})
+		require.Subset(t, names(t, container, "getQueueNames()"), []string{"orders", "audit"})
+
+		for _, port := range []string{artemis.OpenWirePort, artemis.AMQPPort, artemis.STOMPPort, artemis.MQTTPort} {
+			conn, err := net.Dial("tcp", container.Address(port))
+			require.NoError(t, err, port)
+			require.NoError(t, conn.Close())
+		}
+	}
+}
+
+// names executes the provided broker operation that returns a list of names.
+func names(t *testing.T, c *gnomock.Container, operation string) []string {
+	t.Helper()
+
+	var search struct {
+		Value []string `json:"value"`
+	}
+
+	jolokia(t, c, map[string]interface{}{
+		"type":  "search",
+		"mbean": "org.apache.activemq.artemis:broker=*",
+	}, &search)
+	require.Len(t, search.Value, 1)
+
+	var exec struct {
+		Value []string `json:"value"`
+	}
+
+	jolokia(t, c, map[string]interface{}{
+		"type":      "exec",
+		"mbean":     search.Value[0],
+		"operation": operation,
+	}, &exec)
+
+	return exec.Value
+}
+
+func jolokia(t *testing.T, c *gnomock.Container, request map[string]interface{}, v interface{}) {
+	t.Helper()
+
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	addr := fmt.Sprintf("http://%s/console/jolokia", c.Address(artemis.ConsolePort))
+
+	req, err := http.NewRequest(http.MethodPost, addr, bytes.NewReader(body)) // nolint:noctx
+	require.NoError(t, err)
+
+	req.SetBasicAuth("admin", "secret")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
@@ -0,0 +1,28 @@
+package azurite
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version, for example "3.23.0".
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithContainers creates empty blob containers with the provided names. This
+// option can be used multiple times.
+func WithContainers(containers ...string) Option {
+	return func(o *P) {
+		o.Containers = append(o.Containers, containers...)
+	}
+}
+
+// WithQueues creates empty queues with the provided names. This option can
+// be used multiple times.
+func WithQueues(queues ...string) Option {
+	return func(o *P) {
+		o.Queues = append(o.Queues, queues...)
+	}
+}
@@ -0,0 +1,156 @@
+// Package azurite includes Azurite implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured Azure Storage emulator to use in tests.
+//
+// The default port, also available as BlobPort, serves Blob service, while
+// QueuePort and TablePort serve Queue and Table services. All the services
+// use the well-known development storage account, AccountName and
+// AccountKey. Use ConnectionString to configure Azure SDK clients.
+package azurite
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// Named ports exposed by Azurite containers. BlobPort is the default port.
+const (
+	BlobPort  = gnomock.DefaultPort
+	QueuePort = "queue"
+	TablePort = "table"
+)
+
+// Well-known development storage account name and key, accepted by Azurite.
+const (
+	AccountName = "devstoreaccount1"
+	AccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+)
+
+const (
+	defaultVersion = "3.23.0"
+	defaultPort    = 10000
+	queuePort      = 10001
+	tablePort      = 10002
+)
+
+func init() {
+	registry.Register("azurite", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Azurite preset. This preset includes an
+// Azurite specific healthcheck function and default Azurite image and ports,
+// and allows to optionally create blob containers and queues.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Azurite.
+type P struct {
+	Version    string   `json:"version"`
+	Containers []string `json:"containers"`
+	Queues     []string `json:"queues"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("mcr.microsoft.com/azure-storage/azurite:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[QueuePort] = gnomock.Port{Protocol: "tcp", Port: queuePort}
+	namedPorts[TablePort] = gnomock.Port{Protocol: "tcp", Port: tablePort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithCommand(
+			"azurite", "--location", "/data",
+			"--blobHost", "0.0.0.0", "--queueHost", "0.0.0.0", "--tableHost", "0.0.0.0",
+		),
+	}
+
+	if len(p.Containers) > 0 || len(p.Queues) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// ConnectionString returns a connection string of the development storage
+// account, which includes the endpoints of all the services in the provided
+// container. It can be used to create Azure SDK clients.
+func ConnectionString(c *gnomock.Container) string {
+	return fmt.Sprintf(
+		"DefaultEndpointsProtocol=http;AccountName=%s;AccountKey=%s;"+
+			"BlobEndpoint=%s;QueueEndpoint=%s;TableEndpoint=%s;",
+		AccountName, AccountKey,
+		endpoint(c, BlobPort), endpoint(c, QueuePort), endpoint(c, TablePort),
+	)
+}
+
+func endpoint(c *gnomock.Container, port string) string {
+	return fmt.Sprintf("http://%s/%s", c.Address(port), AccountName)
+}
+
+// healthcheck makes sure that Blob and Queue services accept authenticated
+// requests, and that Table service accepts connections.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	if _, err := request(ctx, c, BlobPort, http.MethodGet, "", "comp=list"); err != nil {
+		return fmt.Errorf("blob service is not ready: %w", err)
+	}
+
+	if _, err := request(ctx, c, QueuePort, http.MethodGet, "", "comp=list"); err != nil {
+		return fmt.Errorf("queue service is not ready: %w", err)
+	}
+
+	d := net.Dialer{}
+
+	conn, err := d.DialContext(ctx, "tcp", c.Address(TablePort))
+	if err != nil {
+		return fmt.Errorf("table service is not ready: %w", err)
+	}
+
+	return conn.Close()
+}
+
+// initf creates the configured blob containers and queues.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	for _, name := range p.Containers {
+		if err := create(ctx, c, BlobPort, name, "restype=container"); err != nil {
+			return fmt.Errorf("can't create container '%s': %w", name, err)
+		}
+	}
+
+	for _, name := range p.Queues {
+		if err := create(ctx, c, QueuePort, name, ""); err != nil {
+			return fmt.Errorf("can't create queue '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
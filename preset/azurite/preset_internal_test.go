@@ -0,0 +1,30 @@
+package azurite
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringToSign(t *testing.T) {
+	t.Parallel()
+
+	u, err := url.Parse("http://127.0.0.1:10000/devstoreaccount1/images?restype=container&comp=list")
+	require.NoError(t, err)
+
+	header := http.Header{}
+	header.Set("x-ms-version", "2021-08-06")
+	header.Set("x-ms-date", "Mon, 27 Mar 2023 10:00:00 GMT")
+	header.Set("Content-Length", "0")
+
+	expected := "GET\n\n\n\n\n\n\n\n\n\n\n\n" +
+		"x-ms-date:Mon, 27 Mar 2023 10:00:00 GMT\n" +
+		"x-ms-version:2021-08-06\n" +
+		"/devstoreaccount1/devstoreaccount1/images\n" +
+		"comp:list\n" +
+		"restype:container"
+
+	require.Equal(t, expected, stringToSign(http.MethodGet, header, u))
+}
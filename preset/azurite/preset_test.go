@@ -0,0 +1,94 @@
+package azurite_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azqueue"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/azurite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"3.22.0", "3.23.0"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := azurite.Preset(
+			azurite.WithVersion(version),
+			azurite.WithContainers("images", "reports"),
+			azurite.WithQueues("jobs"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		connStr := azurite.ConnectionString(container)
+
+		blobClient, err := azblob.NewClientFromConnectionString(connStr, nil)
+		require.NoError(t, err)
+
+		containers := []string{}
+		containersPager := blobClient.NewListContainersPager(nil)
+
+		for containersPager.More() {
+			page, err := containersPager.NextPage(ctx)
+			require.NoError(t, err)
+
+			for _, item := range page.ContainerItems {
+				containers = append(containers, *item.Name)
+			}
+		}
+
+		require.Equal(t, []string{"images", "reports"}, containers)
+
+		queueClient, err := azqueue.NewServiceClientFromConnectionString(connStr, nil)
+		require.NoError(t, err)
+
+		queues := []string{}
+		queuesPager := queueClient.NewListQueuesPager(nil)
+
+		for queuesPager.More() {
+			page, err := queuesPager.NextPage(ctx)
+			require.NoError(t, err)
+
+			for _, item := range page.Queues {
+				queues = append(queues, *item.Name)
+			}
+		}
+
+		require.Equal(t, []string{"jobs"}, queues)
+
+		conn, err := net.Dial("tcp", container.Address(azurite.TablePort))
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	}
+}
+
+func TestPreset_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	p := azurite.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	client, err := azblob.NewClientFromConnectionString(azurite.ConnectionString(container), nil)
+	require.NoError(t, err)
+
+	_, err = client.CreateContainer(context.Background(), "created", nil)
+	require.NoError(t, err)
+}
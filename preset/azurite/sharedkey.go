@@ -0,0 +1,177 @@
+package azurite
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/orlangure/gnomock"
+)
+
+// apiVersion is the version of Azure Storage REST API used by this preset.
+const apiVersion = "2021-08-06"
+
+var errConflict = errors.New("already exists")
+
+// create sends a request to create a resource with the provided name, such as
+// a blob container or a queue, unless it already exists.
+func create(ctx context.Context, c *gnomock.Container, port, name, query string) error {
+	_, err := request(ctx, c, port, http.MethodPut, name, query)
+	if err != nil && !errors.Is(err, errConflict) {
+		return err
+	}
+
+	return nil
+}
+
+// request sends a request authorized using Shared Key scheme to the service
+// listening on the provided port, and returns the response body. The
+// resource is a path relative to the development storage account, for
+// example a container name.
+func request(ctx context.Context, c *gnomock.Container, port, method, resource, query string) ([]byte, error) {
+	addr := fmt.Sprintf("%s/%s", endpoint(c, port), resource)
+	if query != "" {
+		addr += "?" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", apiVersion)
+
+	signature, err := sign(stringToSign(req.Method, req.Header, req.URL))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", AccountName, signature))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		return nil, errConflict
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+
+		if err := xml.Unmarshal(bs, &apiErr); err == nil && apiErr.Code != "" {
+			return nil, fmt.Errorf("unexpected response status %d: %s: %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+		}
+
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return bs, nil
+}
+
+// sign returns base64 encoded HMAC-SHA256 signature of the provided string,
+// computed using the development storage account key.
+func sign(s string) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(AccountKey)
+	if err != nil {
+		return "", fmt.Errorf("can't decode account key: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(s))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// stringToSign returns a string to sign for Blob and Queue services requests.
+// Requests to the emulator include the account name in their path, so it
+// appears twice in the canonicalized resource.
+func stringToSign(method string, header http.Header, u *url.URL) string {
+	contentLength := header.Get("Content-Length")
+	if contentLength == "0" {
+		contentLength = ""
+	}
+
+	lines := []string{
+		method,
+		header.Get("Content-Encoding"),
+		header.Get("Content-Language"),
+		contentLength,
+		header.Get("Content-MD5"),
+		header.Get("Content-Type"),
+		header.Get("Date"),
+		header.Get("If-Modified-Since"),
+		header.Get("If-Match"),
+		header.Get("If-None-Match"),
+		header.Get("If-Unmodified-Since"),
+		header.Get("Range"),
+	}
+
+	return strings.Join(lines, "\n") + "\n" + canonicalizedHeaders(header) + canonicalizedResource(u)
+}
+
+func canonicalizedHeaders(header http.Header) string {
+	names := []string{}
+
+	for name := range header {
+		if name = strings.ToLower(name); strings.HasPrefix(name, "x-ms-") {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var sb strings.Builder
+
+	for _, name := range names {
+		sb.WriteString(name + ":" + strings.TrimSpace(header.Get(name)) + "\n")
+	}
+
+	return sb.String()
+}
+
+func canonicalizedResource(u *url.URL) string {
+	var sb strings.Builder
+
+	sb.WriteString("/" + AccountName + u.EscapedPath())
+
+	query := u.Query()
+	params := make([]string, 0, len(query))
+
+	for param := range query {
+		params = append(params, param)
+	}
+
+	sort.Strings(params)
+
+	for _, param := range params {
+		values := query[param]
+		sort.Strings(values)
+
+		sb.WriteString("\n" + strings.ToLower(param) + ":" + strings.Join(values, ","))
+	}
+
+	return sb.String()
+}
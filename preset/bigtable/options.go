@@ -0,0 +1,41 @@
+package bigtable
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets the version of Google Cloud SDK image, for example
+// "424.0.0". The emulators variant of the image is used.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithProjectID sets the project of the tables created during init. If not
+// used, DefaultProjectID is used.
+func WithProjectID(projectID string) Option {
+	return func(o *P) {
+		o.ProjectID = projectID
+	}
+}
+
+// WithInstanceID sets the instance of the tables created during init. If not
+// used, DefaultInstanceID is used.
+func WithInstanceID(instanceID string) Option {
+	return func(o *P) {
+		o.InstanceID = instanceID
+	}
+}
+
+// WithTable creates a table with the provided name and column families. This
+// option can be used multiple times, with the same or different tables.
+func WithTable(name string, families ...string) Option {
+	return func(o *P) {
+		if o.Tables == nil {
+			o.Tables = make(map[string][]string)
+		}
+
+		o.Tables[name] = append(o.Tables[name], families...)
+	}
+}
@@ -0,0 +1,182 @@
+// Package bigtable includes Google Cloud Bigtable emulator implementation of
+// Gnomock Preset interface. This Preset can be passed to gnomock.Start()
+// function to create a configured Bigtable emulator container to use in
+// tests.
+//
+// The emulator accepts any project and instance names, but tables created
+// during init are only visible to clients using the configured ones.
+package bigtable
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"cloud.google.com/go/bigtable"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// EmulatorHostEnv is honored by both bigtable.NewClient and
+// bigtable.NewAdminClient: when it is set to container.DefaultAddress(), data
+// and table administration requests go to the emulator.
+const EmulatorHostEnv = "BIGTABLE_EMULATOR_HOST"
+
+// Default project and instance of the tables created during init, unless
+// different values are configured using WithProjectID and WithInstanceID.
+const (
+	DefaultProjectID  = "gnomock"
+	DefaultInstanceID = "gnomock"
+)
+
+const (
+	defaultVersion = "424.0.0"
+	defaultPort    = 8086
+)
+
+func init() {
+	registry.Register("bigtable", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Bigtable preset. This preset includes a
+// Bigtable specific healthcheck function and default Bigtable emulator image
+// and port, and allows to optionally create tables and column families.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Bigtable emulator.
+type P struct {
+	Version    string              `json:"version"`
+	ProjectID  string              `json:"project_id"`
+	InstanceID string              `json:"instance_id"`
+	Tables     map[string][]string `json:"tables"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("gcr.io/google.com/cloudsdktool/cloud-sdk:%s-emulators", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithCommand(
+			"gcloud", "beta", "emulators", "bigtable", "start",
+			fmt.Sprintf("--host-port=0.0.0.0:%d", defaultPort),
+		),
+	}
+
+	if len(p.Tables) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.ProjectID == "" {
+		p.ProjectID = DefaultProjectID
+	}
+
+	if p.InstanceID == "" {
+		p.InstanceID = DefaultInstanceID
+	}
+}
+
+// healthcheck makes sure that the emulator port serves admin requests.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) (err error) {
+	client, err := p.adminClient(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		closeErr := client.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	if _, err := client.Tables(ctx); err != nil {
+		return fmt.Errorf("can't list tables: %w", err)
+	}
+
+	return nil
+}
+
+// initf creates the configured tables and their column families in sorted
+// order.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) (err error) {
+	client, err := p.adminClient(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		closeErr := client.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	tables := make([]string, 0, len(p.Tables))
+	for table := range p.Tables {
+		tables = append(tables, table)
+	}
+
+	sort.Strings(tables)
+
+	for _, table := range tables {
+		err := client.CreateTable(ctx, table)
+		if err != nil && status.Code(err) != codes.AlreadyExists {
+			return fmt.Errorf("can't create table '%s': %w", table, err)
+		}
+
+		for _, family := range p.Tables[table] {
+			err := client.CreateColumnFamily(ctx, table, family)
+			if err != nil && status.Code(err) != codes.AlreadyExists {
+				return fmt.Errorf("can't create column family '%s' in table '%s': %w", family, table, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *P) adminClient(ctx context.Context, c *gnomock.Container) (*bigtable.AdminClient, error) {
+	client, err := bigtable.NewAdminClient(
+		ctx, p.ProjectID, p.InstanceID,
+		option.WithEndpoint(c.DefaultAddress()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't create bigtable admin client: %w", err)
+	}
+
+	return client, nil
+}
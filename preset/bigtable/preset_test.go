@@ -0,0 +1,101 @@
+package bigtable_test
+
+import (
+	"context"
+	"testing"
+
+	gbigtable "cloud.google.com/go/bigtable"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/bigtable"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"423.0.0", "424.0.0"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := bigtable.Preset(
+			bigtable.WithVersion(version),
+			bigtable.WithProjectID("project"),
+			bigtable.WithInstanceID("instance"),
+			bigtable.WithTable("users", "profile", "stats"),
+			bigtable.WithTable("users", "profile"),
+			bigtable.WithTable("events"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		ctx := context.Background()
+
+		admin, err := gbigtable.NewAdminClient(ctx, "project", "instance", clientOptions(container)...)
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, admin.Close()) }()
+
+		tables, err := admin.Tables(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"events", "users"}, tables)
+
+		info, err := admin.TableInfo(ctx, "users")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"profile", "stats"}, info.Families)
+
+		client, err := gbigtable.NewClient(ctx, "project", "instance", clientOptions(container)...)
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, client.Close()) }()
+
+		table := client.Open("users")
+
+		mut := gbigtable.NewMutation()
+		mut.Set("profile", "name", gbigtable.Now(), []byte("Alice"))
+		require.NoError(t, table.Apply(ctx, "alice", mut))
+
+		row, err := table.ReadRow(ctx, "alice")
+		require.NoError(t, err)
+		require.Len(t, row["profile"], 1)
+		require.Equal(t, "Alice", string(row["profile"][0].Value))
+	}
+}
+
+func TestPreset_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	p := bigtable.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	admin, err := gbigtable.NewAdminClient(
+		ctx, bigtable.DefaultProjectID, bigtable.DefaultInstanceID, clientOptions(container)...,
+	)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, admin.Close()) }()
+
+	require.NoError(t, admin.CreateTable(ctx, "created"))
+}
+
+func clientOptions(c *gnomock.Container) []option.ClientOption {
+	return []option.ClientOption{
+		option.WithEndpoint(c.DefaultAddress()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	}
+}
@@ -10,3 +10,32 @@ func WithVersion(version string) Option {
 		o.Version = version
 	}
 }
+
+// WithKeyspace creates a keyspace with the provided name during initial
+// setup. The keyspace uses SimpleStrategy with replication factor 1. Queries
+// provided using WithCQLQueries and WithCQLFile are executed in this
+// keyspace.
+func WithKeyspace(name string) Option {
+	return func(o *P) {
+		o.Keyspace = name
+	}
+}
+
+// WithCQLQueries executes the provided CQL statements during initial setup,
+// after the statements from files provided using WithCQLFile. Every query
+// should be a single statement. This option can be used multiple times.
+func WithCQLQueries(queries ...string) Option {
+	return func(o *P) {
+		o.Queries = append(o.Queries, queries...)
+	}
+}
+
+// WithCQLFile sets a file name to read initial CQL statements from. The file
+// may include multiple statements separated by semicolons, and comments
+// starting with "--" or "//" on separate lines. This option can be used
+// multiple times, and the files are executed in the same order.
+func WithCQLFile(file string) Option {
+	return func(o *P) {
+		o.QueriesFiles = append(o.QueriesFiles, file)
+	}
+}
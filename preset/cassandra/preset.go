@@ -12,6 +12,7 @@ import (
 
 	"github.com/gocql/gocql"
 	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/cql"
 	"github.com/orlangure/gnomock/internal/registry"
 )
 
@@ -30,7 +31,7 @@ func init() {
 
 // Preset creates a new Gmomock Cassandra preset. This preset includes a
 // Cassandra specific healthcheck function and default Cassandra image and
-// port.
+// port, and allows to optionally set up initial state.
 //
 // Containers created using this preset should be accessed using
 // cassandra/cassandra username/password pair.
@@ -46,7 +47,10 @@ func Preset(opts ...Option) gnomock.Preset {
 
 // P is a Gnomock Preset implementation for Cassandra.
 type P struct {
-	Version string `json:"version"`
+	Version      string   `json:"version"`
+	Keyspace     string   `json:"keyspace"`
+	Queries      []string `json:"queries"`
+	QueriesFiles []string `json:"queries_files"`
 }
 
 // Image returns an image that should be pulled to create this container.
@@ -67,6 +71,10 @@ func (p *P) Options() []gnomock.Option {
 		gnomock.WithHealthCheck(p.healthcheck),
 	}
 
+	if p.Keyspace != "" || len(p.Queries) > 0 || len(p.QueriesFiles) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
 	return opts
 }
 
@@ -77,18 +85,19 @@ func (p *P) setDefaults() {
 }
 
 func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
-	cluster := gocql.NewCluster(c.DefaultAddress())
-	cluster.Authenticator = gocql.PasswordAuthenticator{
-		Username: DefaultUser,
-		Password: DefaultPassword,
-	}
-
-	session, err := cluster.CreateSession()
-	if err != nil {
-		return fmt.Errorf("failed to create a new session: %w", err)
-	}
+	return node(c).Healthcheck(ctx)
+}
 
-	session.Close()
+func (p *P) initf(_ context.Context, c *gnomock.Container) error {
+	return node(c).Init(p.Keyspace, p.QueriesFiles, p.Queries)
+}
 
-	return nil
+func node(c *gnomock.Container) cql.Node {
+	return cql.Node{
+		Addr: c.DefaultAddress(),
+		Authenticator: gocql.PasswordAuthenticator{
+			Username: DefaultUser,
+			Password: DefaultPassword,
+		},
+	}
 }
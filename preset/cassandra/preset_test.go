@@ -52,3 +52,48 @@ func testPreset(version string) func(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestPreset_withKeyspace(t *testing.T) {
+	t.Parallel()
+
+	p := cassandra.Preset(
+		cassandra.WithKeyspace("gnomock"),
+		cassandra.WithCQLFile("./testdata/schema.cql"),
+		cassandra.WithCQLQueries("insert into users (id, name) values (3, 'baz')"),
+	)
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	cluster := gocql.NewCluster(container.DefaultAddress())
+	cluster.Keyspace = "gnomock"
+
+	session, err := cluster.CreateSession()
+	require.NoError(t, err)
+
+	defer session.Close()
+
+	var count int
+
+	require.NoError(t, session.Query("select count(*) from users").Scan(&count))
+	require.Equal(t, 3, count)
+
+	var name string
+
+	require.NoError(t, session.Query("select name from users where id = 2").Scan(&name))
+	require.Equal(t, "bar", name)
+}
+
+func TestPreset_wrongCQLFile(t *testing.T) {
+	t.Parallel()
+
+	p := cassandra.Preset(cassandra.WithCQLFile("./testdata/missing.cql"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read cql file")
+}
@@ -0,0 +1,49 @@
+package clickhouse
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithUser creates a new user with the provided credentials in the container.
+// If not used, the default credentials are gnomock:gnomick.
+func WithUser(user, password string) Option {
+	return func(o *P) {
+		o.User = user
+		o.Password = password
+	}
+}
+
+// WithDatabase creates a database with the provided name in the container. If
+// not provided, "default" database is used. WithQueries, if provided, runs
+// against this database.
+func WithDatabase(db string) Option {
+	return func(o *P) {
+		o.DB = db
+	}
+}
+
+// WithQueries executes the provided queries against the database created with
+// WithDatabase, or against "default" database. ClickHouse only accepts a
+// single statement per query, so every query should be a single statement.
+func WithQueries(queries ...string) Option {
+	return func(o *P) {
+		o.Queries = append(o.Queries, queries...)
+	}
+}
+
+// WithQueriesFile sets a file name to read initial queries from. Queries from
+// this file are executed before any other queries provided in WithQueries.
+// Every file is sent as a single statement. This option can be used multiple
+// times, and the files are executed in the same order.
+func WithQueriesFile(file string) Option {
+	return func(o *P) {
+		o.QueriesFiles = append(o.QueriesFiles, file)
+	}
+}
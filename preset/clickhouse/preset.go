@@ -0,0 +1,177 @@
+// Package clickhouse includes ClickHouse implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured ClickHouse container to use in tests.
+//
+// ClickHouse containers expose both native protocol port, which is the
+// default port, and HTTP interface port, available as HTTPPort.
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+)
+
+// HTTPPort is a name of the port exposed by ClickHouse HTTP interface. Use it
+// with container.Address to send queries over HTTP.
+const HTTPPort = "http"
+
+const (
+	defaultUser     = "gnomock"
+	defaultPassword = "gnomick"
+	defaultDatabase = "default"
+	defaultVersion  = "23.3"
+	defaultPort     = 9000
+	httpPort        = 8123
+)
+
+func init() {
+	registry.Register("clickhouse", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock ClickHouse preset. This preset includes a
+// ClickHouse specific healthcheck function and default ClickHouse image and
+// ports, and allows to optionally set up initial state.
+//
+// When used without specifying username/password, default ones are used:
+// gnomock/gnomick.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for ClickHouse.
+type P struct {
+	Version      string   `json:"version"`
+	User         string   `json:"user"`
+	Password     string   `json:"password"`
+	DB           string   `json:"db"`
+	Queries      []string `json:"queries"`
+	QueriesFiles []string `json:"queries_files"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/clickhouse/clickhouse-server:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[HTTPPort] = gnomock.Port{Protocol: "tcp", Port: httpPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithEnv("CLICKHOUSE_USER=" + p.User),
+		gnomock.WithEnv("CLICKHOUSE_PASSWORD=" + p.Password),
+		gnomock.WithEnv("CLICKHOUSE_DB=" + p.DB),
+	}
+
+	if len(p.Queries) > 0 || len(p.QueriesFiles) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.User == "" && p.Password == "" {
+		p.User = defaultUser
+		p.Password = defaultPassword
+	}
+
+	if p.DB == "" {
+		p.DB = defaultDatabase
+	}
+}
+
+// healthcheck makes sure the server answers queries over HTTP interface using
+// the configured credentials.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	out, err := p.query(ctx, c, `SELECT 1`)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(out) != "1" {
+		return fmt.Errorf("unexpected healthcheck response: %s", out)
+	}
+
+	return nil
+}
+
+// initf executes queries from files and the rest of the queries, in this
+// order, against the configured database.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	setup := sqlsetup.Setup{Queries: p.Queries, QueriesFiles: p.QueriesFiles}
+
+	queries, err := setup.AllQueries()
+	if err != nil {
+		return err
+	}
+
+	for i, q := range queries {
+		if _, err := p.query(ctx, c, q); err != nil {
+			return fmt.Errorf("query %d of %d failed (%s): %w", i+1, len(queries), sqlsetup.Snippet(q), err)
+		}
+	}
+
+	return nil
+}
+
+// query sends the provided query to HTTP interface of the server running in
+// the provided container, and returns the response body.
+func (p *P) query(ctx context.Context, c *gnomock.Container, q string) (string, error) {
+	params := url.Values{"database": []string{p.DB}}
+	addr := fmt.Sprintf("http://%s/?%s", c.Address(HTTPPort), params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, strings.NewReader(q))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("X-ClickHouse-User", p.User)
+	req.Header.Set("X-ClickHouse-Key", p.Password)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New(strings.TrimSpace(string(body)))
+	}
+
+	return string(body), nil
+}
@@ -0,0 +1,94 @@
+package clickhouse_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/clickhouse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"22.8", "23.3"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := clickhouse.Preset(
+			clickhouse.WithVersion(version),
+			clickhouse.WithUser("user", "secret"),
+			clickhouse.WithDatabase("gnomock"),
+			clickhouse.WithQueriesFile("./testdata/queries.sql"),
+			clickhouse.WithQueries(
+				"insert into events values (1, 'foo'), (2, 'bar')",
+				"insert into events values (3, 'baz')",
+			),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+		require.NotEmpty(t, container.DefaultAddress())
+
+		out := query(t, container, "user", "secret", "select count(*) from gnomock.events")
+		require.Equal(t, "3", out)
+
+		out = query(t, container, "user", "secret", "select name from gnomock.events where id = 2")
+		require.Equal(t, "bar", out)
+	}
+}
+
+func TestPreset_wrongQueriesFile(t *testing.T) {
+	t.Parallel()
+
+	p := clickhouse.Preset(clickhouse.WithQueriesFile("./testdata/missing.sql"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read queries file")
+}
+
+func TestPreset_defaultCredentials(t *testing.T) {
+	t.Parallel()
+
+	container, err := gnomock.Start(clickhouse.Preset())
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+	require.Equal(t, "1", query(t, container, "gnomock", "gnomick", "select 1"))
+}
+
+func query(t *testing.T, c *gnomock.Container, user, password, q string) string {
+	t.Helper()
+
+	addr := fmt.Sprintf("http://%s/?%s", c.Address(clickhouse.HTTPPort), url.Values{"query": []string{q}}.Encode())
+
+	req, err := http.NewRequest(http.MethodGet, addr, nil)
+	require.NoError(t, err)
+
+	req.SetBasicAuth(user, password)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1 @@
+create table events (id UInt32, name String) engine = MergeTree order by id
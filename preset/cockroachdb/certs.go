@@ -0,0 +1,122 @@
+package cockroachdb
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+const certsDir = "/gnomock/certs"
+
+// certsSetup writes the certificates generated for secure mode from
+// GNOMOCK_CA_CRT, GNOMOCK_NODE_CRT and GNOMOCK_NODE_KEY variables into the
+// certificates directory. The node refuses to use a key readable by others.
+const certsSetup = `mkdir -p ` + certsDir + `
+printf '%s\n' "$GNOMOCK_CA_CRT" > ` + certsDir + `/ca.crt
+printf '%s\n' "$GNOMOCK_NODE_CRT" > ` + certsDir + `/node.crt
+(umask 077 && printf '%s\n' "$GNOMOCK_NODE_KEY" > ` + certsDir + `/node.key)
+`
+
+// entrypoint starts the node as usual, with the command passed as arguments,
+// once the certificates are in place.
+const entrypoint = `exec /cockroach/cockroach.sh "$0" "$@"`
+
+// certs includes PEM encoded certificates and keys used in secure mode: the
+// node certificate is used by the server, and the client certificate allows
+// the preset to connect as root, which can't authenticate using a password.
+type certs struct {
+	caCert     []byte
+	nodeCert   []byte
+	nodeKey    []byte
+	clientCert []byte
+	clientKey  []byte
+}
+
+// generateCerts creates a new certificate authority valid for one day, and
+// uses it to sign a node certificate and root client certificate.
+func generateCerts() (*certs, error) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("can't generate ca key: %w", err)
+	}
+
+	caTemplate := certTemplate("gnomock ca")
+	caTemplate.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	caTemplate.BasicConstraintsValid = true
+	caTemplate.IsCA = true
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("can't create ca certificate: %w", err)
+	}
+
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse ca certificate: %w", err)
+	}
+
+	// node certificate is also used by the node to connect to itself
+	nodeTemplate := certTemplate("node")
+	nodeTemplate.DNSNames = []string{"localhost", "node"}
+	nodeTemplate.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	nodeTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+
+	nodeCert, nodeKey, err := signCert(nodeTemplate, ca, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("can't create node certificate: %w", err)
+	}
+
+	clientTemplate := certTemplate(rootUser)
+	clientTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+
+	clientCert, clientKey, err := signCert(clientTemplate, ca, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("can't create client certificate: %w", err)
+	}
+
+	return &certs{
+		caCert:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		nodeCert:   nodeCert,
+		nodeKey:    nodeKey,
+		clientCert: clientCert,
+		clientKey:  clientKey,
+	}, nil
+}
+
+func certTemplate(commonName string) *x509.Certificate {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		serial = big.NewInt(time.Now().UnixNano())
+	}
+
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"Cockroach"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour * 24),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	}
+}
+
+func signCert(template, ca *x509.Certificate, caKey *rsa.PrivateKey) (certPEM, keyPEM []byte, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	return certPEM, keyPEM, nil
+}
@@ -34,3 +34,28 @@ func WithQueriesFile(file string) Option {
 		p.QueriesFiles = append(p.QueriesFiles, file)
 	}
 }
+
+// WithUser creates a new user with the provided credentials during initial
+// setup, and grants it all privileges on the database created with
+// WithDatabase. Queries provided using WithQueries and WithQueriesFile are
+// executed as this user.
+//
+// CockroachDB only supports passwords in secure mode, so the password is
+// ignored unless WithSecureMode is used. In insecure mode, the user can
+// connect without a password.
+func WithUser(user, password string) Option {
+	return func(p *P) {
+		p.User = user
+		p.Password = password
+	}
+}
+
+// WithSecureMode starts the node in secure mode, using certificates generated
+// for this container. Clients must connect over TLS, and authenticate using a
+// password. Since root user can't use a password, another user is created,
+// either the one provided using WithUser, or gnomock:gnomick by default.
+func WithSecureMode() Option {
+	return func(p *P) {
+		p.Secure = true
+	}
+}
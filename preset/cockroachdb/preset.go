@@ -2,9 +2,12 @@
 // This Preset can be passed to gnomock.Start() function to create a configured
 // CockroachDB container to use in tests.
 //
-// Containers created with this preset use `root` user without a password for
-// authentication. There is currently no way to setup an initial user at create
-// time.
+// By default, containers created with this preset run a single node in
+// insecure mode, and use `root` user without a password for authentication.
+// WithUser creates another user during initial setup, and WithSecureMode
+// starts the node with generated certificates, so that connections must use
+// TLS, and users must authenticate with a password. Use ConnString to get a
+// connection string that works in either mode.
 //
 // By default, a new database "mydb" is created, and all the provided queries
 // are executed against it.
@@ -13,18 +16,25 @@ package cockroachdb
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"os"
+	"strings"
 
-	_ "github.com/lib/pq" // postgres driver
+	"github.com/lib/pq"
 	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/failinit"
 	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
 )
 
 const (
 	defaultVersion  = "v20.1.10"
 	defaultPort     = 26257
 	defaultDatabase = "mydb"
+	defaultUser     = "gnomock"
+	defaultPassword = "gnomick"
+
+	rootUser = "root"
 )
 
 func init() {
@@ -47,8 +57,13 @@ func Preset(opts ...Option) gnomock.Preset {
 type P struct {
 	Version      string   `json:"version"`
 	DB           string   `json:"db"`
+	User         string   `json:"user"`
+	Password     string   `json:"password"`
+	Secure       bool     `json:"secure"`
 	Queries      []string `json:"queries"`
 	QueriesFiles []string `json:"queries_files"`
+
+	certs *certs
 }
 
 // Image returns an image that should be pulled to create this container.
@@ -65,13 +80,34 @@ func (p *P) Ports() gnomock.NamedPorts {
 func (p *P) Options() []gnomock.Option {
 	p.setDefaults()
 
+	if !p.Secure {
+		return []gnomock.Option{
+			gnomock.WithHealthCheck(p.healthcheck),
+			gnomock.WithInit(p.initf()),
+			gnomock.WithCommand("start-single-node", "--insecure"),
+		}
+	}
+
+	certs, err := generateCerts()
+	if err != nil {
+		return []gnomock.Option{gnomock.WithInit(failinit.Func(err))}
+	}
+
+	p.certs = certs
+
 	opts := []gnomock.Option{
-		gnomock.WithHealthCheck(healthcheck),
-		gnomock.WithCommand("start-single-node", "--insecure"),
+		gnomock.WithHealthCheck(p.healthcheck),
 		gnomock.WithInit(p.initf()),
 	}
 
-	return opts
+	return append(
+		opts,
+		gnomock.WithEnv("GNOMOCK_CA_CRT="+string(p.certs.caCert)),
+		gnomock.WithEnv("GNOMOCK_NODE_CRT="+string(p.certs.nodeCert)),
+		gnomock.WithEnv("GNOMOCK_NODE_KEY="+string(p.certs.nodeKey)),
+		gnomock.WithEntrypoint("/bin/sh", "-c", certsSetup+entrypoint),
+		gnomock.WithCommand("start-single-node", "--certs-dir="+certsDir),
+	)
 }
 
 func (p *P) setDefaults() {
@@ -82,10 +118,15 @@ func (p *P) setDefaults() {
 	if p.DB == "" {
 		p.DB = defaultDatabase
 	}
+
+	// root can't use a password, so secure mode requires another user
+	if p.Secure && p.User == "" {
+		p.User, p.Password = defaultUser, defaultPassword
+	}
 }
 
-func healthcheck(ctx context.Context, c *gnomock.Container) error {
-	db, err := connect(c, "")
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	db, err := p.connect(c, "")
 	if err != nil {
 		return err
 	}
@@ -99,55 +140,134 @@ func healthcheck(ctx context.Context, c *gnomock.Container) error {
 	return db.QueryRow(`select 1`).Scan(&one)
 }
 
+// initf creates the database and the user, if it is configured, and executes
+// the queries as this user, so that the user owns the objects they create.
 func (p *P) initf() gnomock.InitFunc {
 	return func(ctx context.Context, c *gnomock.Container) error {
-		db, err := connect(c, "")
+		setup := sqlsetup.Setup{Queries: p.Queries, QueriesFiles: p.QueriesFiles}
+
+		queries, err := setup.AllQueries()
 		if err != nil {
 			return err
 		}
 
-		_, err = db.Exec("create database " + p.DB)
+		db, err := p.connect(c, "")
 		if err != nil {
 			return err
 		}
 
+		err = sqlsetup.Execute(ctx, db, p.setupQueries())
+
 		_ = db.Close()
 
-		db, err = connect(c, p.DB)
+		if err != nil {
+			return err
+		}
+
+		if p.User != "" {
+			db, err = sql.Open("postgres", p.connString(c))
+		} else {
+			db, err = p.connect(c, p.DB)
+		}
+
 		if err != nil {
 			return err
 		}
 
 		defer func() { _ = db.Close() }()
 
-		if len(p.QueriesFiles) > 0 {
-			for _, f := range p.QueriesFiles {
-				bs, err := os.ReadFile(f) // nolint:gosec
-				if err != nil {
-					return fmt.Errorf("can't read queries file '%s': %w", f, err)
-				}
+		return sqlsetup.Execute(ctx, db, queries)
+	}
+}
+
+// setupQueries returns queries that create the database and the user.
+// Passwords are only supported in secure mode.
+func (p *P) setupQueries() []string {
+	db := pq.QuoteIdentifier(p.DB)
+	queries := []string{"create database if not exists " + db}
 
-				p.Queries = append([]string{string(bs)}, p.Queries...)
-			}
-		}
+	if p.User == "" {
+		return queries
+	}
 
-		for _, q := range p.Queries {
-			_, err = db.Exec(q)
-			if err != nil {
-				return err
-			}
-		}
+	user := pq.QuoteIdentifier(p.User)
+	createUser := "create user if not exists " + user
+
+	if p.Secure && p.Password != "" {
+		createUser += " with password " + pq.QuoteLiteral(p.Password)
+	}
 
-		return nil
+	return append(queries, createUser, fmt.Sprintf("grant all on database %s to %s", db, user))
+}
+
+// ConnString returns a connection string that can be used to connect to the
+// database created in the provided container. Use the same options that were
+// used to create the preset, so that the connection string includes the right
+// database name, credentials and TLS mode. Without WithUser, root user is
+// used, which is only possible in insecure mode.
+//
+// In secure mode, the connection uses TLS without verifying the generated
+// server certificate.
+func ConnString(c *gnomock.Container, opts ...Option) string {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.setDefaults()
+
+	return p.connString(c)
+}
+
+func (p *P) connString(c *gnomock.Container) string {
+	params := []string{
+		fmt.Sprintf("host=%s", c.Host),
+		fmt.Sprintf("port=%d", c.Port(gnomock.DefaultPort)),
+		"dbname=" + quoteParam(p.DB),
+	}
+
+	user := p.User
+	if user == "" {
+		user = rootUser
 	}
+
+	params = append(params, "user="+quoteParam(user))
+
+	if p.Secure {
+		params = append(params, "sslmode=require")
+
+		if p.Password != "" {
+			params = append(params, "password="+quoteParam(p.Password))
+		}
+	} else {
+		params = append(params, "sslmode=disable")
+	}
+
+	return strings.Join(params, " ")
 }
 
-func connect(c *gnomock.Container, db string) (*sql.DB, error) {
+// connect connects to the provided database as root. In secure mode, root
+// authenticates using the generated client certificate.
+func (p *P) connect(c *gnomock.Container, db string) (*sql.DB, error) {
 	connStr := fmt.Sprintf(
-		"host=%s port=%d sslmode=disable user=root dbname=%s",
-		c.Host, c.Port(gnomock.DefaultPort), db,
+		"host=%s port=%d user=%s dbname=%s",
+		c.Host, c.Port(gnomock.DefaultPort), rootUser, quoteParam(db),
 	)
 
+	if p.Secure {
+		if p.certs == nil {
+			return nil, errors.New("secure mode certificates are missing")
+		}
+
+		connStr += fmt.Sprintf(
+			" sslmode=require sslinline=true sslcert=%s sslkey=%s",
+			quoteParam(string(p.certs.clientCert)), quoteParam(string(p.certs.clientKey)),
+		)
+	} else {
+		connStr += " sslmode=disable"
+	}
+
 	conn, err := sql.Open("postgres", connStr)
 	if err != nil {
 		return nil, err
@@ -155,3 +275,11 @@ func connect(c *gnomock.Container, db string) (*sql.DB, error) {
 
 	return conn, conn.Ping()
 }
+
+// quoteParam quotes a connection string parameter value, so that it can
+// include spaces, quotes and backslashes.
+func quoteParam(v string) string {
+	v = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v)
+
+	return "'" + v + "'"
+}
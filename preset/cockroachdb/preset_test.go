@@ -87,3 +87,96 @@ func TestPreset_wrongQueriesFile(t *testing.T) {
 	require.Contains(t, err.Error(), "can't read queries file")
 	require.NoError(t, gnomock.Stop(c))
 }
+
+func TestPreset_withUser(t *testing.T) {
+	t.Parallel()
+
+	opts := []cockroachdb.Option{
+		cockroachdb.WithDatabase("gnomock"),
+		cockroachdb.WithUser("gnomock_user", ""),
+		cockroachdb.WithQueriesFile("./testdata/queries.sql"),
+		cockroachdb.WithQueries("insert into t (a) values (1)"),
+	}
+
+	container, err := gnomock.Start(cockroachdb.Preset(opts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", cockroachdb.ConnString(container, opts...))
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, db.Close()) }()
+
+	var count int
+
+	require.NoError(t, db.QueryRow("select count(*) from t").Scan(&count))
+	require.Equal(t, 1, count)
+}
+
+func TestPreset_withSecureMode(t *testing.T) {
+	t.Parallel()
+
+	opts := []cockroachdb.Option{
+		cockroachdb.WithSecureMode(),
+		cockroachdb.WithDatabase("gnomock"),
+		cockroachdb.WithUser("gnomock_user", "p@ss w0rd"),
+		cockroachdb.WithQueries("create table t (a int)", "insert into t (a) values (1)"),
+	}
+
+	container, err := gnomock.Start(cockroachdb.Preset(opts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", cockroachdb.ConnString(container, opts...))
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, db.Close()) }()
+
+	var count int
+
+	require.NoError(t, db.QueryRow("select count(*) from t").Scan(&count))
+	require.Equal(t, 1, count)
+
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=root dbname=gnomock sslmode=disable",
+		container.Host, container.DefaultPort(),
+	)
+
+	insecureDB, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, insecureDB.Close()) }()
+
+	require.Error(t, insecureDB.Ping())
+}
+
+func TestConnString(t *testing.T) {
+	t.Parallel()
+
+	c := &gnomock.Container{
+		Host:  "127.0.0.1",
+		Ports: gnomock.DefaultTCP(26257),
+	}
+
+	require.Equal(
+		t,
+		"host=127.0.0.1 port=26257 dbname='mydb' user='root' sslmode=disable",
+		cockroachdb.ConnString(c),
+	)
+
+	require.Equal(
+		t,
+		"host=127.0.0.1 port=26257 dbname='gnomock' user='gnomock' sslmode=require password='gnomick'",
+		cockroachdb.ConnString(c, cockroachdb.WithSecureMode(), cockroachdb.WithDatabase("gnomock")),
+	)
+
+	require.Equal(
+		t,
+		`host=127.0.0.1 port=26257 dbname='mydb' user='u' sslmode=require password='it\'s'`,
+		cockroachdb.ConnString(c, cockroachdb.WithSecureMode(), cockroachdb.WithUser("u", "it's")),
+	)
+}
@@ -0,0 +1,36 @@
+package consul
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithKV puts the provided KV entries once the container is ready. Keys are
+// paths such as "config/app/name"; a leading slash is ignored. This option can
+// be used multiple times; later values of the same key replace earlier ones.
+func WithKV(kv map[string]string) Option {
+	return func(o *P) {
+		if o.KV == nil {
+			o.KV = make(map[string]string, len(kv))
+		}
+
+		for k, v := range kv {
+			o.KV[k] = v
+		}
+	}
+}
+
+// WithServices registers the provided services in the agent once the
+// container is ready, so that they can be discovered using HTTP API or DNS.
+// This option can be used multiple times.
+func WithServices(services ...Service) Option {
+	return func(o *P) {
+		o.Services = append(o.Services, services...)
+	}
+}
@@ -0,0 +1,221 @@
+// Package consul includes HashiCorp Consul implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured Consul container to use in tests.
+//
+// The container runs a single Consul agent in development mode. The default
+// port serves HTTP API, for example "/v1/kv/<key>", and DNSPort answers DNS
+// queries, such as "<service>.service.consul", over UDP.
+package consul
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// DNSPort is a name of the port exposed by Consul containers in addition to
+// the default HTTP API port.
+const DNSPort = "dns"
+
+const (
+	defaultVersion = "1.15.1"
+	defaultPort    = 8500
+	dnsPort        = 8600
+)
+
+func init() {
+	registry.Register("consul", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Consul preset. This preset includes a Consul
+// specific healthcheck function and default Consul image and ports, and
+// allows to optionally preload KV entries and register services.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Consul.
+type P struct {
+	Version  string            `json:"version"`
+	KV       map[string]string `json:"kv"`
+	Services []Service         `json:"services"`
+}
+
+// Service is a service registered in Consul agent catalog during container
+// initialization.
+type Service struct {
+	// ID is a unique service ID. If not set, Name is used.
+	ID string `json:"id"`
+
+	// Name is the name of the service used in DNS and catalog queries.
+	Name string `json:"name"`
+
+	// Address is the address of the service. If not set, the address of the
+	// agent is used.
+	Address string `json:"address"`
+
+	// Port is the port of the service.
+	Port int `json:"port"`
+
+	// Tags are optional service tags, for example "primary".
+	Tags []string `json:"tags"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/hashicorp/consul:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[DNSPort] = gnomock.Port{Protocol: "udp", Port: dnsPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+	}
+
+	if len(p.KV) > 0 || len(p.Services) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// healthcheck makes sure that the agent reports its own configuration, and
+// that the cluster has a leader, so that KV writes are accepted.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	if _, err := request(ctx, c, http.MethodGet, "/v1/agent/self", nil); err != nil {
+		return fmt.Errorf("agent self-check failed: %w", err)
+	}
+
+	body, err := request(ctx, c, http.MethodGet, "/v1/status/leader", nil)
+	if err != nil {
+		return err
+	}
+
+	var leader string
+
+	if err := json.Unmarshal(body, &leader); err != nil {
+		return fmt.Errorf("can't decode leader: %w", err)
+	}
+
+	if leader == "" {
+		return fmt.Errorf("no cluster leader")
+	}
+
+	return nil
+}
+
+// initf puts the configured KV entries, and then registers the configured
+// services.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	keys := make([]string, 0, len(p.KV))
+
+	for k := range p.KV {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		path := "/v1/kv/" + escapeKey(k)
+
+		if _, err := request(ctx, c, http.MethodPut, path, []byte(p.KV[k])); err != nil {
+			return fmt.Errorf("can't put key '%s': %w", k, err)
+		}
+	}
+
+	for _, s := range p.Services {
+		if err := registerService(ctx, c, s); err != nil {
+			return fmt.Errorf("can't register service '%s': %w", s.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func registerService(ctx context.Context, c *gnomock.Container, s Service) error {
+	body, err := json.Marshal(struct {
+		ID      string   `json:"ID,omitempty"`
+		Name    string   `json:"Name"`
+		Address string   `json:"Address,omitempty"`
+		Port    int      `json:"Port,omitempty"`
+		Tags    []string `json:"Tags,omitempty"`
+	}{s.ID, s.Name, s.Address, s.Port, s.Tags})
+	if err != nil {
+		return err
+	}
+
+	_, err = request(ctx, c, http.MethodPut, "/v1/agent/service/register", body)
+
+	return err
+}
+
+// escapeKey escapes every segment of the provided key, without the leading
+// slash which Consul does not allow.
+func escapeKey(key string) string {
+	segments := strings.Split(strings.TrimPrefix(key, "/"), "/")
+
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// request sends a request to Consul HTTP API, and returns the response body.
+func request(ctx context.Context, c *gnomock.Container, method, path string, body []byte) ([]byte, error) {
+	addr := fmt.Sprintf("http://%s%s", c.DefaultAddress(), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, strings.TrimSpace(string(bs)))
+	}
+
+	return bs, nil
+}
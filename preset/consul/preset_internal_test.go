@@ -0,0 +1,15 @@
+package consul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeKey(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "config/app/name", escapeKey("/config/app/name"))
+	require.Equal(t, "config/app/name", escapeKey("config/app/name"))
+	require.Equal(t, "config/my%20app/a%3Fb", escapeKey("config/my app/a?b"))
+}
@@ -0,0 +1,99 @@
+package consul_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/consul"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"1.14.5", "1.15.1"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := consul.Preset(
+			consul.WithVersion(version),
+			consul.WithKV(map[string]string{
+				"/config/app/name": "gnomock",
+				"config/app/port":  "8080",
+			}),
+			consul.WithServices(
+				consul.Service{Name: "web", Address: "10.0.0.1", Port: 80, Tags: []string{"primary"}},
+				consul.Service{ID: "web-2", Name: "web", Address: "10.0.0.2", Port: 80},
+			),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		require.Equal(t, "gnomock", get(t, container, "/v1/kv/config/app/name?raw"))
+		require.Equal(t, "8080", get(t, container, "/v1/kv/config/app/port?raw"))
+
+		var services []struct {
+			ServiceID   string   `json:"ServiceID"`
+			ServiceTags []string `json:"ServiceTags"`
+		}
+
+		require.NoError(t, json.Unmarshal([]byte(get(t, container, "/v1/catalog/service/web")), &services))
+		require.Len(t, services, 2)
+
+		resolver := &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				d := net.Dialer{}
+				return d.DialContext(ctx, "udp", container.Address(consul.DNSPort))
+			},
+		}
+
+		addrs, err := resolver.LookupHost(context.Background(), "web.service.consul")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"10.0.0.1", "10.0.0.2"}, addrs)
+
+		addrs, err = resolver.LookupHost(context.Background(), "primary.web.service.consul")
+		require.NoError(t, err)
+		require.Equal(t, []string{"10.0.0.1"}, addrs)
+	}
+}
+
+func TestPreset_defaults(t *testing.T) {
+	t.Parallel()
+
+	p := consul.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+	require.NotEqual(t, `""`, get(t, container, "/v1/status/leader"))
+}
+
+func get(t *testing.T, c *gnomock.Container, path string) string {
+	t.Helper()
+
+	resp, err := http.Get(fmt.Sprintf("http://%s%s", c.DefaultAddress(), path)) // nolint:gosec,noctx
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	bs, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	require.Equalf(t, http.StatusOK, resp.StatusCode, string(bs))
+
+	return string(bs)
+}
@@ -0,0 +1,78 @@
+package couchbase
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version, for example "community-7.1.1" or
+// "enterprise-7.1.4".
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithUser sets cluster administrator credentials. The password must be at
+// least 6 characters long. If not used, the default credentials are
+// gnomock:gnomick.
+func WithUser(user, password string) Option {
+	return func(o *P) {
+		o.User = user
+		o.Password = password
+	}
+}
+
+// WithServices sets the services to enable on the node, such as "kv", "n1ql",
+// "index" or "fts". By default, "kv", "n1ql" and "index" services are
+// enabled.
+func WithServices(services ...string) Option {
+	return func(o *P) {
+		o.Services = append(o.Services, services...)
+	}
+}
+
+// WithMemoryQuota sets the memory quota of the provided service in megabytes,
+// for example WithMemoryQuota("kv", 512). The quotas of "kv", "index", "fts",
+// "eventing" and "cbas" services are 256 megabytes by default. The quota of
+// "kv" service must be large enough for all the buckets.
+func WithMemoryQuota(service string, megabytes int) Option {
+	return func(o *P) {
+		if o.MemoryQuotas == nil {
+			o.MemoryQuotas = make(map[string]int)
+		}
+
+		o.MemoryQuotas[service] = megabytes
+	}
+}
+
+// WithBucket creates a bucket with the provided name and memory quota in
+// megabytes. Zero quota means the default quota of 100 megabytes. This option
+// can be used multiple times.
+func WithBucket(name string, ramQuota int) Option {
+	return func(o *P) {
+		o.Buckets = append(o.Buckets, Bucket{Name: name, RAMQuota: ramQuota})
+	}
+}
+
+// WithCollections creates collections using the provided keyspaces in
+// "bucket.scope.collection" format, for example "shop.inventory.items".
+// Scopes are created when needed, and buckets that are not created using
+// WithBucket are created with the default quota. Use "_default" scope to
+// create collections in the default scope. This option can be used multiple
+// times.
+func WithCollections(keyspaces ...string) Option {
+	return func(o *P) {
+		o.Collections = append(o.Collections, keyspaces...)
+	}
+}
+
+// WithQueries executes the provided N1QL queries once the buckets and the
+// collections are created, for example to create indexes or insert
+// documents. Once the queries are executed, the preset waits for the created
+// indexes to be built, unless their build is deferred. This option can be
+// used multiple times.
+func WithQueries(queries ...string) Option {
+	return func(o *P) {
+		o.Queries = append(o.Queries, queries...)
+	}
+}
@@ -0,0 +1,160 @@
+// Package couchbase includes Couchbase Server implementation of Gnomock
+// Preset interface. This Preset can be passed to gnomock.Start() function to
+// create a configured single-node Couchbase cluster to use in tests.
+//
+// The cluster is initialized during initial setup: services are enabled,
+// memory quotas are set, and buckets, scopes, collections and indexes are
+// created. The default port serves cluster management REST API. Other
+// services are available using named ports, and are advertised to SDK
+// clients as external alternate addresses, so clients should use ConnString
+// to connect.
+package couchbase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// Named ports exposed by Couchbase containers in addition to the default
+// cluster management port.
+const (
+	KVPort     = "kv"
+	ViewsPort  = "views"
+	QueryPort  = "query"
+	SearchPort = "search"
+)
+
+const (
+	defaultVersion  = "community-7.1.1"
+	defaultPort     = 8091
+	kvPort          = 11210
+	viewsPort       = 8092
+	queryPort       = 8093
+	searchPort      = 8094
+	defaultUser     = "gnomock"
+	defaultPassword = "gnomick"
+
+	defaultMemoryQuota = 256
+	defaultBucketQuota = 100
+)
+
+// defaultServices are enough to store documents and query them using N1QL.
+var defaultServices = []string{"kv", "n1ql", "index"}
+
+func init() {
+	registry.Register("couchbase", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Couchbase preset. This preset includes a
+// Couchbase specific healthcheck function and default Couchbase image and
+// ports, and initializes a single-node cluster.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Couchbase.
+type P struct {
+	Version      string         `json:"version"`
+	User         string         `json:"user"`
+	Password     string         `json:"password"`
+	Services     []string       `json:"services"`
+	MemoryQuotas map[string]int `json:"memory_quotas"`
+	Buckets      []Bucket       `json:"buckets"`
+	Collections  []string       `json:"collections"`
+	Queries      []string       `json:"queries"`
+}
+
+// Bucket is a Couchbase bucket created during initial setup.
+type Bucket struct {
+	Name string `json:"name"`
+
+	// RAMQuota is the memory quota of the bucket in megabytes, 100 by
+	// default.
+	RAMQuota int `json:"ram_quota"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/couchbase/server:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[KVPort] = gnomock.Port{Protocol: "tcp", Port: kvPort}
+	namedPorts[ViewsPort] = gnomock.Port{Protocol: "tcp", Port: viewsPort}
+	namedPorts[QueryPort] = gnomock.Port{Protocol: "tcp", Port: queryPort}
+	namedPorts[SearchPort] = gnomock.Port{Protocol: "tcp", Port: searchPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	return []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithInit(p.initf),
+	}
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.User == "" && p.Password == "" {
+		p.User = defaultUser
+		p.Password = defaultPassword
+	}
+
+	if len(p.Services) == 0 {
+		p.Services = defaultServices
+	}
+}
+
+// healthcheck makes sure that cluster management API is available. The
+// cluster is not initialized at this point, so no credentials are required.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	addr := fmt.Sprintf("http://%s/pools", c.DefaultAddress())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ConnString returns a connection string that can be used by Couchbase SDKs
+// to connect to the provided container. It uses external alternate addresses
+// configured during initial setup, since the ports of the container are
+// mapped to random host ports.
+func ConnString(c *gnomock.Container) string {
+	params := url.Values{"network": []string{"external"}}
+
+	return fmt.Sprintf("couchbase://%s:%d?%s", c.Host, c.Port(KVPort), params.Encode())
+}
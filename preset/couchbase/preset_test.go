@@ -0,0 +1,94 @@
+package couchbase_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/couchbase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"community-7.0.2", "community-7.1.1"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := couchbase.Preset(
+			couchbase.WithVersion(version),
+			couchbase.WithUser("admin", "secret"),
+			couchbase.WithMemoryQuota("kv", 512),
+			couchbase.WithBucket("shop", 200),
+			couchbase.WithCollections("shop.inventory.items", "audit._default.events"),
+			couchbase.WithQueries(
+				"create index items_by_name on shop.inventory.items(name)",
+				`insert into shop.inventory.items (key, value) values ("apple", {"name": "apple", "price": 1.5})`,
+			),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+		require.Contains(t, couchbase.ConnString(container), "network=external")
+
+		rows := query(t, container, `select raw price from shop.inventory.items where name = "apple"`)
+		require.Equal(t, []interface{}{1.5}, rows)
+
+		rows = query(t, container, `select raw state from system:indexes where name = "items_by_name"`)
+		require.Equal(t, []interface{}{"online"}, rows)
+
+		rows = query(t, container, `select raw count(*) from audit._default.events`)
+		require.Equal(t, []interface{}{float64(0)}, rows)
+	}
+}
+
+func TestPreset_invalidKeyspace(t *testing.T) {
+	t.Parallel()
+
+	p := couchbase.Preset(couchbase.WithCollections("shop.items"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid keyspace 'shop.items'")
+}
+
+func query(t *testing.T, c *gnomock.Container, q string) []interface{} {
+	t.Helper()
+
+	addr := fmt.Sprintf("http://%s/query/service", c.Address(couchbase.QueryPort))
+	form := url.Values{"statement": []string{q}}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, addr, strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+
+	req.SetBasicAuth("admin", "secret")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	var result struct {
+		Results []interface{} `json:"results"`
+		Errors  []interface{} `json:"errors"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Empty(t, result.Errors)
+
+	return result.Results
+}
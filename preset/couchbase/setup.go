@@ -0,0 +1,409 @@
+package couchbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+)
+
+// memoryQuotaParams are the names of cluster memory quota parameters of the
+// services that require a quota.
+var memoryQuotaParams = map[string]string{
+	"kv":       "memoryQuota",
+	"index":    "indexMemoryQuota",
+	"fts":      "ftsMemoryQuota",
+	"eventing": "eventingMemoryQuota",
+	"cbas":     "cbasMemoryQuota",
+}
+
+// keyspaceNotFoundCode is reported by the query service when a collection
+// created during setup is not yet known to it.
+const keyspaceNotFoundCode = 12003
+
+// initf performs cluster initialization, which is the same sequence of
+// requests a user would perform using the web console or couchbase-cli, and
+// then creates the buckets, the collections and runs the queries.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	buckets, err := p.allBuckets()
+	if err != nil {
+		return err
+	}
+
+	if err := p.initCluster(ctx, c); err != nil {
+		return err
+	}
+
+	for _, b := range buckets {
+		if err := p.createBucket(ctx, c, b); err != nil {
+			return err
+		}
+	}
+
+	if err := p.createCollections(ctx, c); err != nil {
+		return err
+	}
+
+	if len(p.Queries) == 0 {
+		return nil
+	}
+
+	return p.runQueries(ctx, c)
+}
+
+func (p *P) initCluster(ctx context.Context, c *gnomock.Container) error {
+	services := url.Values{"services": []string{strings.Join(p.Services, ",")}}
+	if _, err := p.post(ctx, c, "/node/controller/setupServices", services); err != nil {
+		return fmt.Errorf("can't set up services: %w", err)
+	}
+
+	quotas := url.Values{}
+
+	for _, service := range p.Services {
+		param, ok := memoryQuotaParams[service]
+		if !ok {
+			continue
+		}
+
+		quota := p.MemoryQuotas[service]
+		if quota == 0 {
+			quota = defaultMemoryQuota
+		}
+
+		quotas.Set(param, strconv.Itoa(quota))
+	}
+
+	if _, err := p.post(ctx, c, "/pools/default", quotas); err != nil {
+		return fmt.Errorf("can't set memory quotas: %w", err)
+	}
+
+	credentials := url.Values{
+		"port":     []string{"SAME"},
+		"username": []string{p.User},
+		"password": []string{p.Password},
+	}
+	if _, err := p.post(ctx, c, "/settings/web", credentials); err != nil {
+		return fmt.Errorf("can't set credentials: %w", err)
+	}
+
+	if hasService(p.Services, "index") {
+		// community edition only supports this storage mode
+		storage := url.Values{"storageMode": []string{"forestdb"}}
+		if strings.Contains(p.Version, "enterprise") {
+			storage.Set("storageMode", "plasma")
+		}
+
+		if _, err := p.post(ctx, c, "/settings/indexes", storage); err != nil {
+			return fmt.Errorf("can't set index storage mode: %w", err)
+		}
+	}
+
+	return p.setupAlternateAddresses(ctx, c)
+}
+
+// setupAlternateAddresses advertises host ports of the container to SDK
+// clients that use "external" network.
+func (p *P) setupAlternateAddresses(ctx context.Context, c *gnomock.Container) error {
+	addresses := url.Values{
+		"hostname": []string{c.Host},
+		"mgmt":     []string{strconv.Itoa(c.DefaultPort())},
+		"kv":       []string{strconv.Itoa(c.Port(KVPort))},
+		"capi":     []string{strconv.Itoa(c.Port(ViewsPort))},
+		"n1ql":     []string{strconv.Itoa(c.Port(QueryPort))},
+		"fts":      []string{strconv.Itoa(c.Port(SearchPort))},
+	}
+
+	_, err := p.request(ctx, c, http.MethodPut, "/node/controller/setupAlternateAddresses/external", addresses)
+	if err != nil {
+		return fmt.Errorf("can't set up alternate addresses: %w", err)
+	}
+
+	return nil
+}
+
+// allBuckets returns configured buckets followed by the buckets that are
+// only referred to by the collections.
+func (p *P) allBuckets() ([]Bucket, error) {
+	buckets := make([]Bucket, 0, len(p.Buckets))
+	known := make(map[string]bool, len(p.Buckets))
+
+	for _, b := range p.Buckets {
+		buckets = append(buckets, b)
+		known[b.Name] = true
+	}
+
+	for _, keyspace := range p.Collections {
+		bucket, _, _, err := splitKeyspace(keyspace)
+		if err != nil {
+			return nil, err
+		}
+
+		if !known[bucket] {
+			buckets = append(buckets, Bucket{Name: bucket})
+			known[bucket] = true
+		}
+	}
+
+	return buckets, nil
+}
+
+// createBucket creates the provided bucket, and waits until it is ready to
+// accept documents.
+func (p *P) createBucket(ctx context.Context, c *gnomock.Container, b Bucket) error {
+	quota := b.RAMQuota
+	if quota == 0 {
+		quota = defaultBucketQuota
+	}
+
+	params := url.Values{
+		"name":         []string{b.Name},
+		"bucketType":   []string{"couchbase"},
+		"ramQuotaMB":   []string{strconv.Itoa(quota)},
+		"flushEnabled": []string{"1"},
+	}
+
+	if _, err := p.post(ctx, c, "/pools/default/buckets", params); err != nil {
+		return fmt.Errorf("can't create bucket '%s': %w", b.Name, err)
+	}
+
+	var (
+		status string
+		err    error
+	)
+
+	for {
+		status, err = p.bucketStatus(ctx, c, b.Name)
+		if err == nil && status == "healthy" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("bucket '%s' is not ready (status '%s'): %w", b.Name, status, ctx.Err())
+		case <-time.After(time.Millisecond * 250):
+		}
+	}
+}
+
+func (p *P) bucketStatus(ctx context.Context, c *gnomock.Container, name string) (string, error) {
+	body, err := p.request(ctx, c, http.MethodGet, "/pools/default/buckets/"+url.PathEscape(name), nil)
+	if err != nil {
+		return "", err
+	}
+
+	var bucket struct {
+		Nodes []struct {
+			Status string `json:"status"`
+		} `json:"nodes"`
+	}
+
+	if err := json.Unmarshal(body, &bucket); err != nil {
+		return "", fmt.Errorf("can't decode bucket: %w", err)
+	}
+
+	if len(bucket.Nodes) == 0 {
+		return "", errors.New("bucket has no nodes")
+	}
+
+	return bucket.Nodes[0].Status, nil
+}
+
+// createCollections creates the configured collections, and their scopes
+// unless they are the default scope or already exist.
+func (p *P) createCollections(ctx context.Context, c *gnomock.Container) error {
+	scopes := make(map[string]bool)
+
+	for _, keyspace := range p.Collections {
+		bucket, scope, collection, err := splitKeyspace(keyspace)
+		if err != nil {
+			return err
+		}
+
+		scopesPath := "/pools/default/buckets/" + url.PathEscape(bucket) + "/scopes"
+
+		if scope != "_default" && !scopes[bucket+"."+scope] {
+			if _, err := p.post(ctx, c, scopesPath, url.Values{"name": []string{scope}}); err != nil {
+				return fmt.Errorf("can't create scope '%s.%s': %w", bucket, scope, err)
+			}
+
+			scopes[bucket+"."+scope] = true
+		}
+
+		collectionsPath := scopesPath + "/" + url.PathEscape(scope) + "/collections"
+		if _, err := p.post(ctx, c, collectionsPath, url.Values{"name": []string{collection}}); err != nil {
+			return fmt.Errorf("can't create collection '%s': %w", keyspace, err)
+		}
+	}
+
+	return nil
+}
+
+// splitKeyspace returns bucket, scope and collection names of the provided
+// keyspace in "bucket.scope.collection" format.
+func splitKeyspace(keyspace string) (bucket, scope, collection string, err error) {
+	parts := strings.Split(keyspace, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid keyspace '%s', expected 'bucket.scope.collection'", keyspace)
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
+
+// runQueries executes the provided queries one by one, and then waits for the
+// created indexes to be built, unless their build was deferred.
+func (p *P) runQueries(ctx context.Context, c *gnomock.Container) error {
+	for i, q := range p.Queries {
+		if err := p.retryQuery(ctx, c, q); err != nil {
+			return fmt.Errorf("query %d of %d failed (%s): %w", i+1, len(p.Queries), sqlsetup.Snippet(q), err)
+		}
+	}
+
+	var pending []interface{}
+
+	for {
+		rows, err := p.query(ctx, c, `select raw name from system:indexes where state not in ["online", "deferred"]`)
+		if err == nil && len(rows) == 0 {
+			return nil
+		}
+
+		if err == nil {
+			pending = rows
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("indexes %v are not built: %w", pending, ctx.Err())
+		case <-time.After(time.Millisecond * 250):
+		}
+	}
+}
+
+// retryQuery executes the provided query, and retries it while the query
+// service is not ready or doesn't know about new collections yet.
+func (p *P) retryQuery(ctx context.Context, c *gnomock.Container, q string) error {
+	for {
+		_, err := p.query(ctx, c, q)
+
+		var qErr *queryError
+		if err == nil || (errors.As(err, &qErr) && qErr.Code != keyspaceNotFoundCode) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%v: %w", err, ctx.Err())
+		case <-time.After(time.Millisecond * 250):
+		}
+	}
+}
+
+type queryError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func (e *queryError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Msg)
+}
+
+// query executes the provided N1QL query using query service REST API, and
+// returns the results.
+func (p *P) query(ctx context.Context, c *gnomock.Container, q string) ([]interface{}, error) {
+	addr := fmt.Sprintf("http://%s/query/service", c.Address(QueryPort))
+	form := url.Values{"statement": []string{q}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(p.User, p.Password)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var result struct {
+		Results []interface{} `json:"results"`
+		Errors  []queryError  `json:"errors"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("can't decode query result (status %d): %w", resp.StatusCode, err)
+	}
+
+	if len(result.Errors) > 0 {
+		return nil, &result.Errors[0]
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return result.Results, nil
+}
+
+func (p *P) post(ctx context.Context, c *gnomock.Container, path string, params url.Values) ([]byte, error) {
+	return p.request(ctx, c, http.MethodPost, path, params)
+}
+
+// request sends an authenticated request to cluster management REST API, and
+// returns the response body. Before the credentials are set, they are
+// ignored.
+func (p *P) request(
+	ctx context.Context, c *gnomock.Container, method, path string, params url.Values,
+) ([]byte, error) {
+	addr := fmt.Sprintf("http://%s%s", c.DefaultAddress(), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(p.User, p.Password)
+
+	if params != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, strings.TrimSpace(string(bs)))
+	}
+
+	return bs, nil
+}
+
+func hasService(services []string, service string) bool {
+	for _, s := range services {
+		if s == service {
+			return true
+		}
+	}
+
+	return false
+}
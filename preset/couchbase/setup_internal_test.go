@@ -0,0 +1,40 @@
+package couchbase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitKeyspace(t *testing.T) {
+	t.Parallel()
+
+	bucket, scope, collection, err := splitKeyspace("shop.inventory.items")
+	require.NoError(t, err)
+	require.Equal(t, "shop", bucket)
+	require.Equal(t, "inventory", scope)
+	require.Equal(t, "items", collection)
+
+	for _, keyspace := range []string{"shop", "shop.items", "shop..items", "a.b.c.d"} {
+		_, _, _, err := splitKeyspace(keyspace)
+		require.Error(t, err, keyspace)
+	}
+}
+
+func TestAllBuckets(t *testing.T) {
+	t.Parallel()
+
+	p := &P{
+		Buckets:     []Bucket{{Name: "shop", RAMQuota: 200}},
+		Collections: []string{"shop.inventory.items", "audit._default.events", "audit._default.logins"},
+	}
+
+	buckets, err := p.allBuckets()
+	require.NoError(t, err)
+	require.Equal(t, []Bucket{{Name: "shop", RAMQuota: 200}, {Name: "audit"}}, buckets)
+
+	p.Collections = []string{"invalid"}
+
+	_, err = p.allBuckets()
+	require.Error(t, err)
+}
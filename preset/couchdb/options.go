@@ -0,0 +1,49 @@
+package couchdb
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithUser sets admin user credentials. If not used, the default credentials
+// are gnomock:gnomick.
+func WithUser(user, password string) Option {
+	return func(o *P) {
+		o.User = user
+		o.Password = password
+	}
+}
+
+// WithDatabases creates empty databases with the provided names in the
+// container. This option can be used multiple times.
+func WithDatabases(names ...string) Option {
+	return func(o *P) {
+		o.Databases = append(o.Databases, names...)
+	}
+}
+
+// WithData sets up initial container state according to the files at the
+// given path:
+//
+//   - path/first.json
+//   - path/second.json
+//
+// For such directory structure, two databases are created: "first" and
+// "second". Database name is the file name without its extension.
+//
+// Files contain JSON documents to be inserted into the databases, either one
+// document per line, or a single array of documents. Documents may include
+// "_id" field, otherwise an id is generated.
+//
+// Directories under "path" are ignored, only top level files are used.
+func WithData(path string) Option {
+	return func(o *P) {
+		o.DataPath = path
+	}
+}
@@ -0,0 +1,264 @@
+// Package couchdb includes CouchDB implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured single-node CouchDB container to use in tests.
+//
+// System databases, such as "_users" and "_replicator", are created during
+// initial setup, so that replication and user management work out of the
+// box.
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/jsondocs"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+const (
+	defaultVersion  = "3.3.1"
+	defaultPort     = 5984
+	defaultUser     = "gnomock"
+	defaultPassword = "gnomick"
+)
+
+// systemDatabases are created by cluster setup, which is not used by single
+// node containers.
+var systemDatabases = []string{"_users", "_replicator"}
+
+func init() {
+	registry.Register("couchdb", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock CouchDB preset. This preset includes a CouchDB
+// specific healthcheck function and default CouchDB image and port, and
+// allows to optionally set up initial state.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for CouchDB.
+type P struct {
+	Version   string   `json:"version"`
+	User      string   `json:"user"`
+	Password  string   `json:"password"`
+	Databases []string `json:"databases"`
+	DataPath  string   `json:"data_path"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/library/couchdb:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	return []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithInit(p.initf),
+		gnomock.WithEnv("COUCHDB_USER=" + p.User),
+		gnomock.WithEnv("COUCHDB_PASSWORD=" + p.Password),
+	}
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.User == "" && p.Password == "" {
+		p.User = defaultUser
+		p.Password = defaultPassword
+	}
+}
+
+// healthcheck makes sure that the node is up and accepts the admin
+// credentials.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	body, err := p.request(ctx, c, http.MethodGet, "/_up", nil)
+	if err != nil {
+		return err
+	}
+
+	var status struct {
+		Status string `json:"status"`
+	}
+
+	if err := json.Unmarshal(body, &status); err != nil {
+		return fmt.Errorf("can't decode status: %w", err)
+	}
+
+	if status.Status != "ok" {
+		return fmt.Errorf("unexpected status: %s", status.Status)
+	}
+
+	return nil
+}
+
+// initf creates system databases and the databases configured using options,
+// and then loads the data from the data path, if provided.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	for _, db := range systemDatabases {
+		if err := p.createDatabase(ctx, c, db); err != nil {
+			return err
+		}
+	}
+
+	for _, db := range p.Databases {
+		if err := p.createDatabase(ctx, c, db); err != nil {
+			return err
+		}
+	}
+
+	if p.DataPath == "" {
+		return nil
+	}
+
+	dataFiles, err := os.ReadDir(p.DataPath)
+	if err != nil {
+		return fmt.Errorf("can't read test data path: %w", err)
+	}
+
+	for _, dataFile := range dataFiles {
+		if dataFile.IsDir() {
+			continue
+		}
+
+		fName := dataFile.Name()
+
+		if err := p.setupDatabase(ctx, c, fName); err != nil {
+			return fmt.Errorf("can't setup database from file '%s': %w", fName, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *P) setupDatabase(ctx context.Context, c *gnomock.Container, dataFileName string) error {
+	db := strings.TrimSuffix(dataFileName, path.Ext(dataFileName))
+
+	bs, err := os.ReadFile(path.Join(p.DataPath, dataFileName)) // nolint:gosec
+	if err != nil {
+		return fmt.Errorf("can't read file '%s': %w", dataFileName, err)
+	}
+
+	docs, err := jsondocs.Read(bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+
+	if err := p.createDatabase(ctx, c, db); err != nil {
+		return err
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"docs": docs})
+	if err != nil {
+		return err
+	}
+
+	resBody, err := p.request(ctx, c, http.MethodPost, "/"+url.PathEscape(db)+"/_bulk_docs", body)
+	if err != nil {
+		return fmt.Errorf("can't insert documents: %w", err)
+	}
+
+	var results []apiError
+
+	if err := json.Unmarshal(resBody, &results); err != nil {
+		return fmt.Errorf("can't decode insert results: %w", err)
+	}
+
+	for i, res := range results {
+		if res.Error != "" {
+			return fmt.Errorf("can't insert document %d of %d: %s: %s", i+1, len(results), res.Error, res.Reason)
+		}
+	}
+
+	return nil
+}
+
+// createDatabase creates a database with the provided name, unless it already
+// exists.
+func (p *P) createDatabase(ctx context.Context, c *gnomock.Container, name string) error {
+	_, err := p.request(ctx, c, http.MethodPut, "/"+url.PathEscape(name), nil)
+	if err != nil && !errors.Is(err, errConflict) {
+		return fmt.Errorf("can't create database '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+var errConflict = errors.New("already exists")
+
+type apiError struct {
+	Error  string `json:"error"`
+	Reason string `json:"reason"`
+}
+
+// request sends an authenticated request to CouchDB HTTP API, and returns the
+// response body.
+func (p *P) request(ctx context.Context, c *gnomock.Container, method, path string, body []byte) ([]byte, error) {
+	addr := fmt.Sprintf("http://%s%s", c.DefaultAddress(), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(p.User, p.Password)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return nil, errConflict
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr apiError
+
+		if err := json.Unmarshal(bs, &apiErr); err == nil && apiErr.Reason != "" {
+			return nil, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, apiErr.Reason)
+		}
+
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return bs, nil
+}
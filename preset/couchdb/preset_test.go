@@ -0,0 +1,86 @@
+package couchdb_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/couchdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"3.2.2", "3.3.1"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := couchdb.Preset(
+			couchdb.WithVersion(version),
+			couchdb.WithUser("admin", "secret"),
+			couchdb.WithDatabases("empty"),
+			couchdb.WithData("./testdata"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		require.Equal(t, 0, docCount(t, container, "empty"))
+		require.Equal(t, 2, docCount(t, container, "orders"))
+		require.Equal(t, 3, docCount(t, container, "customers"))
+		require.Equal(t, 0, docCount(t, container, "_replicator"))
+		require.Equal(t, http.StatusNotFound, get(t, container, "/ignored", nil))
+	}
+}
+
+func TestPreset_wrongDataPath(t *testing.T) {
+	t.Parallel()
+
+	p := couchdb.Preset(couchdb.WithData("./testdata/missing"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read test data path")
+}
+
+func docCount(t *testing.T, c *gnomock.Container, db string) int {
+	t.Helper()
+
+	var info struct {
+		DocCount int `json:"doc_count"`
+	}
+
+	require.Equal(t, http.StatusOK, get(t, c, "/"+db, &info))
+
+	return info.DocCount
+}
+
+func get(t *testing.T, c *gnomock.Container, path string, v interface{}) int {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s%s", c.DefaultAddress(), path), nil) // nolint:noctx
+	require.NoError(t, err)
+
+	req.SetBasicAuth("admin", "secret")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	if v != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	}
+
+	return resp.StatusCode
+}
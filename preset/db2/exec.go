@@ -0,0 +1,124 @@
+package db2
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/shell"
+)
+
+// clpPrelude defines a function that runs a command line processor command,
+// and stops the script on errors. Return codes below 4 mean success, no rows
+// or warnings.
+const clpPrelude = `run() { "$@"; rc=$?; if [ "$rc" -ge 4 ]; then exit "$rc"; fi; }
+`
+
+// Query connects to the database created in the provided container, executes
+// the provided query using Db2 command line processor inside the container,
+// and returns its output without column headers, for example
+// "db2.Query(ctx, c, `select count(*) from things`)". Use the same options
+// that were used to create the preset.
+//
+// Query does not require Db2 client libraries on the host, and can be used to
+// verify database state in tests that don't use cgo.
+func Query(ctx context.Context, c *gnomock.Container, query string, opts ...Option) (string, error) {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.setDefaults()
+
+	out, err := p.clp(ctx, c, "run db2 -x "+shell.Quote(query))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// clp runs the provided script as instance owner connected to the database,
+// and returns its output.
+func (p *P) clp(ctx context.Context, c *gnomock.Container, script string) (string, error) {
+	script = clpPrelude + "run db2 connect to " + p.DB + " > /dev/null\n" + script + "\n"
+
+	return execInContainer(ctx, c, "su", "-", p.User, "-c", script)
+}
+
+// execInContainer runs the provided command inside the container, and
+// returns its combined output. Non-zero exit codes are reported as errors
+// that include the output.
+func execInContainer(ctx context.Context, c *gnomock.Container, cmd ...string) (string, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return "", fmt.Errorf("can't connect to docker: %w", err)
+	}
+
+	defer func() { _ = cli.Close() }()
+
+	created, err := cli.ContainerExecCreate(ctx, c.DockerID(), types.ExecConfig{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("can't create exec: %w", err)
+	}
+
+	attached, err := cli.ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{})
+	if err != nil {
+		return "", fmt.Errorf("can't attach to exec: %w", err)
+	}
+
+	defer attached.Close()
+
+	var out bytes.Buffer
+
+	if _, err := stdcopy.StdCopy(&out, &out, attached.Reader); err != nil && err != io.EOF {
+		return "", fmt.Errorf("can't read exec output: %w", err)
+	}
+
+	inspect, err := cli.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return "", fmt.Errorf("can't inspect exec: %w", err)
+	}
+
+	if inspect.ExitCode != 0 {
+		return "", fmt.Errorf("exit code %d: %s", inspect.ExitCode, strings.TrimSpace(out.String()))
+	}
+
+	return out.String(), nil
+}
+
+// containerLogs returns logs of the provided container.
+func containerLogs(ctx context.Context, c *gnomock.Container) (string, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return "", fmt.Errorf("can't connect to docker: %w", err)
+	}
+
+	defer func() { _ = cli.Close() }()
+
+	r, err := cli.ContainerLogs(ctx, c.DockerID(), types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return "", fmt.Errorf("can't read container logs: %w", err)
+	}
+
+	defer func() { _ = r.Close() }()
+
+	var out bytes.Buffer
+
+	if _, err := stdcopy.StdCopy(&out, &out, r); err != nil && err != io.EOF {
+		return "", fmt.Errorf("can't read container logs: %w", err)
+	}
+
+	return out.String(), nil
+}
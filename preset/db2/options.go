@@ -0,0 +1,59 @@
+package db2
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithLicense sets license acceptance state. To accept the license, use true.
+// The container does not start unless the license is accepted. See
+// https://www.ibm.com/terms/?id=L-KHAI-CAC4RJ for more information.
+func WithLicense(accept bool) Option {
+	return func(o *P) {
+		o.License = accept
+	}
+}
+
+// WithDatabase creates a database with the provided name in the container. If
+// not provided, "mydb" is used by default. Database names are limited to 8
+// characters. WithQueries, if provided, runs against the new database.
+func WithDatabase(db string) Option {
+	return func(o *P) {
+		o.DB = db
+	}
+}
+
+// WithUser sets the name and the password of the instance user, which owns
+// the database. The name must be lowercase and up to 8 characters long. If
+// not used, the default credentials are db2inst1:gnomick.
+func WithUser(user, password string) Option {
+	return func(o *P) {
+		o.User = user
+		o.Password = password
+	}
+}
+
+// WithQueries executes the provided queries against the database created with
+// WithDatabase, or against default "mydb" database. Every query should be a
+// single statement without a trailing semicolon.
+func WithQueries(queries ...string) Option {
+	return func(o *P) {
+		o.Queries = append(o.Queries, queries...)
+	}
+}
+
+// WithQueriesFile sets a file name to read initial queries from. Queries from
+// this file are executed before any other queries provided in WithQueries.
+// Statements in the file should end with a semicolon. This option can be used
+// multiple times, and the files are executed in the same order.
+func WithQueriesFile(file string) Option {
+	return func(o *P) {
+		o.QueriesFiles = append(o.QueriesFiles, file)
+	}
+}
@@ -0,0 +1,188 @@
+// Package db2 includes IBM Db2 Community Edition implementation of Gnomock
+// Preset interface. This Preset can be passed to gnomock.Start() function to
+// create a configured Db2 container to use in tests.
+//
+// Db2 containers run in privileged mode, and the first start takes several
+// minutes, while the instance and the database are created. This preset
+// checks the state of the database, and executes initial queries using Db2
+// command line processor inside the container, so that Db2 client libraries
+// are not required on the host. Use Query to run queries the same way.
+package db2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/failinit"
+	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/shell"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+)
+
+const (
+	defaultVersion  = "11.5.8.0"
+	defaultPort     = 50000
+	defaultUser     = "db2inst1"
+	defaultPassword = "gnomick"
+	defaultDatabase = "mydb"
+
+	defaultTimeout             = time.Minute * 15
+	defaultHealthcheckInterval = time.Second * 5
+
+	// setupCompleted is logged once the instance and the database are ready
+	setupCompleted = "Setup has completed"
+)
+
+func init() {
+	registry.Register("db2", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Db2 preset. This preset includes a Db2
+// specific healthcheck function and default Db2 image and port, and allows to
+// optionally set up initial state.
+//
+// You must accept the license to use this image (`WithLicense` option). When
+// used without any configuration, it creates `mydb` database owned by
+// `db2inst1` instance user with `gnomick` password.
+//
+// Db2 takes a long time to start, so this preset waits for up to 15 minutes
+// by default. Use `gnomock.WithTimeout` together with this preset to change
+// it.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Db2.
+type P struct {
+	Version      string   `json:"version"`
+	License      bool     `json:"license"`
+	DB           string   `json:"db"`
+	User         string   `json:"user"`
+	Password     string   `json:"password"`
+	Queries      []string `json:"queries"`
+	QueriesFiles []string `json:"queries_files"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("icr.io/db2_community/db2:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	if !p.License {
+		return []gnomock.Option{
+			gnomock.WithTimeout(defaultTimeout),
+			gnomock.WithInit(failinit.Func(errors.New("license is not accepted"))),
+		}
+	}
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithHealthCheckInterval(defaultHealthcheckInterval),
+		gnomock.WithTimeout(defaultTimeout),
+		gnomock.WithPrivileged(),
+		gnomock.WithEnv("DB2INSTANCE=" + p.User),
+		gnomock.WithEnv("DB2INST1_PASSWORD=" + p.Password),
+		gnomock.WithEnv("DBNAME=" + p.DB),
+		gnomock.WithEnv("ARCHIVE_LOGS=false"),
+		gnomock.WithEnv("AUTOCONFIG=false"),
+		gnomock.WithEnv("PERSISTENT_HOME=false"),
+		gnomock.WithEnv("SAMPLEDB=false"),
+		gnomock.WithEnv("UPDATEAVAIL=NO"),
+		gnomock.WithEnv("LICENSE=accept"),
+	}
+
+	if len(p.Queries) > 0 || len(p.QueriesFiles) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.DB == "" {
+		p.DB = defaultDatabase
+	}
+
+	if p.User == "" && p.Password == "" {
+		p.User = defaultUser
+		p.Password = defaultPassword
+	}
+}
+
+// healthcheck waits for the container setup to complete, since the instance
+// is restarted during the setup, and then makes sure that the database
+// accepts queries.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	logs, err := containerLogs(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(logs, setupCompleted) {
+		return errors.New("setup is not completed")
+	}
+
+	out, err := p.clp(ctx, c, "run db2 -x 'select 1 from sysibm.sysdummy1'")
+	if err != nil {
+		return fmt.Errorf("can't query database: %w", err)
+	}
+
+	if strings.TrimSpace(out) != "1" {
+		return fmt.Errorf("unexpected healthcheck response: %s", out)
+	}
+
+	return nil
+}
+
+// initf executes statements from queries files, followed by the rest of the
+// queries, as the instance user. Every file is written into the container
+// and executed as a script with semicolon statement terminator.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	for i, f := range p.QueriesFiles {
+		bs, err := os.ReadFile(f) // nolint:gosec
+		if err != nil {
+			return fmt.Errorf("can't read queries file '%s': %w", f, err)
+		}
+
+		script := fmt.Sprintf(
+			"f=$(mktemp)\nprintf '%%s\\n' %s > \"$f\"\nrun db2 -stvf \"$f\"",
+			shell.Quote(string(bs)),
+		)
+
+		if _, err := p.clp(ctx, c, script); err != nil {
+			return fmt.Errorf("queries file %d of %d failed (%s): %w", i+1, len(p.QueriesFiles), f, err)
+		}
+	}
+
+	for i, q := range p.Queries {
+		if _, err := p.clp(ctx, c, "run db2 -sv "+shell.Quote(q)); err != nil {
+			return fmt.Errorf("query %d of %d failed (%s): %w", i+1, len(p.Queries), sqlsetup.Snippet(q), err)
+		}
+	}
+
+	return nil
+}
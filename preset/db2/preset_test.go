@@ -0,0 +1,62 @@
+package db2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/db2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"11.5.8.0"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		opts := []db2.Option{
+			db2.WithVersion(version),
+			db2.WithLicense(true),
+			db2.WithDatabase("gnomock"),
+			db2.WithUser("gnomock", "secret"),
+			db2.WithQueriesFile("./testdata/queries.sql"),
+			db2.WithQueries("insert into things (id, name) values (3, 'baz')"),
+		}
+
+		container, err := gnomock.Start(db2.Preset(opts...))
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+		require.NotEmpty(t, container.DefaultAddress())
+
+		ctx := context.Background()
+
+		out, err := db2.Query(ctx, container, "select count(*) from things", opts...)
+		require.NoError(t, err)
+		require.Equal(t, "3", out)
+
+		out, err = db2.Query(ctx, container, "select name from things where id = 2", opts...)
+		require.NoError(t, err)
+		require.Equal(t, "bar", out)
+
+		_, err = db2.Query(ctx, container, "select * from missing", opts...)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "SQL0204N")
+	}
+}
+
+func TestPreset_withoutLicense(t *testing.T) {
+	t.Parallel()
+
+	container, err := gnomock.Start(db2.Preset())
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.EqualError(t, err, "can't init container: license is not accepted")
+}
@@ -0,0 +1,4 @@
+-- things used in tests
+create table things (id int not null primary key, name varchar(50));
+insert into things (id, name) values (1, 'foo');
+insert into things (id, name) values (2, 'bar');
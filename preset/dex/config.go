@@ -0,0 +1,131 @@
+package dex
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// defaultConfigTemplate renders Dex configuration with in-memory storage.
+// Values are rendered as JSON, which is valid YAML, to avoid escaping issues.
+const defaultConfigTemplate = `issuer: {{ json .Issuer }}
+storage:
+  type: memory
+web:
+  http: {{ json .HTTPAddr }}
+oauth2:
+  skipApprovalScreen: true
+{{- if .StaticPasswords }}
+  passwordConnector: local
+enablePasswordDB: true
+staticPasswords:
+{{- range .StaticPasswords }}
+  - email: {{ json .Email }}
+    username: {{ json .Username }}
+    hash: {{ json .Hash }}
+    userID: {{ json .UserID }}
+{{- end }}
+{{- end }}
+{{- if .StaticClients }}
+staticClients:
+{{- range .StaticClients }}
+  - id: {{ json .ID }}
+    name: {{ json .Name }}
+    secret: {{ json .Secret }}
+    public: {{ .Public }}
+    redirectURIs: {{ json .RedirectURIs }}
+{{- end }}
+{{- end }}
+{{- if .Connectors }}
+connectors:
+{{- range .Connectors }}
+  - type: {{ json .Type }}
+    id: {{ json .ID }}
+    name: {{ json .Name }}
+    config: {{ json .Config }}
+{{- end }}
+{{- end }}
+`
+
+// configData is used to render configuration templates.
+type configData struct {
+	// Issuer is the issuer URL, and HTTPAddr is the address Dex listens on.
+	Issuer   string
+	HTTPAddr string
+	Port     int
+
+	StaticClients   []StaticClient
+	StaticPasswords []hashedPassword
+	Connectors      []Connector
+}
+
+// hashedPassword is a static password with bcrypt hash of the password, as
+// required by Dex.
+type hashedPassword struct {
+	StaticPassword
+	Hash string
+}
+
+// mockConnector logs users in without any interaction. It is used when
+// neither connectors nor static passwords are configured, since Dex requires
+// at least one of them.
+var mockConnector = Connector{Type: "mockCallback", ID: "mock", Name: "Mock"}
+
+// config renders the configuration template with the configured clients,
+// passwords and connectors.
+func (p *P) config() (string, error) {
+	data := configData{
+		Issuer:        fmt.Sprintf("http://%s:%d%s", localhostAddr, p.Port, issuerPath),
+		HTTPAddr:      fmt.Sprintf("0.0.0.0:%d", p.Port),
+		Port:          p.Port,
+		StaticClients: p.StaticClients,
+		Connectors:    p.Connectors,
+	}
+
+	for _, password := range p.StaticPasswords {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return "", fmt.Errorf("can't hash password of '%s': %w", password.Email, err)
+		}
+
+		if password.UserID == "" {
+			password.UserID = password.Username
+		}
+
+		data.StaticPasswords = append(data.StaticPasswords, hashedPassword{password, string(hash)})
+	}
+
+	if len(data.Connectors) == 0 && len(data.StaticPasswords) == 0 {
+		data.Connectors = []Connector{mockConnector}
+	}
+
+	configTemplate := p.ConfigTemplate
+	if configTemplate == "" {
+		configTemplate = defaultConfigTemplate
+	}
+
+	tmpl, err := template.New("config").Funcs(template.FuncMap{"json": toJSON}).Parse(configTemplate)
+	if err != nil {
+		return "", fmt.Errorf("can't parse config template: %w", err)
+	}
+
+	var sb strings.Builder
+
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("can't render config template: %w", err)
+	}
+
+	return sb.String(), nil
+}
+
+func toJSON(v interface{}) (string, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(bs), nil
+}
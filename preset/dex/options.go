@@ -0,0 +1,64 @@
+package dex
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version, for example "v2.36.0".
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithPort allows to use a custom port instead of the default one. If no
+// custom port is provided, port 5556 is used.
+//
+// The same port is used on the host and inside the container, so that the
+// issuer URL is the same for the clients and for Dex. Please make sure that
+// whichever port you choose to use (including the default) is available on
+// the host system. Otherwise this container won't start.
+func WithPort(port int) Option {
+	return func(o *P) {
+		o.Port = port
+	}
+}
+
+// WithStaticClients adds OAuth2 clients to Dex configuration. This option can
+// be used multiple times.
+func WithStaticClients(clients ...StaticClient) Option {
+	return func(o *P) {
+		o.StaticClients = append(o.StaticClients, clients...)
+	}
+}
+
+// WithStaticPasswords enables the local password database with the provided
+// users, and allows them to get tokens using password grant. Passwords are
+// hashed before they are added to Dex configuration. This option can be used
+// multiple times.
+func WithStaticPasswords(passwords ...StaticPassword) Option {
+	return func(o *P) {
+		o.StaticPasswords = append(o.StaticPasswords, passwords...)
+	}
+}
+
+// WithConnectors adds upstream identity providers to Dex configuration. If
+// neither connectors nor static passwords are configured, "mockCallback"
+// connector is used. This option can be used multiple times.
+func WithConnectors(connectors ...Connector) Option {
+	return func(o *P) {
+		o.Connectors = append(o.Connectors, connectors...)
+	}
+}
+
+// WithConfigTemplate replaces the default configuration template. The
+// template uses text/template syntax, and can use the following fields:
+// .Issuer, .HTTPAddr, .Port, .StaticClients, .StaticPasswords (with bcrypt
+// hash available as .Hash) and .Connectors. "json" function renders a value
+// as JSON, which is also valid YAML. Dex must serve .Issuer on .HTTPAddr
+// for the healthcheck to pass.
+func WithConfigTemplate(configTemplate string) Option {
+	return func(o *P) {
+		o.ConfigTemplate = configTemplate
+	}
+}
@@ -0,0 +1,176 @@
+// Package dex includes Dex implementation of Gnomock Preset interface. This
+// Preset can be passed to gnomock.Start() function to create a configured
+// Dex OpenID Connect provider to use in tests.
+//
+// Dex tokens include the issuer URL, so Dex containers use the same port on
+// the host and inside the container, and the issuer is built from the local
+// address and this port. Use Issuer to get the issuer URL, and configure
+// OpenID Connect clients using its discovery document.
+package dex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/failinit"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+const (
+	defaultVersion = "v2.36.0"
+	defaultPort    = 5556
+	localhostAddr  = "127.0.0.1"
+	issuerPath     = "/dex"
+	configEnv      = "DEX_CONFIG"
+	configFile     = "/tmp/gnomock-dex.yaml"
+)
+
+// entrypoint writes the configuration from DEX_CONFIG environment variable to
+// a file, and starts Dex using this file.
+const entrypoint = `printf '%s' "$DEX_CONFIG" > ` + configFile + ` && exec dex serve ` + configFile
+
+func init() {
+	registry.Register("dex", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Dex preset. This preset includes a Dex
+// specific healthcheck function and default Dex image and port, and allows to
+// configure static clients, static passwords and connectors.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Dex.
+type P struct {
+	Version         string           `json:"version"`
+	Port            int              `json:"port"`
+	StaticClients   []StaticClient   `json:"static_clients"`
+	StaticPasswords []StaticPassword `json:"static_passwords"`
+	Connectors      []Connector      `json:"connectors"`
+	ConfigTemplate  string           `json:"config_template"`
+}
+
+// StaticClient is an OAuth2 client defined in Dex configuration. Public
+// clients don't use a secret.
+type StaticClient struct {
+	ID           string   `json:"id"`
+	Secret       string   `json:"secret"`
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	Public       bool     `json:"public"`
+}
+
+// StaticPassword is a user of the local password database. Users log in
+// with their email, and UserID defaults to the username.
+type StaticPassword struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	UserID   string `json:"user_id"`
+}
+
+// Connector is an upstream identity provider configuration. Type is one of
+// Dex connector types, for example "mockCallback", "ldap" or "oidc", and
+// Config includes type specific configuration.
+type Connector struct {
+	Type   string                 `json:"type"`
+	ID     string                 `json:"id"`
+	Name   string                 `json:"name"`
+	Config map[string]interface{} `json:"config"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("ghcr.io/dexidp/dex:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	port := gnomock.TCP(p.Port)
+	port.HostPort = p.Port
+
+	return gnomock.NamedPorts{gnomock.DefaultPort: port}
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	config, err := p.config()
+	if err != nil {
+		return []gnomock.Option{gnomock.WithInit(failinit.Func(err))}
+	}
+
+	return []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithEnv(configEnv + "=" + config),
+		gnomock.WithEntrypoint("/bin/sh", "-c", entrypoint),
+	}
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.Port == 0 {
+		p.Port = defaultPort
+	}
+}
+
+// Issuer returns the issuer URL of the provided container. OpenID Connect
+// discovery document is available under "/.well-known/openid-configuration"
+// path of this URL.
+func Issuer(c *gnomock.Container) string {
+	return fmt.Sprintf("http://%s%s", c.DefaultAddress(), issuerPath)
+}
+
+// healthcheck makes sure that the discovery document is served.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	addr := Issuer(c) + "/.well-known/openid-configuration"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	var doc struct {
+		Issuer string `json:"issuer"`
+	}
+
+	if err := json.Unmarshal(bs, &doc); err != nil {
+		return fmt.Errorf("can't parse discovery document: %w", err)
+	}
+
+	if doc.Issuer == "" {
+		return fmt.Errorf("discovery document has no issuer")
+	}
+
+	return nil
+}
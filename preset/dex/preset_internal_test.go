@@ -0,0 +1,102 @@
+package dex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default", func(t *testing.T) {
+		p := &P{}
+		p.setDefaults()
+
+		config, err := p.config()
+		require.NoError(t, err)
+
+		var out struct {
+			Issuer string `yaml:"issuer"`
+			Web    struct {
+				HTTP string `yaml:"http"`
+			} `yaml:"web"`
+			EnablePasswordDB bool `yaml:"enablePasswordDB"`
+			Connectors       []struct {
+				Type string `yaml:"type"`
+			} `yaml:"connectors"`
+		}
+
+		require.NoError(t, yaml.Unmarshal([]byte(config), &out))
+		require.Equal(t, "http://127.0.0.1:5556/dex", out.Issuer)
+		require.Equal(t, "0.0.0.0:5556", out.Web.HTTP)
+		require.False(t, out.EnablePasswordDB)
+		require.Len(t, out.Connectors, 1)
+		require.Equal(t, "mockCallback", out.Connectors[0].Type)
+	})
+
+	t.Run("clients and passwords", func(t *testing.T) {
+		p := &P{
+			Port: 15556,
+			StaticClients: []StaticClient{
+				{ID: "app", Secret: `"quoted": secret`, RedirectURIs: []string{"http://localhost/callback"}},
+			},
+			StaticPasswords: []StaticPassword{
+				{Email: "alice@example.com", Username: "alice", Password: "alice-password"},
+			},
+		}
+		p.setDefaults()
+
+		config, err := p.config()
+		require.NoError(t, err)
+
+		var out struct {
+			OAuth2 struct {
+				PasswordConnector string `yaml:"passwordConnector"`
+			} `yaml:"oauth2"`
+			EnablePasswordDB bool `yaml:"enablePasswordDB"`
+			StaticPasswords  []struct {
+				Email  string `yaml:"email"`
+				Hash   string `yaml:"hash"`
+				UserID string `yaml:"userID"`
+			} `yaml:"staticPasswords"`
+			StaticClients []struct {
+				ID           string   `yaml:"id"`
+				Secret       string   `yaml:"secret"`
+				RedirectURIs []string `yaml:"redirectURIs"`
+			} `yaml:"staticClients"`
+			Connectors []interface{} `yaml:"connectors"`
+		}
+
+		require.NoError(t, yaml.Unmarshal([]byte(config), &out))
+		require.Equal(t, "local", out.OAuth2.PasswordConnector)
+		require.True(t, out.EnablePasswordDB)
+		require.Len(t, out.StaticPasswords, 1)
+		require.Equal(t, "alice", out.StaticPasswords[0].UserID)
+		require.NoError(t, bcrypt.CompareHashAndPassword([]byte(out.StaticPasswords[0].Hash), []byte("alice-password")))
+		require.Len(t, out.StaticClients, 1)
+		require.Equal(t, `"quoted": secret`, out.StaticClients[0].Secret)
+		require.Equal(t, []string{"http://localhost/callback"}, out.StaticClients[0].RedirectURIs)
+		require.Empty(t, out.Connectors)
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		p := &P{ConfigTemplate: "issuer: {{ json .Issuer }}"}
+		p.setDefaults()
+
+		config, err := p.config()
+		require.NoError(t, err)
+		require.Equal(t, `issuer: "http://127.0.0.1:5556/dex"`, config)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		p := &P{ConfigTemplate: "{{ .Unknown"}
+		p.setDefaults()
+
+		_, err := p.config()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "can't parse config template")
+	})
+}
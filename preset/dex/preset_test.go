@@ -0,0 +1,132 @@
+package dex_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/dex"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"v2.35.3", "v2.36.0"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := dex.Preset(
+			dex.WithVersion(version),
+			dex.WithPort(25556),
+			dex.WithStaticClients(dex.StaticClient{
+				ID:           "app",
+				Secret:       "app-secret",
+				Name:         "App",
+				RedirectURIs: []string{"http://localhost:3000/callback"},
+			}),
+			dex.WithStaticPasswords(dex.StaticPassword{
+				Email:    "alice@example.com",
+				Username: "alice",
+				Password: "alice-password",
+			}),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		tokenEndpoint := discover(t, dex.Issuer(container))
+
+		status, token := requestToken(t, tokenEndpoint, "alice@example.com", "alice-password")
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, token)
+
+		status, token = requestToken(t, tokenEndpoint, "alice@example.com", "wrong-password")
+		require.NotEqual(t, http.StatusOK, status)
+		require.Empty(t, token)
+	}
+}
+
+func TestPreset_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	p := dex.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+	require.NotEmpty(t, discover(t, dex.Issuer(container)))
+}
+
+func TestPreset_invalidConfigTemplate(t *testing.T) {
+	t.Parallel()
+
+	p := dex.Preset(dex.WithPort(25557), dex.WithConfigTemplate("{{ .Unknown"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't init container: can't parse config template")
+}
+
+// discover returns token endpoint from the discovery document of the issuer.
+func discover(t *testing.T, issuer string) string {
+	t.Helper()
+
+	resp, err := http.Get(issuer + "/.well-known/openid-configuration") // nolint:gosec,noctx
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var doc struct {
+		Issuer        string `json:"issuer"`
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	require.Equal(t, issuer, doc.Issuer)
+
+	return doc.TokenEndpoint
+}
+
+func requestToken(t *testing.T, tokenEndpoint, email, password string) (int, string) {
+	t.Helper()
+
+	form := url.Values{
+		"grant_type": {"password"},
+		"scope":      {"openid email"},
+		"username":   {email},
+		"password":   {password},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode())) // nolint:noctx
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("app", "app-secret")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	var out struct {
+		IDToken string `json:"id_token"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+	return resp.StatusCode, out.IDToken
+}
@@ -0,0 +1,20 @@
+package dynamodb
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version, for example "1.21.0".
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithTables creates tables during initial setup, and optionally puts items
+// into them. This option can be used multiple times.
+func WithTables(tables ...Table) Option {
+	return func(o *P) {
+		o.Tables = append(o.Tables, tables...)
+	}
+}
@@ -0,0 +1,281 @@
+// Package dynamodb includes DynamoDB Local implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create
+// a configured DynamoDB Local container to use in tests.
+//
+// DynamoDB Local uses a single shared database, so clients can use any
+// region and credentials.
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+const (
+	defaultVersion = "1.21.0"
+	defaultPort    = 8000
+	region         = "us-east-1"
+)
+
+func init() {
+	registry.Register("dynamodb", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock DynamoDB preset. This preset includes a
+// DynamoDB specific healthcheck function and default DynamoDB Local image and
+// port, and allows to optionally create tables and put items into them.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for DynamoDB Local.
+type P struct {
+	Version string  `json:"version"`
+	Tables  []Table `json:"tables"`
+}
+
+// Table is a DynamoDB table created during initial setup. Tables use
+// on-demand billing mode.
+type Table struct {
+	Name string `json:"name"`
+
+	// PartitionKey is required, while SortKey is optional.
+	PartitionKey KeyAttribute  `json:"partition_key"`
+	SortKey      *KeyAttribute `json:"sort_key"`
+
+	GlobalIndexes []Index `json:"global_indexes"`
+
+	// TTLAttribute, if set, enables time to live using this attribute.
+	TTLAttribute string `json:"ttl_attribute"`
+
+	// ItemsFile is a path to a JSON file with an array of items to put into
+	// the table after it is created.
+	ItemsFile string `json:"items_file"`
+}
+
+// KeyAttribute is an attribute used in a table or index key. Type is one of
+// "S", "N" or "B", for string, number and binary attributes.
+type KeyAttribute struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Index is a global secondary index of a table, projecting all the
+// attributes.
+type Index struct {
+	Name         string        `json:"name"`
+	PartitionKey KeyAttribute  `json:"partition_key"`
+	SortKey      *KeyAttribute `json:"sort_key"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/amazon/dynamodb-local:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithCommand("-jar", "DynamoDBLocal.jar", "-sharedDb", "-inMemory"),
+	}
+
+	if len(p.Tables) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// healthcheck makes sure that DynamoDB Local serves API requests.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	svc, err := client(c)
+	if err != nil {
+		return err
+	}
+
+	if _, err := svc.DescribeLimitsWithContext(ctx, &awsdynamodb.DescribeLimitsInput{}); err != nil {
+		return fmt.Errorf("can't describe limits: %w", err)
+	}
+
+	return nil
+}
+
+// initf creates the configured tables, and puts items into them.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	svc, err := client(c)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range p.Tables {
+		if err := createTable(ctx, svc, t); err != nil {
+			return err
+		}
+
+		if err := putItems(ctx, svc, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func client(c *gnomock.Container) (*awsdynamodb.DynamoDB, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Endpoint:    aws.String(fmt.Sprintf("http://%s", c.DefaultAddress())),
+		Credentials: credentials.NewStaticCredentials("gnomock", "gnomock", ""),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't create dynamodb session: %w", err)
+	}
+
+	return awsdynamodb.New(sess), nil
+}
+
+func createTable(ctx context.Context, svc *awsdynamodb.DynamoDB, t Table) error {
+	// every attribute used in any key must be defined exactly once
+	defs := map[string]string{}
+	addDef := func(attrs ...*KeyAttribute) {
+		for _, a := range attrs {
+			if a != nil {
+				defs[a.Name] = a.Type
+			}
+		}
+	}
+
+	addDef(&t.PartitionKey, t.SortKey)
+
+	input := &awsdynamodb.CreateTableInput{
+		TableName:   aws.String(t.Name),
+		BillingMode: aws.String(awsdynamodb.BillingModePayPerRequest),
+		KeySchema:   keySchema(t.PartitionKey, t.SortKey),
+	}
+
+	for _, idx := range t.GlobalIndexes {
+		idx := idx
+
+		addDef(&idx.PartitionKey, idx.SortKey)
+
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, &awsdynamodb.GlobalSecondaryIndex{
+			IndexName:  aws.String(idx.Name),
+			KeySchema:  keySchema(idx.PartitionKey, idx.SortKey),
+			Projection: &awsdynamodb.Projection{ProjectionType: aws.String(awsdynamodb.ProjectionTypeAll)},
+		})
+	}
+
+	for name, typ := range defs {
+		input.AttributeDefinitions = append(input.AttributeDefinitions, &awsdynamodb.AttributeDefinition{
+			AttributeName: aws.String(name),
+			AttributeType: aws.String(typ),
+		})
+	}
+
+	_, err := svc.CreateTableWithContext(ctx, input)
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == awsdynamodb.ErrCodeResourceInUseException {
+		// tables already exist when an existing container is reused
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("can't create table '%s': %w", t.Name, err)
+	}
+
+	if t.TTLAttribute != "" {
+		_, err := svc.UpdateTimeToLiveWithContext(ctx, &awsdynamodb.UpdateTimeToLiveInput{
+			TableName: aws.String(t.Name),
+			TimeToLiveSpecification: &awsdynamodb.TimeToLiveSpecification{
+				AttributeName: aws.String(t.TTLAttribute),
+				Enabled:       aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("can't enable ttl of table '%s': %w", t.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func keySchema(partitionKey KeyAttribute, sortKey *KeyAttribute) []*awsdynamodb.KeySchemaElement {
+	schema := []*awsdynamodb.KeySchemaElement{
+		{AttributeName: aws.String(partitionKey.Name), KeyType: aws.String(awsdynamodb.KeyTypeHash)},
+	}
+
+	if sortKey != nil {
+		schema = append(schema, &awsdynamodb.KeySchemaElement{
+			AttributeName: aws.String(sortKey.Name),
+			KeyType:       aws.String(awsdynamodb.KeyTypeRange),
+		})
+	}
+
+	return schema
+}
+
+func putItems(ctx context.Context, svc *awsdynamodb.DynamoDB, t Table) error {
+	if t.ItemsFile == "" {
+		return nil
+	}
+
+	bs, err := os.ReadFile(t.ItemsFile) // nolint:gosec
+	if err != nil {
+		return fmt.Errorf("can't read items file '%s': %w", t.ItemsFile, err)
+	}
+
+	var items []map[string]interface{}
+
+	if err := json.Unmarshal(bs, &items); err != nil {
+		return fmt.Errorf("can't parse items file '%s': %w", t.ItemsFile, err)
+	}
+
+	for _, item := range items {
+		av, err := dynamodbattribute.MarshalMap(item)
+		if err != nil {
+			return fmt.Errorf("can't convert item of table '%s': %w", t.Name, err)
+		}
+
+		_, err = svc.PutItemWithContext(ctx, &awsdynamodb.PutItemInput{
+			TableName: aws.String(t.Name),
+			Item:      av,
+		})
+		if err != nil {
+			return fmt.Errorf("can't put item into table '%s': %w", t.Name, err)
+		}
+	}
+
+	return nil
+}
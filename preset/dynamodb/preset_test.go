@@ -0,0 +1,121 @@
+package dynamodb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"1.20.0", "1.21.0"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := dynamodb.Preset(
+			dynamodb.WithVersion(version),
+			dynamodb.WithTables(dynamodb.Table{
+				Name:         "users",
+				PartitionKey: dynamodb.KeyAttribute{Name: "id", Type: "S"},
+				GlobalIndexes: []dynamodb.Index{
+					{Name: "by-email", PartitionKey: dynamodb.KeyAttribute{Name: "email", Type: "S"}},
+				},
+				TTLAttribute: "expires_at",
+				ItemsFile:    "testdata/users.json",
+			}),
+			dynamodb.WithTables(dynamodb.Table{
+				Name:         "events",
+				PartitionKey: dynamodb.KeyAttribute{Name: "user", Type: "S"},
+				SortKey:      &dynamodb.KeyAttribute{Name: "time", Type: "N"},
+			}),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		svc := client(t, container)
+
+		tables, err := svc.ListTables(&awsdynamodb.ListTablesInput{})
+		require.NoError(t, err)
+		require.Len(t, tables.TableNames, 2)
+
+		out, err := svc.Query(&awsdynamodb.QueryInput{
+			TableName:              aws.String("users"),
+			IndexName:              aws.String("by-email"),
+			KeyConditionExpression: aws.String("email = :email"),
+			ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+				":email": {S: aws.String("bob@example.com")},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, out.Items, 1)
+		require.Equal(t, "2", *out.Items[0]["id"].S)
+		require.Equal(t, "25", *out.Items[0]["age"].N)
+
+		ttl, err := svc.DescribeTimeToLive(&awsdynamodb.DescribeTimeToLiveInput{TableName: aws.String("users")})
+		require.NoError(t, err)
+		require.Equal(t, "expires_at", *ttl.TimeToLiveDescription.AttributeName)
+
+		events, err := svc.DescribeTable(&awsdynamodb.DescribeTableInput{TableName: aws.String("events")})
+		require.NoError(t, err)
+		require.Len(t, events.Table.KeySchema, 2)
+	}
+}
+
+func TestPreset_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	p := dynamodb.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	tables, err := client(t, container).ListTables(&awsdynamodb.ListTablesInput{})
+	require.NoError(t, err)
+	require.Empty(t, tables.TableNames)
+}
+
+func TestPreset_wrongItemsFile(t *testing.T) {
+	t.Parallel()
+
+	p := dynamodb.Preset(dynamodb.WithTables(dynamodb.Table{
+		Name:         "users",
+		PartitionKey: dynamodb.KeyAttribute{Name: "id", Type: "S"},
+		ItemsFile:    "testdata/missing.json",
+	}))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read items file")
+}
+
+func client(t *testing.T, c *gnomock.Container) *awsdynamodb.DynamoDB {
+	t.Helper()
+
+	// any credentials and region can be used with DynamoDB Local
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		Endpoint:    aws.String(fmt.Sprintf("http://%s", c.DefaultAddress())),
+		Credentials: credentials.NewStaticCredentials("a", "b", ""),
+	})
+	require.NoError(t, err)
+
+	return awsdynamodb.New(sess)
+}
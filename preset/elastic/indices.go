@@ -0,0 +1,109 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7"
+)
+
+func (p *P) createIndices(client *elasticsearch.Client) error {
+	names := make([]string, 0, len(p.Indices))
+	for name := range p.Indices {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		res, err := client.Indices.Create(
+			name,
+			client.Indices.Create.WithBody(strings.NewReader(p.Indices[name])),
+		)
+		if err != nil {
+			return fmt.Errorf("can't create index '%s': %w", name, err)
+		}
+
+		_ = res.Body.Close()
+
+		if res.IsError() {
+			return fmt.Errorf("creation of index '%s' failed: %s", name, res.String())
+		}
+	}
+
+	return nil
+}
+
+// indexDocuments sends all the documents using bulk API, and refreshes the
+// indices so that the documents become searchable. It returns the number of
+// indexed documents.
+func (p *P) indexDocuments(client *elasticsearch.Client) (int, error) {
+	if len(p.Documents) == 0 {
+		return 0, nil
+	}
+
+	var buf bytes.Buffer
+
+	docCount := 0
+	indices := make([]string, 0, len(p.Documents))
+
+	for index, docs := range p.Documents {
+		indices = append(indices, index)
+
+		for _, doc := range docs {
+			action := map[string]interface{}{"index": map[string]string{"_index": index}}
+
+			for _, v := range []interface{}{action, doc} {
+				bs, err := json.Marshal(v)
+				if err != nil {
+					return 0, fmt.Errorf("can't encode document of index '%s': %w", index, err)
+				}
+
+				buf.Write(bs)
+				buf.WriteByte('\n')
+			}
+
+			docCount++
+		}
+	}
+
+	res, err := client.Bulk(&buf)
+	if err != nil {
+		return 0, fmt.Errorf("can't index documents: %w", err)
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	if res.IsError() {
+		return 0, fmt.Errorf("bulk indexing failed: %s", res.String())
+	}
+
+	var out struct {
+		Errors bool `json:"errors"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return 0, fmt.Errorf("invalid bulk indexing response: %w", err)
+	}
+
+	if out.Errors {
+		return 0, errors.New("some documents were not indexed")
+	}
+
+	refresh, err := client.Indices.Refresh(client.Indices.Refresh.WithIndex(indices...))
+	if err != nil {
+		return 0, fmt.Errorf("can't refresh indices: %w", err)
+	}
+
+	_ = refresh.Body.Close()
+
+	if refresh.IsError() {
+		return 0, fmt.Errorf("indices refresh failed: %s", refresh.String())
+	}
+
+	return docCount, nil
+}
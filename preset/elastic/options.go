@@ -23,3 +23,41 @@ func WithInputFile(file string) Option {
 		o.Inputs = append(o.Inputs, file)
 	}
 }
+
+// WithIndex creates an index during initial setup, before any data is
+// ingested. Body is a JSON request body used to create the index, and may
+// include mappings, settings and aliases, for example
+// `{"mappings": {"properties": {"title": {"type": "text"}}}}`.
+func WithIndex(name, body string) Option {
+	return func(o *P) {
+		if o.Indices == nil {
+			o.Indices = make(map[string]string)
+		}
+
+		o.Indices[name] = body
+	}
+}
+
+// WithDocuments indexes the provided documents into an index during initial
+// setup using bulk API. Documents can be any values that can be encoded as JSON
+// objects. Once indexed, the documents are searchable.
+func WithDocuments(index string, docs ...interface{}) Option {
+	return func(o *P) {
+		if o.Documents == nil {
+			o.Documents = make(map[string][]interface{})
+		}
+
+		o.Documents[index] = append(o.Documents[index], docs...)
+	}
+}
+
+// WithPlugins installs the provided official plugins, such as "analysis-icu",
+// before the node starts. The container is ready only when all the plugins
+// are loaded. Plugins are downloaded on every start, so it takes longer for
+// the container to become ready. This option is supported starting with
+// Elasticsearch 6.
+func WithPlugins(plugins ...string) Option {
+	return func(o *P) {
+		o.Plugins = append(o.Plugins, plugins...)
+	}
+}
@@ -45,6 +45,10 @@ func Preset(opts ...Option) gnomock.Preset {
 type P struct {
 	Version string   `json:"version"`
 	Inputs  []string `json:"input_files"`
+
+	Indices   map[string]string        `json:"indices"`
+	Documents map[string][]interface{} `json:"documents"`
+	Plugins   []string                 `json:"plugins"`
 }
 
 // Image returns an image that should be pulled to create this container.
@@ -67,7 +71,21 @@ func (p *P) Options() []gnomock.Option {
 		gnomock.WithHealthCheck(p.healthcheck),
 	}
 
-	if len(p.Inputs) > 0 {
+	if len(p.Plugins) > 0 {
+		// plugins are passed to the script as its arguments, and the node is
+		// started after they are installed
+		script := `set -e
+for plugin in "$@"; do elasticsearch-plugin install --batch "$plugin"; done
+exec /usr/local/bin/docker-entrypoint.sh eswrapper`
+
+		opts = append(
+			opts,
+			gnomock.WithEntrypoint("/bin/bash", "-c", script, "plugins"),
+			gnomock.WithCommand(p.Plugins[0], p.Plugins[1:]...),
+		)
+	}
+
+	if len(p.Inputs)+len(p.Indices)+len(p.Documents) > 0 {
 		opts = append(opts, gnomock.WithInit(p.initf))
 	}
 
@@ -103,6 +121,42 @@ func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) (err error) {
 		return fmt.Errorf("cluster info failed: %s", res.String())
 	}
 
+	return p.checkPlugins(client)
+}
+
+// checkPlugins returns an error if any of the requested plugins is not
+// loaded by the node.
+func (p *P) checkPlugins(client *elasticsearch.Client) error {
+	if len(p.Plugins) == 0 {
+		return nil
+	}
+
+	res, err := client.Cat.Plugins(client.Cat.Plugins.WithFormat("json"))
+	if err != nil {
+		return fmt.Errorf("can't list plugins: %w", err)
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	var plugins []struct {
+		Component string `json:"component"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&plugins); err != nil {
+		return fmt.Errorf("invalid plugins list: %w", err)
+	}
+
+	loaded := make(map[string]bool, len(plugins))
+	for _, plugin := range plugins {
+		loaded[plugin.Component] = true
+	}
+
+	for _, plugin := range p.Plugins {
+		if !loaded[plugin] {
+			return fmt.Errorf("plugin '%s' is not loaded", plugin)
+		}
+	}
+
 	return nil
 }
 
@@ -118,11 +172,22 @@ func (p *P) initf(ctx context.Context, c *gnomock.Container) (err error) {
 		return fmt.Errorf("can't create elasticsearch client: %w", err)
 	}
 
-	docCount, err := p.ingestSeedFiles(ctx, client)
+	if err := p.createIndices(client); err != nil {
+		return err
+	}
+
+	docCount, err := p.indexDocuments(client)
+	if err != nil {
+		return err
+	}
+
+	fileDocCount, err := p.ingestSeedFiles(ctx, client)
 	if err != nil {
 		return fmt.Errorf("seed file ingestion failed: %w", err)
 	}
 
+	docCount += fileDocCount
+
 	tick := time.NewTicker(time.Millisecond * 250)
 	defer tick.Stop()
 
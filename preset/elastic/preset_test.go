@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/orlangure/gnomock"
@@ -97,3 +98,82 @@ func TestPreset_withDefaults(t *testing.T) {
 	_, err = elasticsearch.NewClient(cfg)
 	require.NoError(t, err)
 }
+
+func TestPreset_withIndexAndDocuments(t *testing.T) {
+	if israce.Enabled {
+		t.Skip("elastic tests can't run with race detector due to https://github.com/elastic/go-elasticsearch/issues/147")
+	}
+
+	p := elastic.Preset(
+		elastic.WithIndex("books", `{"mappings":{"properties":{"isbn":{"type":"keyword"}}}}`),
+		elastic.WithDocuments("books",
+			map[string]string{"isbn": "978-0-13-468599-1", "title": "The Go Programming Language"},
+			map[string]string{"isbn": "978-1-59327-584-6", "title": "The Linux Command Line"},
+		),
+	)
+
+	c, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(c)) }()
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses:    []string{fmt.Sprintf("http://%s", c.DefaultAddress())},
+		DisableRetry: true,
+	})
+	require.NoError(t, err)
+
+	res, err := client.Indices.GetMapping(client.Indices.GetMapping.WithIndex("books"))
+	require.NoError(t, err)
+	require.False(t, res.IsError(), res.String())
+	require.Contains(t, res.String(), "keyword")
+	require.NoError(t, res.Body.Close())
+
+	// keyword fields are not analyzed, so only exact values match
+	res, err = client.Search(
+		client.Search.WithIndex("books"),
+		client.Search.WithQuery(`isbn:"978-0-13-468599-1"`),
+	)
+	require.NoError(t, err)
+	require.False(t, res.IsError(), res.String())
+
+	var out struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+		} `json:"hits"`
+	}
+
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&out))
+	require.NoError(t, res.Body.Close())
+	require.Equal(t, 1, out.Hits.Total.Value)
+}
+
+func TestPreset_withPlugins(t *testing.T) {
+	if israce.Enabled {
+		t.Skip("elastic tests can't run with race detector due to https://github.com/elastic/go-elasticsearch/issues/147")
+	}
+
+	p := elastic.Preset(
+		elastic.WithPlugins("analysis-icu", "analysis-kuromoji"),
+		elastic.WithIndex("docs", `{"settings":{"analysis":{"analyzer":{"default":{"type":"icu_analyzer"}}}}}`),
+	)
+
+	c, err := gnomock.Start(p, gnomock.WithTimeout(time.Minute*5))
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(c)) }()
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses:    []string{fmt.Sprintf("http://%s", c.DefaultAddress())},
+		DisableRetry: true,
+	})
+	require.NoError(t, err)
+
+	res, err := client.Cat.Plugins()
+	require.NoError(t, err)
+	require.Contains(t, res.String(), "analysis-icu")
+	require.Contains(t, res.String(), "analysis-kuromoji")
+	require.NoError(t, res.Body.Close())
+}
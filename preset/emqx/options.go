@@ -0,0 +1,44 @@
+package emqx
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithUsers creates MQTT users with the provided names and passwords in the
+// built-in database. When this option is used, clients must authenticate
+// using one of these users. If not used, anonymous clients are allowed. This
+// option can be used multiple times.
+func WithUsers(users map[string]string) Option {
+	return func(o *P) {
+		if o.Users == nil {
+			o.Users = make(map[string]string, len(users))
+		}
+
+		for user, password := range users {
+			o.Users[user] = password
+		}
+	}
+}
+
+// WithRetainedMessages publishes the provided payloads to the provided topics
+// as retained messages once the container is ready, so that the tested code
+// receives them when it subscribes. This option can be used multiple times;
+// later payloads of the same topic replace earlier ones.
+func WithRetainedMessages(messages map[string]string) Option {
+	return func(o *P) {
+		if o.Retained == nil {
+			o.Retained = make(map[string]string, len(messages))
+		}
+
+		for topic, payload := range messages {
+			o.Retained[topic] = payload
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// Package emqx includes EMQX implementation of Gnomock Preset interface. This
+// Preset can be passed to gnomock.Start() function to create a configured
+// EMQX MQTT broker to use in tests.
+//
+// The default port accepts MQTT connections, WebSocketPort accepts MQTT over
+// WebSocket connections at "/mqtt", and DashboardPort serves the dashboard
+// and REST API, for example "/api/v5/clients".
+package emqx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// Named ports exposed by EMQX containers in addition to the default MQTT
+// port.
+const (
+	WebSocketPort = "ws"
+	DashboardPort = "dashboard"
+)
+
+// Dashboard and REST API credentials.
+const (
+	DashboardUser     = "admin"
+	DashboardPassword = "public"
+)
+
+const (
+	defaultVersion = "5.0.21"
+	defaultPort    = 1883
+	webSocketPort  = 8083
+	dashboardPort  = 18083
+	authenticator  = "password_based:built_in_database"
+)
+
+func init() {
+	registry.Register("emqx", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock EMQX preset. This preset includes an EMQX
+// specific healthcheck function and default EMQX image and ports, and allows
+// to optionally require authentication and publish retained messages.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for EMQX.
+type P struct {
+	Version  string            `json:"version"`
+	Users    map[string]string `json:"users"`
+	Retained map[string]string `json:"retained"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/emqx/emqx:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[WebSocketPort] = gnomock.Port{Protocol: "tcp", Port: webSocketPort}
+	namedPorts[DashboardPort] = gnomock.Port{Protocol: "tcp", Port: dashboardPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+	}
+
+	if len(p.Users) > 0 || len(p.Retained) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// healthcheck makes sure that the broker is running, and that REST API
+// accepts the dashboard credentials.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	body, err := request(ctx, c, "", http.MethodGet, "/status", nil)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(string(body), "is running") {
+		return fmt.Errorf("unexpected status: %s", strings.TrimSpace(string(body)))
+	}
+
+	_, err = login(ctx, c)
+
+	return err
+}
+
+// initf creates a built-in database authenticator with the configured users,
+// and then publishes the retained messages.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	token, err := login(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if len(p.Users) > 0 {
+		if err := p.createUsers(ctx, c, token); err != nil {
+			return err
+		}
+	}
+
+	topics := make([]string, 0, len(p.Retained))
+
+	for topic := range p.Retained {
+		topics = append(topics, topic)
+	}
+
+	sort.Strings(topics)
+
+	for _, topic := range topics {
+		body, err := json.Marshal(map[string]interface{}{
+			"topic":   topic,
+			"payload": p.Retained[topic],
+			"qos":     1,
+			"retain":  true,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := request(ctx, c, token, http.MethodPost, "/api/v5/publish", body); err != nil {
+			return fmt.Errorf("can't publish to '%s': %w", topic, err)
+		}
+	}
+
+	return nil
+}
+
+// createUsers creates a built-in database authenticator, which makes the
+// broker reject clients without valid credentials, and adds the configured
+// users to it.
+func (p *P) createUsers(ctx context.Context, c *gnomock.Container, token string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"mechanism":    "password_based",
+		"backend":      "built_in_database",
+		"user_id_type": "username",
+		"password_hash_algorithm": map[string]string{
+			"name":          "sha256",
+			"salt_position": "suffix",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := request(ctx, c, token, http.MethodPost, "/api/v5/authentication", body); err != nil {
+		return fmt.Errorf("can't create authenticator: %w", err)
+	}
+
+	users := make([]string, 0, len(p.Users))
+
+	for user := range p.Users {
+		users = append(users, user)
+	}
+
+	sort.Strings(users)
+
+	for _, user := range users {
+		body, err := json.Marshal(map[string]string{"user_id": user, "password": p.Users[user]})
+		if err != nil {
+			return err
+		}
+
+		path := "/api/v5/authentication/" + authenticator + "/users"
+
+		if _, err := request(ctx, c, token, http.MethodPost, path, body); err != nil {
+			return fmt.Errorf("can't create user '%s': %w", user, err)
+		}
+	}
+
+	return nil
+}
+
+// login returns REST API token of the dashboard user.
+func login(ctx context.Context, c *gnomock.Container) (string, error) {
+	body, err := json.Marshal(map[string]string{"username": DashboardUser, "password": DashboardPassword})
+	if err != nil {
+		return "", err
+	}
+
+	resBody, err := request(ctx, c, "", http.MethodPost, "/api/v5/login", body)
+	if err != nil {
+		return "", fmt.Errorf("can't login: %w", err)
+	}
+
+	var res struct {
+		Token string `json:"token"`
+	}
+
+	if err := json.Unmarshal(resBody, &res); err != nil {
+		return "", fmt.Errorf("can't decode login response: %w", err)
+	}
+
+	return res.Token, nil
+}
+
+// request sends a request to EMQX REST API, authenticated using the provided
+// token unless it is empty, and returns the response body.
+func request(ctx context.Context, c *gnomock.Container, token, method, path string, body []byte) ([]byte, error) {
+	addr := fmt.Sprintf("http://%s%s", c.Address(DashboardPort), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+
+		if err := json.Unmarshal(bs, &apiErr); err == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("unexpected response status %d: %s: %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+		}
+
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return bs, nil
+}
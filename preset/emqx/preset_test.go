@@ -0,0 +1,96 @@
+package emqx_test
+
+import (
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/emqx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"5.0.20", "5.0.21"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := emqx.Preset(
+			emqx.WithVersion(version),
+			emqx.WithUsers(map[string]string{"device": "secret"}),
+			emqx.WithRetainedMessages(map[string]string{"devices/1/state": "on"}),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		anonymous := mqtt.NewClientOptions().AddBroker("tcp://" + container.DefaultAddress())
+		token := mqtt.NewClient(anonymous).Connect()
+		require.True(t, token.WaitTimeout(time.Second*5))
+		require.Error(t, token.Error())
+
+		opts := mqtt.NewClientOptions().
+			AddBroker("tcp://" + container.DefaultAddress()).
+			SetUsername("device").
+			SetPassword("secret")
+		require.Equal(t, "on", receiveRetained(t, opts, "devices/+/state"))
+
+		opts = mqtt.NewClientOptions().
+			AddBroker("ws://" + container.Address(emqx.WebSocketPort) + "/mqtt").
+			SetUsername("device").
+			SetPassword("secret")
+		require.Equal(t, "on", receiveRetained(t, opts, "devices/1/state"))
+	}
+}
+
+func TestPreset_anonymous(t *testing.T) {
+	t.Parallel()
+
+	p := emqx.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	opts := mqtt.NewClientOptions().AddBroker("tcp://" + container.DefaultAddress())
+	token := mqtt.NewClient(opts).Connect()
+	require.True(t, token.WaitTimeout(time.Second*5))
+	require.NoError(t, token.Error())
+}
+
+func receiveRetained(t *testing.T, opts *mqtt.ClientOptions, topic string) string {
+	t.Helper()
+
+	client := mqtt.NewClient(opts)
+
+	token := client.Connect()
+	require.True(t, token.WaitTimeout(time.Second*5))
+	require.NoError(t, token.Error())
+
+	defer client.Disconnect(0)
+
+	messages := make(chan mqtt.Message, 1)
+
+	token = client.Subscribe(topic, 1, func(_ mqtt.Client, m mqtt.Message) { messages <- m })
+	require.True(t, token.WaitTimeout(time.Second*5))
+	require.NoError(t, token.Error())
+
+	select {
+	case m := <-messages:
+		require.True(t, m.Retained())
+
+		return string(m.Payload())
+	case <-time.After(time.Second * 5):
+		require.FailNow(t, "retained message not received")
+	}
+
+	return ""
+}
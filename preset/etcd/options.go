@@ -0,0 +1,28 @@
+package etcd
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version, for example "v3.5.7".
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithKeys puts the provided keys and their values once the container is
+// ready, for example to set up configuration read by the tested code. This
+// option can be used multiple times; later values of the same key replace
+// earlier ones.
+func WithKeys(keys map[string]string) Option {
+	return func(o *P) {
+		if o.Keys == nil {
+			o.Keys = make(map[string]string, len(keys))
+		}
+
+		for k, v := range keys {
+			o.Keys[k] = v
+		}
+	}
+}
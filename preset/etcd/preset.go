@@ -0,0 +1,178 @@
+// Package etcd includes etcd implementation of Gnomock Preset interface. This
+// Preset can be passed to gnomock.Start() function to create a configured
+// single-node etcd cluster to use in tests.
+//
+// The default port accepts v3 API client connections, both gRPC and HTTP
+// gateway requests, for example "/v3/kv/range".
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+const (
+	defaultVersion = "v3.5.7"
+	defaultPort    = 2379
+	clientURL      = "http://0.0.0.0:2379"
+)
+
+func init() {
+	registry.Register("etcd", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock etcd preset. This preset includes an etcd
+// specific healthcheck function and default etcd image and port, and allows
+// to optionally set up initial keys.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for etcd.
+type P struct {
+	Version string            `json:"version"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("quay.io/coreos/etcd:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithCommand(
+			"/usr/local/bin/etcd",
+			"--name=gnomock",
+			"--data-dir=/tmp/etcd",
+			"--listen-client-urls="+clientURL,
+			"--advertise-client-urls="+clientURL,
+		),
+	}
+
+	if len(p.Keys) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// healthcheck makes sure that the member is healthy, which means that it has
+// a leader and serves requests.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	addr := fmt.Sprintf("http://%s/health", c.DefaultAddress())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var health struct {
+		Health string `json:"health"`
+		Reason string `json:"reason"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return fmt.Errorf("can't decode health status (status %d): %w", resp.StatusCode, err)
+	}
+
+	if health.Health != "true" {
+		return fmt.Errorf("unexpected health status: %s %s", health.Health, health.Reason)
+	}
+
+	return nil
+}
+
+// initf puts the configured keys using v3 API HTTP gateway. Keys are put in
+// order, one request per key.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	keys := make([]string, 0, len(p.Keys))
+
+	for k := range p.Keys {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := put(ctx, c, k, p.Keys[k]); err != nil {
+			return fmt.Errorf("can't put key '%s': %w", k, err)
+		}
+	}
+
+	return nil
+}
+
+func put(ctx context.Context, c *gnomock.Container, key, value string) error {
+	body, err := json.Marshal(map[string]string{
+		"key":   base64.StdEncoding.EncodeToString([]byte(key)),
+		"value": base64.StdEncoding.EncodeToString([]byte(value)),
+	})
+	if err != nil {
+		return err
+	}
+
+	addr := fmt.Sprintf("http://%s/v3/kv/put", c.DefaultAddress())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		bs, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("can't read response body: %w", err)
+		}
+
+		return fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, string(bs))
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package etcd_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/etcd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"v3.4.24", "v3.5.7"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := etcd.Preset(
+			etcd.WithVersion(version),
+			etcd.WithKeys(map[string]string{"/config/name": "gnomock"}),
+			etcd.WithKeys(map[string]string{
+				"/config/enabled": "true",
+				"/config/name":    "gnomick",
+			}),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		kvs := get(t, container, "/config/")
+		require.Equal(t, map[string]string{
+			"/config/enabled": "true",
+			"/config/name":    "gnomick",
+		}, kvs)
+	}
+}
+
+func TestPreset_noKeys(t *testing.T) {
+	t.Parallel()
+
+	p := etcd.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+	require.Empty(t, get(t, container, "/"))
+}
+
+// get returns all keys with the provided prefix, and their values.
+func get(t *testing.T, c *gnomock.Container, prefix string) map[string]string {
+	t.Helper()
+
+	rangeEnd := []byte(prefix)
+	rangeEnd[len(rangeEnd)-1]++
+
+	body, err := json.Marshal(map[string]string{
+		"key":       base64.StdEncoding.EncodeToString([]byte(prefix)),
+		"range_end": base64.StdEncoding.EncodeToString(rangeEnd),
+	})
+	require.NoError(t, err)
+
+	addr := fmt.Sprintf("http://%s/v3/kv/range", c.DefaultAddress())
+
+	resp, err := http.Post(addr, "application/json", bytes.NewReader(body)) // nolint:gosec,noctx
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var res struct {
+		KVs []struct {
+			Key   []byte `json:"key"`
+			Value []byte `json:"value"`
+		} `json:"kvs"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+
+	kvs := make(map[string]string, len(res.KVs))
+
+	for _, kv := range res.KVs {
+		kvs[string(kv.Key)] = string(kv.Value)
+	}
+
+	return kvs
+}
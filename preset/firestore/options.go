@@ -0,0 +1,38 @@
+package firestore
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets the version of Google Cloud SDK image, for example
+// "424.0.0". The emulators variant of the image is used.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithProjectID sets the project used by the emulator. Clients must use the
+// same project. If not used, DefaultProjectID is used.
+func WithProjectID(projectID string) Option {
+	return func(o *P) {
+		o.ProjectID = projectID
+	}
+}
+
+// WithFixtures seeds documents from JSON files at the given path:
+//
+//   - path/users.json
+//   - path/orders.json
+//
+// For such directory structure, documents are created in two collections:
+// "users" and "orders". Every file must contain an object, where keys are
+// document IDs, and values are objects with document fields, for example
+// {"alice": {"name": "Alice", "age": 30}}.
+//
+// Files without ".json" extension and directories are ignored.
+func WithFixtures(path string) Option {
+	return func(o *P) {
+		o.FixturesPath = path
+	}
+}
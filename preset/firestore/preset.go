@@ -0,0 +1,268 @@
+// Package firestore includes Google Cloud Firestore emulator implementation
+// of Gnomock Preset interface. This Preset can be passed to gnomock.Start()
+// function to create a configured Firestore emulator container to use in
+// tests.
+//
+// Documents written by the preset bypass security rules, and so do clients
+// configured with EmulatorHostEnv.
+package firestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// EmulatorHostEnv makes firestore.NewClient talk to the emulator listening on
+// the address it holds, usually container.DefaultAddress(). Such clients
+// authorize as an owner, so security rules don't apply to their requests.
+const EmulatorHostEnv = "FIRESTORE_EMULATOR_HOST"
+
+// DefaultProjectID is the project used by the emulator unless a different
+// project is configured using WithProjectID.
+const DefaultProjectID = "gnomock"
+
+const (
+	defaultVersion = "424.0.0"
+	defaultPort    = 8080
+)
+
+func init() {
+	registry.Register("firestore", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Firestore preset. This preset includes a
+// Firestore specific healthcheck function and default Firestore emulator
+// image and port, and allows to optionally seed documents from JSON files.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Firestore emulator.
+type P struct {
+	Version      string `json:"version"`
+	ProjectID    string `json:"project_id"`
+	FixturesPath string `json:"fixtures_path"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("gcr.io/google.com/cloudsdktool/cloud-sdk:%s-emulators", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithCommand(
+			"gcloud", "beta", "emulators", "firestore", "start",
+			"--project="+p.ProjectID,
+			fmt.Sprintf("--host-port=0.0.0.0:%d", defaultPort),
+		),
+	}
+
+	if p.FixturesPath != "" {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.ProjectID == "" {
+		p.ProjectID = DefaultProjectID
+	}
+}
+
+// healthcheck makes sure that the emulator serves HTTP requests. The
+// emulator responds to the root path once it is ready.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	addr := fmt.Sprintf("http://%s/", c.DefaultAddress())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// initf creates documents from every JSON file under the fixtures path. File
+// names, without extension, are used as collection names, and their contents
+// are objects with document IDs as keys and document fields as values.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) (err error) {
+	files, err := os.ReadDir(p.FixturesPath)
+	if err != nil {
+		return fmt.Errorf("can't read fixtures path: %w", err)
+	}
+
+	client, err := firestore.NewClient(
+		ctx, p.ProjectID,
+		option.WithEndpoint(c.DefaultAddress()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		option.WithGRPCDialOption(grpc.WithPerRPCCredentials(emulatorCreds{})),
+	)
+	if err != nil {
+		return fmt.Errorf("can't create firestore client: %w", err)
+	}
+
+	defer func() {
+		closeErr := client.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		collection := strings.TrimSuffix(file.Name(), ".json")
+		fPath := filepath.Join(p.FixturesPath, file.Name())
+
+		if err := createDocuments(ctx, client, collection, fPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createDocuments creates documents described in the provided JSON file in
+// the collection, in sorted order of their IDs. Existing documents are
+// overwritten.
+func createDocuments(ctx context.Context, client *firestore.Client, collection, fPath string) (err error) {
+	f, err := os.Open(fPath) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("can't open file '%s': %w", fPath, err)
+	}
+
+	defer func() {
+		closeErr := f.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	docs, err := readDocuments(f)
+	if err != nil {
+		return fmt.Errorf("can't read documents from '%s': %w", fPath, err)
+	}
+
+	ids := make([]string, 0, len(docs))
+	for id := range docs {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if _, err := client.Collection(collection).Doc(id).Set(ctx, docs[id]); err != nil {
+			return fmt.Errorf("can't create document '%s/%s': %w", collection, id, err)
+		}
+	}
+
+	return nil
+}
+
+// readDocuments decodes documents keeping integer values as int64, so that
+// they are stored as integers instead of doubles.
+func readDocuments(r io.Reader) (map[string]map[string]interface{}, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var docs map[string]map[string]interface{}
+	if err := decoder.Decode(&docs); err != nil {
+		return nil, err
+	}
+
+	for _, doc := range docs {
+		for k, v := range doc {
+			doc[k] = convertNumbers(v)
+		}
+	}
+
+	return docs, nil
+}
+
+func convertNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+
+		f, _ := v.Float64()
+
+		return f
+	case map[string]interface{}:
+		for k, nested := range v {
+			v[k] = convertNumbers(nested)
+		}
+
+		return v
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = convertNumbers(nested)
+		}
+
+		return v
+	default:
+		return v
+	}
+}
+
+// emulatorCreds authorizes requests as an owner, which makes the emulator
+// skip security rules evaluation.
+type emulatorCreds struct{}
+
+func (emulatorCreds) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer owner"}, nil
+}
+
+func (emulatorCreds) RequireTransportSecurity() bool {
+	return false
+}
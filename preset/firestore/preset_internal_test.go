@@ -0,0 +1,31 @@
+package firestore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDocuments(t *testing.T) {
+	t.Parallel()
+
+	input := `{"doc": {"int": 1, "float": 1.5, "list": [2, 2.5], "map": {"int": 3}, "null": null}}`
+
+	docs, err := readDocuments(strings.NewReader(input))
+	require.NoError(t, err)
+
+	expected := map[string]map[string]interface{}{
+		"doc": {
+			"int":   int64(1),
+			"float": 1.5,
+			"list":  []interface{}{int64(2), 2.5},
+			"map":   map[string]interface{}{"int": int64(3)},
+			"null":  nil,
+		},
+	}
+	require.Equal(t, expected, docs)
+
+	_, err = readDocuments(strings.NewReader(`["not", "an", "object"]`))
+	require.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package firestore_test
+
+import (
+	"context"
+	"testing"
+
+	gfirestore "cloud.google.com/go/firestore"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/firestore"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"423.0.0", "424.0.0"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := firestore.Preset(
+			firestore.WithVersion(version),
+			firestore.WithProjectID("project"),
+			firestore.WithFixtures("./testdata"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		client := newClient(ctx, t, container, "project")
+
+		defer func() { require.NoError(t, client.Close()) }()
+
+		doc, err := client.Collection("users").Doc("alice").Get(ctx)
+		require.NoError(t, err)
+
+		data := doc.Data()
+		require.Equal(t, "Alice", data["name"])
+		require.Equal(t, int64(30), data["age"])
+		require.Equal(t, true, data["admin"])
+		require.Equal(t, []interface{}{"founder", "developer"}, data["tags"])
+		require.Equal(t, map[string]interface{}{"city": "Berlin"}, data["address"])
+
+		doc, err = client.Collection("users").Doc("bob").Get(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 25.5, doc.Data()["age"])
+		require.Nil(t, doc.Data()["address"])
+
+		orders, err := client.Collection("orders").Documents(ctx).GetAll()
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+
+		collections := []string{}
+		iter := client.Collections(ctx)
+
+		for {
+			collection, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+
+			require.NoError(t, err)
+
+			collections = append(collections, collection.ID)
+		}
+
+		require.ElementsMatch(t, []string{"orders", "users"}, collections)
+	}
+}
+
+func TestPreset_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	p := firestore.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	client := newClient(ctx, t, container, firestore.DefaultProjectID)
+
+	defer func() { require.NoError(t, client.Close()) }()
+
+	_, err = client.Collection("users").Doc("created").Set(ctx, map[string]interface{}{"name": "foo"})
+	require.NoError(t, err)
+}
+
+func TestPreset_wrongFixturesPath(t *testing.T) {
+	t.Parallel()
+
+	p := firestore.Preset(firestore.WithFixtures("./testdata/missing"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read fixtures path")
+}
+
+func newClient(ctx context.Context, t *testing.T, c *gnomock.Container, projectID string) *gfirestore.Client {
+	t.Helper()
+
+	client, err := gfirestore.NewClient(
+		ctx, projectID,
+		option.WithEndpoint(c.DefaultAddress()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	require.NoError(t, err)
+
+	return client
+}
@@ -0,0 +1,60 @@
+package grafana
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithUser sets admin user credentials. If not used, the default credentials
+// are gnomock:gnomick.
+func WithUser(user, password string) Option {
+	return func(o *P) {
+		o.User = user
+		o.Password = password
+	}
+}
+
+// WithDatasources creates the provided datasources during initial setup. Every
+// datasource is a JSON object accepted by Grafana "POST /api/datasources"
+// endpoint, for example {"name":"prom","type":"prometheus","access":"proxy",
+// "url":"http://172.17.0.1:9090"}. Datasources are created before any
+// dashboards. This option can be used multiple times.
+func WithDatasources(datasources ...string) Option {
+	return func(o *P) {
+		o.Datasources = append(o.Datasources, datasources...)
+	}
+}
+
+// WithDatasourcesFile sets a file name to read a datasource JSON object from.
+// Datasources from files are created before any other datasources provided in
+// WithDatasources. This option can be used multiple times.
+func WithDatasourcesFile(file string) Option {
+	return func(o *P) {
+		o.DatasourcesFiles = append(o.DatasourcesFiles, file)
+	}
+}
+
+// WithDashboards creates the provided dashboards during initial setup. Every
+// dashboard is a JSON model, as exported from Grafana UI or kept in a
+// dashboard-as-code repository. Dashboards with the same uid are overwritten.
+// This option can be used multiple times.
+func WithDashboards(dashboards ...string) Option {
+	return func(o *P) {
+		o.Dashboards = append(o.Dashboards, dashboards...)
+	}
+}
+
+// WithDashboardsFile sets a file name to read a dashboard JSON model from.
+// Dashboards from files are created before any other dashboards provided in
+// WithDashboards. This option can be used multiple times.
+func WithDashboardsFile(file string) Option {
+	return func(o *P) {
+		o.DashboardsFiles = append(o.DashboardsFiles, file)
+	}
+}
@@ -0,0 +1,228 @@
+// Package grafana includes Grafana implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured Grafana container to use in tests.
+//
+// Datasources and dashboards provided using options are created using Grafana
+// HTTP API during initial setup, so invalid definitions fail container
+// startup.
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+const (
+	defaultVersion  = "9.4.7"
+	defaultPort     = 3000
+	defaultUser     = "gnomock"
+	defaultPassword = "gnomick"
+)
+
+func init() {
+	registry.Register("grafana", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Grafana preset. This preset includes a Grafana
+// specific healthcheck function and default Grafana image and port, and
+// allows to optionally provision datasources and dashboards.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Grafana.
+type P struct {
+	Version          string   `json:"version"`
+	User             string   `json:"user"`
+	Password         string   `json:"password"`
+	Datasources      []string `json:"datasources"`
+	DatasourcesFiles []string `json:"datasources_files"`
+	Dashboards       []string `json:"dashboards"`
+	DashboardsFiles  []string `json:"dashboards_files"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/grafana/grafana-oss:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithEnv("GF_SECURITY_ADMIN_USER=" + p.User),
+		gnomock.WithEnv("GF_SECURITY_ADMIN_PASSWORD=" + p.Password),
+		gnomock.WithEnv("GF_ANALYTICS_REPORTING_ENABLED=false"),
+		gnomock.WithEnv("GF_ANALYTICS_CHECK_FOR_UPDATES=false"),
+	}
+
+	if len(p.Datasources) > 0 || len(p.DatasourcesFiles) > 0 ||
+		len(p.Dashboards) > 0 || len(p.DashboardsFiles) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.User == "" && p.Password == "" {
+		p.User = defaultUser
+		p.Password = defaultPassword
+	}
+}
+
+// healthcheck makes sure that the server is up and its database is ready, and
+// that the admin credentials are accepted.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	body, err := p.request(ctx, c, http.MethodGet, "/api/health", nil)
+	if err != nil {
+		return err
+	}
+
+	var health struct {
+		Database string `json:"database"`
+	}
+
+	if err := json.Unmarshal(body, &health); err != nil {
+		return fmt.Errorf("can't decode health status: %w", err)
+	}
+
+	if health.Database != "ok" {
+		return fmt.Errorf("unexpected database status: %s", health.Database)
+	}
+
+	_, err = p.request(ctx, c, http.MethodGet, "/api/org", nil)
+
+	return err
+}
+
+// initf creates datasources before dashboards, so that the dashboards can
+// refer to them. Definitions from files are created before the rest of the
+// definitions of the same kind.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	datasources, err := readAll(p.DatasourcesFiles, p.Datasources, "datasource")
+	if err != nil {
+		return err
+	}
+
+	for i, ds := range datasources {
+		if !json.Valid([]byte(ds)) {
+			return fmt.Errorf("datasource %d of %d is not valid json", i+1, len(datasources))
+		}
+
+		if _, err := p.request(ctx, c, http.MethodPost, "/api/datasources", []byte(ds)); err != nil {
+			return fmt.Errorf("can't create datasource %d of %d: %w", i+1, len(datasources), err)
+		}
+	}
+
+	dashboards, err := readAll(p.DashboardsFiles, p.Dashboards, "dashboard")
+	if err != nil {
+		return err
+	}
+
+	for i, d := range dashboards {
+		body, err := dashboardRequest(d)
+		if err != nil {
+			return fmt.Errorf("dashboard %d of %d is not valid: %w", i+1, len(dashboards), err)
+		}
+
+		if _, err := p.request(ctx, c, http.MethodPost, "/api/dashboards/db", body); err != nil {
+			return fmt.Errorf("can't create dashboard %d of %d: %w", i+1, len(dashboards), err)
+		}
+	}
+
+	return nil
+}
+
+// dashboardRequest wraps the provided dashboard model into a request to
+// create it. Exported dashboards may include an internal id, which would make
+// Grafana look for an existing dashboard, so it is removed.
+func dashboardRequest(dashboard string) ([]byte, error) {
+	var model map[string]interface{}
+
+	if err := json.Unmarshal([]byte(dashboard), &model); err != nil {
+		return nil, err
+	}
+
+	delete(model, "id")
+
+	return json.Marshal(map[string]interface{}{
+		"dashboard": model,
+		"overwrite": true,
+	})
+}
+
+// readAll returns the contents of the provided files followed by the rest of
+// the provided definitions.
+func readAll(files, definitions []string, kind string) ([]string, error) {
+	all := make([]string, 0, len(files)+len(definitions))
+
+	for _, f := range files {
+		bs, err := os.ReadFile(f) // nolint:gosec
+		if err != nil {
+			return nil, fmt.Errorf("can't read %s file '%s': %w", kind, f, err)
+		}
+
+		all = append(all, string(bs))
+	}
+
+	return append(all, definitions...), nil
+}
+
+// request sends an authenticated request to Grafana HTTP API, and returns the
+// response body.
+func (p *P) request(ctx context.Context, c *gnomock.Container, method, path string, body []byte) ([]byte, error) {
+	addr := fmt.Sprintf("http://%s%s", c.DefaultAddress(), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(p.User, p.Password)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, strings.TrimSpace(string(bs)))
+	}
+
+	return bs, nil
+}
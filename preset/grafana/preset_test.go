@@ -0,0 +1,100 @@
+package grafana_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/grafana"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"8.5.22", "9.4.7"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := grafana.Preset(
+			grafana.WithVersion(version),
+			grafana.WithUser("admin", "secret"),
+			grafana.WithDatasourcesFile("./testdata/datasource.json"),
+			grafana.WithDatasources(`{"name":"loki","type":"loki","access":"proxy","url":"http://localhost:3100"}`),
+			grafana.WithDashboardsFile("./testdata/dashboard.json"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		var datasources []struct {
+			Name string `json:"name"`
+		}
+
+		get(t, container, "/api/datasources", &datasources)
+
+		names := make([]string, 0, len(datasources))
+		for _, ds := range datasources {
+			names = append(names, ds.Name)
+		}
+
+		require.ElementsMatch(t, []string{"prometheus", "loki"}, names)
+
+		var dashboard struct {
+			Dashboard struct {
+				Title string `json:"title"`
+			} `json:"dashboard"`
+		}
+
+		get(t, container, "/api/dashboards/uid/gnomock", &dashboard)
+		require.Equal(t, "Gnomock", dashboard.Dashboard.Title)
+	}
+}
+
+func TestPreset_invalidDashboard(t *testing.T) {
+	t.Parallel()
+
+	p := grafana.Preset(grafana.WithDashboards(`{"title":`))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dashboard 1 of 1 is not valid")
+}
+
+func TestPreset_wrongDatasourcesFile(t *testing.T) {
+	t.Parallel()
+
+	p := grafana.Preset(grafana.WithDatasourcesFile("./testdata/missing.json"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read datasource file")
+}
+
+func get(t *testing.T, c *gnomock.Container, path string, v interface{}) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s%s", c.DefaultAddress(), path), nil) // nolint:noctx
+	require.NoError(t, err)
+
+	req.SetBasicAuth("admin", "secret")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
@@ -40,3 +40,23 @@ func WithAuthToken(token string) Option {
 		p.AuthToken = token
 	}
 }
+
+// WithLines writes the provided points in line protocol, for example
+// "cpu,host=a usage=0.5 1672531200000000000", into the initial bucket during
+// initial setup. Timestamps use nanosecond precision. This option can be used
+// multiple times.
+func WithLines(lines ...string) Option {
+	return func(p *P) {
+		p.Lines = append(p.Lines, lines...)
+	}
+}
+
+// WithLinesFile sets a file name to read initial points in line protocol
+// from, one point per line. Empty lines and lines starting with "#" are
+// ignored. Points from files are written before the points provided using
+// WithLines. This option can be used multiple times.
+func WithLinesFile(file string) Option {
+	return func(p *P) {
+		p.LinesFiles = append(p.LinesFiles, file)
+	}
+}
@@ -9,6 +9,8 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
 	"github.com/influxdata/influxdb-client-go/v2/domain"
@@ -32,6 +34,10 @@ func init() {
 
 // Preset creates a new Gmomock InfluxDB preset. This preset includes a
 // InfluxDB specific healthcheck function and default InfluxDB image and port.
+//
+// Initial setup creates the organization, the bucket and the super-user, and
+// uses the provided admin token, or a default one, which can be retrieved
+// using AuthToken. The bucket can be seeded with points in line protocol.
 func Preset(opts ...Option) gnomock.Preset {
 	p := &P{}
 
@@ -44,12 +50,14 @@ func Preset(opts ...Option) gnomock.Preset {
 
 // P is a Gnomock Preset implementation for InfluxDB.
 type P struct {
-	Version   string `json:"version"`
-	Username  string `json:"username"`
-	Password  string `json:"password"`
-	Org       string `json:"org"`
-	Bucket    string `json:"bucket"`
-	AuthToken string `json:"auth_token"`
+	Version    string   `json:"version"`
+	Username   string   `json:"username"`
+	Password   string   `json:"password"`
+	Org        string   `json:"org"`
+	Bucket     string   `json:"bucket"`
+	AuthToken  string   `json:"auth_token"`
+	Lines      []string `json:"lines"`
+	LinesFiles []string `json:"lines_files"`
 }
 
 // Image returns an image that should be pulled to create this container.
@@ -76,6 +84,10 @@ func (p *P) Options() []gnomock.Option {
 		gnomock.WithEnv("DOCKER_INFLUXDB_INIT_ADMIN_TOKEN=" + p.AuthToken),
 	}
 
+	if len(p.Lines) > 0 || len(p.LinesFiles) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
 	return opts
 }
 
@@ -131,3 +143,50 @@ func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
 
 	return nil
 }
+
+// initf writes points from lines files, followed by the rest of the lines,
+// into the initial bucket.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	lines := make([]string, 0, len(p.Lines))
+
+	for _, f := range p.LinesFiles {
+		bs, err := os.ReadFile(f) // nolint:gosec
+		if err != nil {
+			return fmt.Errorf("can't read lines file '%s': %w", f, err)
+		}
+
+		for _, line := range strings.Split(string(bs), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	lines = append(lines, p.Lines...)
+
+	addr := fmt.Sprintf("http://%s", c.DefaultAddress())
+	client := influxdb2.NewClient(addr, p.AuthToken)
+
+	defer client.Close()
+
+	if err := client.WriteAPIBlocking(p.Org, p.Bucket).WriteRecord(ctx, lines...); err != nil {
+		return fmt.Errorf("can't write lines: %w", err)
+	}
+
+	return nil
+}
+
+// AuthToken returns the admin token of a container created using the same
+// options, which is either the token provided using WithAuthToken, or the
+// default one.
+func AuthToken(opts ...Option) string {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.setDefaults()
+
+	return p.AuthToken
+}
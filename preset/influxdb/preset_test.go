@@ -98,3 +98,43 @@ func testPreset(version string, useDefaults bool) func(t *testing.T) {
 		require.Contains(t, orgNames, org)
 	}
 }
+
+func TestPreset_withLines(t *testing.T) {
+	t.Parallel()
+
+	opts := []influxdb.Option{
+		influxdb.WithLinesFile("./testdata/points.lp"),
+		influxdb.WithLines("cpu,host=c usage=0.9 1672531200000000000"),
+	}
+
+	container, err := gnomock.Start(influxdb.Preset(opts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	addr := fmt.Sprintf("http://%s", container.DefaultAddress())
+	client := influxdb2.NewClient(addr, influxdb.AuthToken(opts...))
+
+	defer client.Close()
+
+	result, err := client.QueryAPI("gnomock-org").Query(context.Background(), `
+		from(bucket: "gnomock-bucket")
+			|> range(start: 2023-01-01T00:00:00Z, stop: 2023-01-02T00:00:00Z)
+			|> filter(fn: (r) => r._measurement == "cpu")
+			|> group()
+			|> count()
+	`)
+	require.NoError(t, err)
+
+	require.True(t, result.Next())
+	require.Equal(t, int64(3), result.Record().Value())
+	require.NoError(t, result.Err())
+}
+
+func TestAuthToken(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "gnomock-influxdb-token", influxdb.AuthToken())
+	require.Equal(t, "token", influxdb.AuthToken(influxdb.WithAuthToken("token")))
+}
@@ -0,0 +1,114 @@
+// Package jaeger includes Jaeger all-in-one implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured Jaeger container to use in tests.
+//
+// The default port serves Jaeger query API and UI, for example
+// "/api/traces?service=<name>", which can be used to make sure that the spans
+// sent by the code under test arrive. Spans can be sent using OTLP over gRPC
+// or HTTP, or using Thrift over HTTP or UDP, using the named ports.
+package jaeger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// Named ports exposed by Jaeger containers in addition to the default query
+// API port.
+const (
+	OTLPGRPCPort   = "otlp-grpc"
+	OTLPHTTPPort   = "otlp-http"
+	ThriftHTTPPort = "thrift-http"
+	AgentPort      = "agent"
+)
+
+const (
+	defaultVersion = "1.43"
+	defaultPort    = 16686
+	otlpGRPCPort   = 4317
+	otlpHTTPPort   = 4318
+	thriftHTTPPort = 14268
+	agentPort      = 6831
+)
+
+func init() {
+	registry.Register("jaeger", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Jaeger preset. This preset includes a Jaeger
+// specific healthcheck function and default Jaeger image and ports.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Jaeger.
+type P struct {
+	Version string `json:"version"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/jaegertracing/all-in-one:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[OTLPGRPCPort] = gnomock.Port{Protocol: "tcp", Port: otlpGRPCPort}
+	namedPorts[OTLPHTTPPort] = gnomock.Port{Protocol: "tcp", Port: otlpHTTPPort}
+	namedPorts[ThriftHTTPPort] = gnomock.Port{Protocol: "tcp", Port: thriftHTTPPort}
+	namedPorts[AgentPort] = gnomock.Port{Protocol: "udp", Port: agentPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	return []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		// older versions only accept OTLP when explicitly enabled
+		gnomock.WithEnv("COLLECTOR_OTLP_ENABLED=true"),
+	}
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// healthcheck makes sure that the query API responds, which happens after the
+// storage and the collector are ready.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	addr := fmt.Sprintf("http://%s/api/services", c.DefaultAddress())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package jaeger_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/jaeger"
+	"github.com/stretchr/testify/require"
+)
+
+// span is a minimal OTLP/JSON export request with a single span.
+const span = `{
+  "resourceSpans": [{
+    "resource": {
+      "attributes": [{"key": "service.name", "value": {"stringValue": "gnomock"}}]
+    },
+    "scopeSpans": [{
+      "spans": [{
+        "traceId": "5b8efff798038103d269b633813fc60c",
+        "spanId": "eee19b7ec3c1b174",
+        "name": "test",
+        "kind": 1,
+        "startTimeUnixNano": "%d",
+        "endTimeUnixNano": "%d"
+      }]
+    }]
+  }]
+}`
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"1.41", "1.43"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := jaeger.Preset(jaeger.WithVersion(version))
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		now := time.Now()
+		body := fmt.Sprintf(span, now.UnixNano(), now.Add(time.Millisecond).UnixNano())
+		addr := fmt.Sprintf("http://%s/v1/traces", container.Address(jaeger.OTLPHTTPPort))
+
+		resp, err := http.Post(addr, "application/json", strings.NewReader(body)) // nolint:gosec,noctx
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		addr = fmt.Sprintf("http://%s/api/traces?service=gnomock", container.DefaultAddress())
+
+		require.Eventually(t, func() bool {
+			resp, err := http.Get(addr) // nolint:gosec,noctx
+			if err != nil {
+				return false
+			}
+
+			defer func() { _ = resp.Body.Close() }()
+
+			var result struct {
+				Data []struct {
+					TraceID string `json:"traceID"`
+				} `json:"data"`
+			}
+
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return false
+			}
+
+			return len(result.Data) == 1 && result.Data[0].TraceID == "5b8efff798038103d269b633813fc60c"
+		}, time.Second*10, time.Millisecond*250)
+	}
+}
@@ -0,0 +1,249 @@
+package k3s
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/orlangure/gnomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// imagesDir is a directory k3s imports image archives from when it starts.
+const imagesDir = "/var/lib/rancher/k3s/agent/images"
+
+// imageArchive is a docker image archive mounted into the container, and the
+// images it includes.
+type imageArchive struct {
+	path string
+	tags []string
+	temp bool
+}
+
+// imageMounts prepares image archives for every configured image, and returns
+// the options to mount them into the container. Local images are saved into
+// temporary archives using docker engine of the host. Any error is kept until
+// the container is initialized, since options can't fail.
+func (p *P) imageMounts() []gnomock.Option {
+	opts := make([]gnomock.Option, 0, len(p.Images))
+	p.archives, p.imagesErr = nil, nil
+
+	for i, image := range p.Images {
+		archive, err := prepareImageArchive(image)
+		if err != nil {
+			p.imagesErr = fmt.Errorf("can't prepare image '%s': %w", image, err)
+			return nil
+		}
+
+		p.archives = append(p.archives, archive)
+		dst := fmt.Sprintf("%s/gnomock-%d.tar", imagesDir, i)
+		opts = append(opts, gnomock.WithHostMounts(archive.path, dst))
+	}
+
+	return opts
+}
+
+func prepareImageArchive(image string) (*imageArchive, error) {
+	if _, err := os.Stat(image); err == nil {
+		path, err := filepath.Abs(image)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive path: %w", err)
+		}
+
+		tags, err := archiveTags(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return &imageArchive{path: path, tags: tags}, nil
+	}
+
+	path, err := saveLocalImage(image)
+	if err != nil {
+		return nil, err
+	}
+
+	return &imageArchive{path: path, tags: []string{image}, temp: true}, nil
+}
+
+func saveLocalImage(image string) (path string, err error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return "", fmt.Errorf("can't connect to docker: %w", err)
+	}
+
+	defer func() { _ = cli.Close() }()
+
+	r, err := cli.ImageSave(context.Background(), []string{image})
+	if err != nil {
+		return "", fmt.Errorf("can't save image: %w", err)
+	}
+
+	defer func() { _ = r.Close() }()
+
+	f, err := os.CreateTemp("", "gnomock-k3s-*.tar")
+	if err != nil {
+		return "", fmt.Errorf("can't create image archive: %w", err)
+	}
+
+	defer func() {
+		closeErr := f.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return "", fmt.Errorf("can't write image archive: %w", err)
+	}
+
+	return f.Name(), nil
+}
+
+// archiveTags returns image tags included in docker image archive.
+func archiveTags(path string) ([]string, error) {
+	f, err := os.Open(path) // nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("can't open image archive: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	tr := tar.NewReader(f)
+
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("manifest.json not found in '%s'", path)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("can't read image archive: %w", err)
+		}
+
+		if h.Name != "manifest.json" {
+			continue
+		}
+
+		var manifest []struct {
+			RepoTags []string `json:"RepoTags"`
+		}
+
+		if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+			return nil, fmt.Errorf("can't read image archive manifest: %w", err)
+		}
+
+		tags := []string{}
+
+		for _, m := range manifest {
+			tags = append(tags, m.RepoTags...)
+		}
+
+		return tags, nil
+	}
+}
+
+// waitForImages returns when all the images are imported into the cluster,
+// and removes temporary image archives.
+func (p *P) waitForImages(ctx context.Context, c *gnomock.Container) error {
+	if p.imagesErr != nil {
+		return p.imagesErr
+	}
+
+	defer func() {
+		for _, archive := range p.archives {
+			if archive.temp {
+				_ = os.Remove(archive.path)
+			}
+		}
+	}()
+
+	kubeconfig, err := Config(c)
+	if err != nil {
+		return fmt.Errorf("failed to get kubeconfig: %w", err)
+	}
+
+	// see healthcheck
+	kubeconfig.Host = c.DefaultAddress()
+
+	client, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client from kubeconfig: %w", err)
+	}
+
+	var missing string
+
+	for {
+		missing, err = p.missingImage(ctx, client)
+		if err == nil && missing == "" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("image '%s' is not imported (last error: %v): %w", missing, err, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// missingImage returns the first image that is not yet listed by the cluster
+// node.
+func (p *P) missingImage(ctx context.Context, client *kubernetes.Clientset) (string, error) {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list cluster nodes: %w", err)
+	}
+
+	names := []string{}
+
+	for _, node := range nodes.Items {
+		for _, image := range node.Status.Images {
+			names = append(names, image.Names...)
+		}
+	}
+
+	for _, archive := range p.archives {
+		for _, tag := range archive.tags {
+			if !containsImage(names, tag) {
+				return tag, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// containsImage reports whether the provided image is one of the names,
+// which are fully qualified image references, like
+// "docker.io/library/alpine:3".
+func containsImage(names []string, image string) bool {
+	if !strings.Contains(image, "/") {
+		image = "library/" + image
+	}
+
+	if !strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") {
+		image += ":latest"
+	}
+
+	for _, name := range names {
+		if name == image || strings.HasSuffix(name, "/"+image) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,7 +4,10 @@ package k3s
 // Options to configure the container.
 type Option func(*P)
 
-// WithVersion sets image version.
+// WithVersion sets image version, which is the version of Kubernetes API
+// served by the cluster, for example "v1.21.1". Available versions are listed
+// at https://hub.docker.com/r/orlangure/k3s/tags. Versions without "v" prefix,
+// like "1.21.1", are accepted as well.
 func WithVersion(version string) Option {
 	return func(o *P) {
 		o.Version = version
@@ -22,3 +25,18 @@ func WithPort(port int) Option {
 		o.Port = port
 	}
 }
+
+// WithImages imports the provided images into the cluster when it starts, so
+// that workloads using them can be deployed without pulling the images from a
+// registry. Every image is either a path to an image archive created by
+// `docker save`, or a name of an image available in the local docker engine,
+// for example "my-operator:dev". Local images are saved into temporary
+// archives before the container starts.
+//
+// Pods using imported images should use "IfNotPresent" or "Never" image pull
+// policy, since images tagged "latest" are always pulled by default.
+func WithImages(images ...string) Option {
+	return func(o *P) {
+		o.Images = append(o.Images, images...)
+	}
+}
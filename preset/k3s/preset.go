@@ -83,6 +83,10 @@ func Preset(opts ...Option) gnomock.Preset {
 type P struct {
 	Version string `json:"version"`
 	Port    int
+	Images  []string `json:"images"`
+
+	archives  []*imageArchive
+	imagesErr error
 }
 
 // Image returns an image that should be pulled to create this container.
@@ -112,6 +116,11 @@ func (p *P) Options() []gnomock.Option {
 		gnomock.WithEnv(fmt.Sprintf("K3S_API_PORT=%d", p.Port)),
 	}
 
+	if len(p.Images) > 0 {
+		opts = append(opts, p.imageMounts()...)
+		opts = append(opts, gnomock.WithInit(p.waitForImages))
+	}
+
 	return opts
 }
 
@@ -158,6 +167,11 @@ func (p *P) setDefaults() {
 		p.Version = defaultVersion
 	}
 
+	// image tags follow kubernetes release names, which start with "v"
+	if p.Version[0] >= '0' && p.Version[0] <= '9' {
+		p.Version = "v" + p.Version
+	}
+
 	if p.Port == 0 {
 		p.Port = defaultPort
 	}
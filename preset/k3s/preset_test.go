@@ -2,7 +2,12 @@ package k3s_test
 
 import (
 	"context"
+	"io"
 	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	dockerclient "github.com/docker/docker/client"
 
 	"github.com/orlangure/gnomock"
 	"github.com/orlangure/gnomock/preset/k3s"
@@ -122,3 +127,77 @@ func TestConfigBytes(t *testing.T) {
 		require.Contains(t, err.Error(), "connection refused")
 	})
 }
+
+func TestPreset_versionPrefix(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"v1.21.1", "1.21.1"} {
+		p := k3s.Preset(k3s.WithVersion(version))
+		_ = p.Options()
+		require.Equal(t, "docker.io/orlangure/k3s:v1.21.1", p.Image())
+	}
+
+	p := k3s.Preset()
+	_ = p.Options()
+	require.Equal(t, "docker.io/orlangure/k3s:latest", p.Image())
+}
+
+func TestPreset_withImages(t *testing.T) {
+	t.Parallel()
+
+	const image = "docker.io/orlangure/gnomock-test-image:latest"
+
+	ctx := context.Background()
+
+	cli, err := dockerclient.NewClientWithOpts(dockerclient.FromEnv, dockerclient.WithAPIVersionNegotiation())
+	require.NoError(t, err)
+
+	r, err := cli.ImagePull(ctx, image, types.ImagePullOptions{})
+	require.NoError(t, err)
+	_, err = io.Copy(io.Discard, r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.NoError(t, cli.Close())
+
+	p := k3s.Preset(
+		k3s.WithPort(48449),
+		k3s.WithImages(image),
+	)
+	c, err := gnomock.Start(p, gnomock.WithContainerName("k3s-images"))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, gnomock.Stop(c))
+	}()
+
+	kubeconfig, err := k3s.Config(c)
+	require.NoError(t, err)
+
+	client, err := kubernetes.NewForConfig(kubeconfig)
+	require.NoError(t, err)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gnomock-local",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:            "gnomock",
+					Image:           image,
+					ImagePullPolicy: v1.PullNever,
+				},
+			},
+			RestartPolicy: v1.RestartPolicyNever,
+		},
+	}
+
+	_, err = client.CoreV1().Pods(metav1.NamespaceDefault).Create(ctx, pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		pod, err := client.CoreV1().Pods(metav1.NamespaceDefault).Get(ctx, "gnomock-local", metav1.GetOptions{})
+		return err == nil && pod.Status.Phase == v1.PodRunning
+	}, time.Minute, time.Second)
+}
@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/orlangure/gnomock"
+)
+
+const (
+	defaultKRaftVersion = "3.4.0"
+	controllerPort      = 49093
+)
+
+// kraftOptions configure a single node acting as both broker and controller,
+// using bitnami image environment variables.
+func (p *P) kraftOptions() []gnomock.Option {
+	return []gnomock.Option{
+		gnomock.WithEnv("ALLOW_PLAINTEXT_LISTENER=yes"),
+		gnomock.WithEnv("KAFKA_ENABLE_KRAFT=yes"),
+		gnomock.WithEnv("KAFKA_CFG_NODE_ID=1"),
+		gnomock.WithEnv("KAFKA_CFG_BROKER_ID=1"),
+		gnomock.WithEnv("KAFKA_CFG_PROCESS_ROLES=broker,controller"),
+		gnomock.WithEnv("KAFKA_CFG_CONTROLLER_LISTENER_NAMES=CONTROLLER"),
+		gnomock.WithEnv(fmt.Sprintf(
+			"KAFKA_CFG_LISTENERS=PLAINTEXT://:%d,CONTROLLER://:%d", brokerPort, controllerPort,
+		)),
+		gnomock.WithEnv(fmt.Sprintf("KAFKA_CFG_ADVERTISED_LISTENERS=PLAINTEXT://127.0.0.1:%d", brokerPort)),
+		gnomock.WithEnv("KAFKA_CFG_LISTENER_SECURITY_PROTOCOL_MAP=CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT"),
+		gnomock.WithEnv(fmt.Sprintf("KAFKA_CFG_CONTROLLER_QUORUM_VOTERS=1@127.0.0.1:%d", controllerPort)),
+		gnomock.WithEnv("KAFKA_CFG_AUTO_CREATE_TOPICS_ENABLE=true"),
+		gnomock.WithEnv("KAFKA_CFG_OFFSETS_TOPIC_REPLICATION_FACTOR=1"),
+		gnomock.WithEnv("KAFKA_CFG_TRANSACTION_STATE_LOG_REPLICATION_FACTOR=1"),
+		gnomock.WithEnv("KAFKA_CFG_TRANSACTION_STATE_LOG_MIN_ISR=1"),
+	}
+}
+
+// validateKRaft reports options that are not supported in KRaft mode, since
+// bitnami image doesn't run schema registry, and its listener is always
+// configured as plaintext.
+func (p *P) validateKRaft() error {
+	switch {
+	case p.UseSchemaRegistry:
+		return errors.New("schema registry is not supported in kraft mode")
+	case p.SASLMechanism != "":
+		return errors.New("sasl is not supported in kraft mode")
+	case p.TLSCert != "":
+		return errors.New("tls is not supported in kraft mode")
+	default:
+		return nil
+	}
+}
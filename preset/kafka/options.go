@@ -1,5 +1,11 @@
 package kafka
 
+import (
+	"fmt"
+
+	"github.com/orlangure/gnomock/internal/tlsutil"
+)
+
 // Option is an optional configuration of this Gnomock preset. Use available
 // Options to configure the container.
 type Option func(*P)
@@ -20,7 +26,9 @@ func WithTopics(topics ...string) Option {
 }
 
 // WithMessages makes sure that these messages can be consumed during the test
-// once the container is ready.
+// once the container is ready. Messages are produced during initial setup
+// with their keys, values and headers, and topics that don't exist yet are
+// created automatically.
 func WithMessages(messages ...Message) Option {
 	return func(o *P) {
 		o.Messages = append(o.Messages, messages...)
@@ -35,12 +43,84 @@ func WithMessagesFile(files string) Option {
 	}
 }
 
-// WithSchemaRegistry makes the container wait for the schema registry port to
-// become available. Note that it takes longer to setup schema registry than
-// the broker itself. Gnomock will not wait for the registry by default, but it
-// may become available eventually.
+// WithSchemaRegistry makes the container wait for the schema registry to
+// become available and serve requests. Note that it takes longer to setup
+// schema registry than the broker itself. Gnomock will not wait for the
+// registry by default, but it may become available eventually.
+//
+// Schema registry runs in the same container as the broker, and is available
+// at SchemaRegistryPort named port, for example
+// "http://" + container.Address(kafka.SchemaRegistryPort).
 func WithSchemaRegistry() Option {
 	return func(o *P) {
 		o.UseSchemaRegistry = true
 	}
 }
+
+// WithTopicConfigs makes sure that the provided topics are available when
+// Kafka is up and running, and that they are created with the provided number
+// of partitions, replication factor and configuration entries. Unlike
+// WithTopics, this option doesn't rely on default broker settings.
+func WithTopicConfigs(topics ...TopicConfig) Option {
+	return func(o *P) {
+		o.TopicConfigs = append(o.TopicConfigs, topics...)
+	}
+}
+
+// WithSASL enables SASL authentication of the broker listener using one of
+// SASLPlain, SASLScramSHA256 or SASLScramSHA512 mechanisms. The broker
+// accepts all of them, and Gnomock uses the provided one to connect. The
+// user and its random password are generated when this option is created,
+// and are available using Credentials function. Use Dialer function to get a
+// kafka-go dialer configured with the same credentials.
+//
+// Schema registry and other services of the image don't support
+// authentication, so this option can't be combined with WithSchemaRegistry.
+func WithSASL(mechanism string) Option {
+	password, err := randomPassword()
+
+	return func(o *P) {
+		o.SASLMechanism = mechanism
+		o.SASLUser = defaultSASLUser
+		o.SASLPassword = password
+
+		if err != nil {
+			o.securityErr = fmt.Errorf("can't generate sasl password: %w", err)
+		}
+	}
+}
+
+// WithTLS enables TLS on the broker listener using a self-signed certificate
+// generated when this option is created. The certificate is valid for
+// "localhost" and "127.0.0.1", and is available using CACert function. It can
+// be combined with WithSASL to use SASL_SSL security protocol. Use Dialer
+// function to get a kafka-go dialer that trusts this certificate.
+//
+// Schema registry and other services of the image don't support TLS, so this
+// option can't be combined with WithSchemaRegistry.
+func WithTLS() Option {
+	certPEM, keyPEM, err := tlsutil.SelfSignedCert()
+
+	return func(o *P) {
+		o.TLSCert = string(certPEM)
+		o.TLSKey = string(keyPEM)
+
+		if err != nil {
+			o.securityErr = fmt.Errorf("can't generate tls certificate: %w", err)
+		}
+	}
+}
+
+// WithKRaft runs a single Kafka node in KRaft mode, without ZooKeeper, using
+// bitnami/kafka image. The node acts as both broker and controller, and
+// starts faster than the default image. When this option is used, WithVersion
+// sets the version of bitnami/kafka image; by default, version 3.4.0 is used.
+//
+// Only BrokerPort is available in KRaft mode. WithSchemaRegistry, WithSASL
+// and WithTLS options are not supported: the container fails to start if
+// any of them is used together with this option.
+func WithKRaft() Option {
+	return func(o *P) {
+		o.KRaft = true
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/failinit"
 	"github.com/orlangure/gnomock/internal/registry"
 	"github.com/segmentio/kafka-go"
 )
@@ -32,12 +33,30 @@ const (
 	schemaRegistryPort = 8081
 )
 
-// Message is a single message sent to Kafka.
+// Message is a single message sent to Kafka. Time is a unix timestamp in
+// nanoseconds; current time is used when it is not set.
 type Message struct {
-	Topic string `json:"topic"`
-	Key   string `json:"key"`
-	Value string `json:"value"`
-	Time  int64  `json:"time"`
+	Topic   string            `json:"topic"`
+	Key     string            `json:"key"`
+	Value   string            `json:"value"`
+	Time    int64             `json:"time"`
+	Headers map[string]string `json:"headers"`
+}
+
+// TopicConfig is a topic created during initial setup, with custom settings.
+type TopicConfig struct {
+	Name string `json:"name"`
+
+	// Partitions is the number of partitions of the topic, 1 by default.
+	Partitions int `json:"partitions"`
+
+	// ReplicationFactor of the topic, 1 by default. Since there is only one
+	// broker, it can't be greater than 1.
+	ReplicationFactor int `json:"replication_factor"`
+
+	// Configs are topic level configuration entries, such as
+	// "cleanup.policy" or "retention.ms".
+	Configs map[string]string `json:"configs"`
 }
 
 func init() {
@@ -70,10 +89,28 @@ type P struct {
 	Messages          []Message `json:"messages"`
 	MessagesFiles     []string  `json:"messages_files"`
 	UseSchemaRegistry bool      `json:"use_schema_registry"`
+
+	TopicConfigs []TopicConfig `json:"topic_configs"`
+
+	SASLMechanism string `json:"sasl_mechanism"`
+	SASLUser      string `json:"sasl_user"`
+	SASLPassword  string `json:"sasl_password"`
+	TLSCert       string `json:"tls_cert"`
+	TLSKey        string `json:"tls_key"`
+
+	KRaft bool `json:"kraft"`
+
+	// securityErr is set when options fail to generate credentials or
+	// certificates, and is reported during initial setup
+	securityErr error
 }
 
 // Image returns an image that should be pulled to create this container.
 func (p *P) Image() string {
+	if p.KRaft {
+		return fmt.Sprintf("docker.io/bitnami/kafka:%s", p.Version)
+	}
+
 	return fmt.Sprintf("docker.io/lensesio/fast-data-dev:%s", p.Version)
 }
 
@@ -85,6 +122,10 @@ func (p *P) Ports() gnomock.NamedPorts {
 	bp.HostPort = brokerPort
 	namedPorts[BrokerPort] = bp
 
+	if p.KRaft {
+		return namedPorts
+	}
+
 	namedPorts[ZooKeeperPort] = gnomock.TCP(zookeeperPort)
 	namedPorts[WebPort] = gnomock.TCP(webPort)
 	namedPorts[SchemaRegistryPort] = gnomock.TCP(schemaRegistryPort)
@@ -96,6 +137,20 @@ func (p *P) Ports() gnomock.NamedPorts {
 func (p *P) Options() []gnomock.Option {
 	p.setDefaults()
 
+	if err := p.validate(); err != nil {
+		return []gnomock.Option{gnomock.WithInit(failinit.Func(err))}
+	}
+
+	if p.KRaft {
+		opts := append(p.kraftOptions(), gnomock.WithHealthCheck(p.healthcheck))
+
+		if p.needsInit() {
+			opts = append(opts, gnomock.WithInit(p.initf))
+		}
+
+		return opts
+	}
+
 	opts := []gnomock.Option{
 		gnomock.WithHealthCheck(p.healthcheck),
 		gnomock.WithEnv("KAFKA_AUTO_CREATE_TOPICS_ENABLE=true"),
@@ -106,13 +161,33 @@ func (p *P) Options() []gnomock.Option {
 		gnomock.WithEnv("SAMPLEDATA=0"),
 	}
 
-	if len(p.Topics) > 0 || len(p.Messages) > 0 {
+	if p.needsInit() {
 		opts = append(opts, gnomock.WithInit(p.initf))
 	}
 
+	opts = append(opts, p.securityOptions()...)
+
 	return opts
 }
 
+// validate reports option combinations that can't be used to start the
+// container.
+func (p *P) validate() error {
+	if err := p.validateSecurity(); err != nil {
+		return err
+	}
+
+	if p.KRaft {
+		return p.validateKRaft()
+	}
+
+	return nil
+}
+
+func (p *P) needsInit() bool {
+	return len(p.Topics) > 0 || len(p.Messages) > 0 || len(p.MessagesFiles) > 0 || len(p.TopicConfigs) > 0
+}
+
 func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) (err error) {
 	conn, err := p.connect(c)
 	if err != nil {
@@ -139,7 +214,7 @@ func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) (err error) {
 	}
 
 	if p.UseSchemaRegistry {
-		url := "http://" + c.Address(SchemaRegistryPort)
+		url := "http://" + c.Address(SchemaRegistryPort) + "/subjects"
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
@@ -154,6 +229,11 @@ func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) (err error) {
 		if err := res.Body.Close(); err != nil {
 			return fmt.Errorf("error closing schema registry response body: %w", err)
 		}
+
+		// registry accepts connections before it can serve requests
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("schema registry is not ready: status %d", res.StatusCode)
+		}
 	}
 
 	return nil
@@ -162,6 +242,15 @@ func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) (err error) {
 func (p *P) setDefaults() {
 	if p.Version == "" {
 		p.Version = defaultVersion
+
+		if p.KRaft {
+			p.Version = defaultKRaftVersion
+		}
+	}
+
+	if p.SASLMechanism != "" && p.SASLUser == "" {
+		p.SASLUser = defaultSASLUser
+		p.SASLPassword = defaultSASLPassword
 	}
 }
 
@@ -203,17 +292,7 @@ func (p *P) ingestMessageFiles(ctx context.Context, c *gnomock.Container, conn *
 		p.Topics = append(p.Topics, topic)
 	}
 
-	topics := make([]kafka.TopicConfig, 0, len(p.Topics))
-
-	for _, topic := range p.Topics {
-		topics = append(topics, kafka.TopicConfig{
-			Topic:             topic,
-			ReplicationFactor: 1,
-			NumPartitions:     1,
-		})
-	}
-
-	if err := conn.CreateTopics(topics...); err != nil {
+	if err := conn.CreateTopics(p.topicConfigs()...); err != nil {
 		return fmt.Errorf("can't create topics: %w", err)
 	}
 
@@ -226,6 +305,56 @@ func (p *P) ingestMessageFiles(ctx context.Context, c *gnomock.Container, conn *
 	return nil
 }
 
+// topicConfigs returns configurations of all the topics to create. Topics
+// with custom configuration take precedence over the topics with the same
+// name created with default settings.
+func (p *P) topicConfigs() []kafka.TopicConfig {
+	topics := make([]kafka.TopicConfig, 0, len(p.Topics)+len(p.TopicConfigs))
+	configured := make(map[string]bool, len(p.TopicConfigs))
+
+	for _, tc := range p.TopicConfigs {
+		topic := kafka.TopicConfig{
+			Topic:             tc.Name,
+			NumPartitions:     tc.Partitions,
+			ReplicationFactor: tc.ReplicationFactor,
+		}
+
+		if topic.NumPartitions == 0 {
+			topic.NumPartitions = 1
+		}
+
+		if topic.ReplicationFactor == 0 {
+			topic.ReplicationFactor = 1
+		}
+
+		for k, v := range tc.Configs {
+			topic.ConfigEntries = append(topic.ConfigEntries, kafka.ConfigEntry{
+				ConfigName:  k,
+				ConfigValue: v,
+			})
+		}
+
+		topics = append(topics, topic)
+		configured[tc.Name] = true
+	}
+
+	for _, topic := range p.Topics {
+		if configured[topic] {
+			continue
+		}
+
+		configured[topic] = true
+
+		topics = append(topics, kafka.TopicConfig{
+			Topic:             topic,
+			ReplicationFactor: 1,
+			NumPartitions:     1,
+		})
+	}
+
+	return topics
+}
+
 // nolint:gosec
 func (p *P) loadMessagesFromFile(fName string) (msgs []Message, err error) {
 	f, err := os.Open(fName)
@@ -261,15 +390,26 @@ func (p *P) loadMessagesFromFile(fName string) (msgs []Message, err error) {
 }
 
 func (p *P) connect(c *gnomock.Container) (*kafka.Conn, error) {
-	return kafka.Dial("tcp", c.Address(BrokerPort))
+	d, err := p.dialer()
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Dial("tcp", c.Address(BrokerPort))
 }
 
 // nolint: lll
 func (p *P) sendMessagesIntoTopic(ctx context.Context, brokerAddr, topic string, messages []Message) (err error) {
+	d, err := p.dialer()
+	if err != nil {
+		return err
+	}
+
 	w := kafka.NewWriter(kafka.WriterConfig{
 		Brokers:  []string{brokerAddr},
 		Topic:    topic,
 		Balancer: &kafka.LeastBytes{},
+		Dialer:   d,
 	})
 
 	defer func() {
@@ -285,7 +425,17 @@ func (p *P) sendMessagesIntoTopic(ctx context.Context, brokerAddr, topic string,
 		kafkaMessages[i] = kafka.Message{
 			Key:   []byte(m.Key),
 			Value: []byte(m.Value),
-			Time:  time.Unix(0, m.Time),
+		}
+
+		if m.Time != 0 {
+			kafkaMessages[i].Time = time.Unix(0, m.Time)
+		}
+
+		for k, v := range m.Headers {
+			kafkaMessages[i].Headers = append(kafkaMessages[i].Headers, kafka.Header{
+				Key:   k,
+				Value: []byte(v),
+			})
 		}
 	}
 
@@ -0,0 +1,70 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []Option
+		err  string
+	}{
+		{name: "default"},
+		{name: "sasl", opts: []Option{WithSASL(SASLScramSHA512), WithTLS()}},
+		{name: "kraft", opts: []Option{WithKRaft(), WithTopics("events")}},
+		{
+			name: "unknown sasl mechanism",
+			opts: []Option{WithSASL("GSSAPI")},
+			err:  "unsupported sasl mechanism 'GSSAPI'",
+		},
+		{
+			name: "schema registry with sasl",
+			opts: []Option{WithSchemaRegistry(), WithSASL(SASLPlain)},
+			err:  "schema registry is not supported with sasl",
+		},
+		{
+			name: "schema registry with tls",
+			opts: []Option{WithTLS(), WithSchemaRegistry()},
+			err:  "schema registry is not supported with tls",
+		},
+		{
+			name: "kraft with schema registry",
+			opts: []Option{WithKRaft(), WithSchemaRegistry()},
+			err:  "schema registry is not supported in kraft mode",
+		},
+		{
+			name: "kraft with sasl",
+			opts: []Option{WithKRaft(), WithSASL(SASLPlain)},
+			err:  "sasl is not supported in kraft mode",
+		},
+		{
+			name: "kraft with tls",
+			opts: []Option{WithTLS(), WithKRaft()},
+			err:  "tls is not supported in kraft mode",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := Preset(test.opts...).(*P)
+			p.setDefaults()
+
+			err := p.validate()
+			if test.err == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
@@ -2,13 +2,17 @@ package kafka_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/orlangure/gnomock"
 	"github.com/orlangure/gnomock/preset/kafka"
 	kafkaclient "github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/scram"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,10 +25,11 @@ func TestPreset(t *testing.T) {
 			Time:  time.Now().UnixNano(),
 		},
 		{
-			Topic: "alerts",
-			Key:   "CPU",
-			Value: "92",
-			Time:  time.Now().UnixNano(),
+			Topic:   "alerts",
+			Key:     "CPU",
+			Value:   "92",
+			Time:    time.Now().UnixNano(),
+			Headers: map[string]string{"host": "web-1"},
 		},
 	}
 
@@ -58,6 +63,7 @@ func TestPreset(t *testing.T) {
 
 	require.Equal(t, "CPU", string(m.Key))
 	require.Equal(t, "92", string(m.Value))
+	require.Equal(t, []kafkaclient.Header{{Key: "host", Value: []byte("web-1")}}, m.Headers)
 
 	eventsReader := kafkaclient.NewReader(kafkaclient.ReaderConfig{
 		Brokers: []string{container.Address(kafka.BrokerPort)},
@@ -115,4 +121,146 @@ func TestPreset_withSchemaRegistry(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, out.StatusCode)
 	require.NoError(t, out.Body.Close())
+
+	out, err = http.Post(
+		"http://"+container.Address(kafka.SchemaRegistryPort)+"/subjects/events-value/versions",
+		"application/vnd.schemaregistry.v1+json",
+		strings.NewReader(`{"schema":"{\"type\":\"string\"}"}`),
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, out.StatusCode)
+	require.NoError(t, out.Body.Close())
+}
+
+func TestPreset_withTopicConfigs(t *testing.T) {
+	p := kafka.Preset(kafka.WithTopicConfigs(kafka.TopicConfig{
+		Name:       "compacted",
+		Partitions: 3,
+		Configs:    map[string]string{"cleanup.policy": "compact"},
+	}))
+	container, err := gnomock.Start(
+		p,
+		gnomock.WithContainerName("kafka-topic-configs"),
+		gnomock.WithTimeout(time.Minute*10),
+	)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	c, err := kafkaclient.Dial("tcp", container.Address(kafka.BrokerPort))
+	require.NoError(t, err)
+
+	partitions, err := c.ReadPartitions("compacted")
+	require.NoError(t, err)
+	require.Len(t, partitions, 3)
+	require.NoError(t, c.Close())
+}
+
+func TestPreset_withSASLAndTLS(t *testing.T) {
+	opts := []kafka.Option{
+		kafka.WithSASL(kafka.SASLScramSHA256),
+		kafka.WithTLS(),
+		kafka.WithTopics("secure"),
+		kafka.WithMessages(kafka.Message{Topic: "secure", Key: "a", Value: "b"}),
+	}
+	container, err := gnomock.Start(
+		kafka.Preset(opts...),
+		gnomock.WithContainerName("kafka-sasl-tls"),
+		gnomock.WithTimeout(time.Minute*10),
+	)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	dialer, err := kafka.Dialer(opts...)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	r := kafkaclient.NewReader(kafkaclient.ReaderConfig{
+		Brokers: []string{container.Address(kafka.BrokerPort)},
+		Topic:   "secure",
+		Dialer:  dialer,
+	})
+
+	m, err := r.ReadMessage(ctx)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "b", string(m.Value))
+
+	// clients can be configured using generated credentials and certificate
+	user, password := kafka.Credentials(opts...)
+	require.Equal(t, "gnomock", user)
+	require.NotEmpty(t, password)
+
+	mechanism, err := scram.Mechanism(scram.SHA256, user, password)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(kafka.CACert(opts...)))
+
+	custom := &kafkaclient.Dialer{
+		SASLMechanism: mechanism,
+		TLS:           &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		Timeout:       time.Second * 5,
+	}
+	c, err := custom.Dial("tcp", container.Address(kafka.BrokerPort))
+	require.NoError(t, err)
+
+	_, err = c.ReadPartitions("secure")
+	require.NoError(t, err)
+	require.NoError(t, c.Close())
+
+	// connections without credentials are rejected
+	noAuth := &kafkaclient.Dialer{TLS: dialer.TLS, Timeout: time.Second * 5}
+	c, err = noAuth.Dial("tcp", container.Address(kafka.BrokerPort))
+
+	if err == nil {
+		_, err = c.ReadPartitions("secure")
+		require.NoError(t, c.Close())
+	}
+
+	require.Error(t, err)
+}
+
+func TestPreset_withUnknownSASLMechanism(t *testing.T) {
+	p := kafka.Preset(kafka.WithSASL("GSSAPI"))
+	container, err := gnomock.Start(
+		p,
+		gnomock.WithContainerName("kafka-unknown-sasl"),
+		gnomock.WithTimeout(time.Minute*10),
+	)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.EqualError(t, err, "can't init container: unsupported sasl mechanism 'GSSAPI'")
+}
+
+func TestPreset_withKRaft(t *testing.T) {
+	p := kafka.Preset(
+		kafka.WithKRaft(),
+		kafka.WithMessages(kafka.Message{Topic: "events", Key: "order", Value: "1"}),
+	)
+	container, err := gnomock.Start(
+		p,
+		gnomock.WithContainerName("kafka-kraft"),
+		gnomock.WithTimeout(time.Minute*10),
+	)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	r := kafkaclient.NewReader(kafkaclient.ReaderConfig{
+		Brokers: []string{container.Address(kafka.BrokerPort)},
+		Topic:   "events",
+	})
+
+	m, err := r.ReadMessage(ctx)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "order", string(m.Key))
 }
@@ -0,0 +1,239 @@
+package kafka
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/tlsutil"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// SASL mechanisms supported by the broker when WithSASL option is used.
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+const (
+	defaultSASLUser     = "gnomock"
+	defaultSASLPassword = "gnomick"
+
+	securityDir     = "/gnomock"
+	storePassword   = "gnomock"
+	setupEntrypoint = "/usr/local/bin/setup-and-run.sh"
+)
+
+// saslSetup creates a JAAS file with the user allowed to connect using PLAIN
+// mechanism, which is also used by the broker to connect to itself. The same
+// user is then registered for SCRAM mechanisms once zookeeper is up.
+const saslSetup = `cat > ` + securityDir + `/jaas.conf <<JAAS
+KafkaServer {
+	org.apache.kafka.common.security.plain.PlainLoginModule required
+	username="$GNOMOCK_SASL_USER"
+	password="$GNOMOCK_SASL_PASSWORD"
+	user_$GNOMOCK_SASL_USER="$GNOMOCK_SASL_PASSWORD";
+	org.apache.kafka.common.security.scram.ScramLoginModule required
+	username="$GNOMOCK_SASL_USER"
+	password="$GNOMOCK_SASL_PASSWORD";
+};
+JAAS
+(until kafka-configs --zookeeper 127.0.0.1:2181 --alter --entity-type users \
+	--entity-name "$GNOMOCK_SASL_USER" \
+	--add-config "SCRAM-SHA-256=[password=$GNOMOCK_SASL_PASSWORD],SCRAM-SHA-512=[password=$GNOMOCK_SASL_PASSWORD]" \
+	>/dev/null 2>&1; do sleep 1; done) &
+`
+
+// tlsSetup creates PKCS12 key and trust stores from the certificate and key
+// passed in environment variables.
+const tlsSetup = `printf '%s' "$GNOMOCK_TLS_CERT" > ` + securityDir + `/cert.pem
+printf '%s' "$GNOMOCK_TLS_KEY" > ` + securityDir + `/key.pem
+openssl pkcs12 -export -name gnomock \
+	-in ` + securityDir + `/cert.pem -inkey ` + securityDir + `/key.pem \
+	-out ` + securityDir + `/keystore.p12 -passout pass:` + storePassword + `
+keytool -importcert -noprompt -alias gnomock -storetype PKCS12 \
+	-file ` + securityDir + `/cert.pem -keystore ` + securityDir + `/truststore.p12 \
+	-storepass ` + storePassword + `
+`
+
+// Credentials returns the name and password of the user allowed to connect
+// to a container created using the same options. They are generated by
+// WithSASL option, and are empty if it is not used.
+func Credentials(opts ...Option) (user, password string) {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p.SASLUser, p.SASLPassword
+}
+
+// CACert returns PEM encoded certificate of the broker in a container created
+// using the same options. It is generated by WithTLS option, and clients
+// should trust it to connect to the broker. The certificate is empty if
+// WithTLS option is not used.
+func CACert(opts ...Option) []byte {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return []byte(p.TLSCert)
+}
+
+// Dialer returns a kafka-go dialer configured to connect to a container
+// created using the same options, with SASL authentication and TLS, if they
+// are enabled. It can be used in reader and writer configs to access the
+// broker.
+func Dialer(opts ...Option) (*kafka.Dialer, error) {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p.dialer()
+}
+
+func (p *P) dialer() (*kafka.Dialer, error) {
+	d := &kafka.Dialer{
+		Timeout:   10 * time.Second,
+		DualStack: true,
+	}
+
+	if p.SASLMechanism != "" {
+		mechanism, err := p.saslMechanism()
+		if err != nil {
+			return nil, err
+		}
+
+		d.SASLMechanism = mechanism
+	}
+
+	if p.TLSCert != "" {
+		cfg, err := tlsutil.Config(p.TLSCert, "")
+		if err != nil {
+			return nil, err
+		}
+
+		d.TLS = cfg
+	}
+
+	return d, nil
+}
+
+func (p *P) saslMechanism() (sasl.Mechanism, error) {
+	switch p.SASLMechanism {
+	case SASLPlain:
+		return plain.Mechanism{Username: p.SASLUser, Password: p.SASLPassword}, nil
+	case SASLScramSHA256:
+		return scram.Mechanism(scram.SHA256, p.SASLUser, p.SASLPassword)
+	case SASLScramSHA512:
+		return scram.Mechanism(scram.SHA512, p.SASLUser, p.SASLPassword)
+	default:
+		return nil, fmt.Errorf("unsupported sasl mechanism '%s'", p.SASLMechanism)
+	}
+}
+
+// validateSecurity reports security configuration that can't be used to
+// start the broker, such as an unknown SASL mechanism, or that would make the
+// schema registry unreachable, since it only serves plaintext connections
+// without authentication.
+func (p *P) validateSecurity() error {
+	if p.securityErr != nil {
+		return p.securityErr
+	}
+
+	switch p.SASLMechanism {
+	case "", SASLPlain, SASLScramSHA256, SASLScramSHA512:
+	default:
+		return fmt.Errorf("unsupported sasl mechanism '%s'", p.SASLMechanism)
+	}
+
+	switch {
+	case p.UseSchemaRegistry && p.SASLMechanism != "":
+		return errors.New("schema registry is not supported with sasl")
+	case p.UseSchemaRegistry && p.TLSCert != "":
+		return errors.New("schema registry is not supported with tls")
+	default:
+		return nil
+	}
+}
+
+func randomPassword() (string, error) {
+	bs := make([]byte, 16)
+	if _, err := rand.Read(bs); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bs), nil
+}
+
+// securityProtocol returns kafka security protocol of the broker listener.
+func (p *P) securityProtocol() string {
+	switch {
+	case p.SASLMechanism != "" && p.TLSCert != "":
+		return "SASL_SSL"
+	case p.SASLMechanism != "":
+		return "SASL_PLAINTEXT"
+	case p.TLSCert != "":
+		return "SSL"
+	default:
+		return "PLAINTEXT"
+	}
+}
+
+// securityOptions configure the broker listener to use SASL, TLS, or both.
+// The listener keeps its name, and only its security protocol changes.
+func (p *P) securityOptions() []gnomock.Option {
+	protocol := p.securityProtocol()
+	if protocol == "PLAINTEXT" {
+		return nil
+	}
+
+	script := "set -e\nmkdir -p " + securityDir + "\n"
+	opts := []gnomock.Option{
+		gnomock.WithEnv("KAFKA_LISTENER_SECURITY_PROTOCOL_MAP=PLAINTEXT:" + protocol),
+	}
+
+	if p.SASLMechanism != "" {
+		script += saslSetup
+		opts = append(
+			opts,
+			gnomock.WithEnv("GNOMOCK_SASL_USER="+p.SASLUser),
+			gnomock.WithEnv("GNOMOCK_SASL_PASSWORD="+p.SASLPassword),
+			gnomock.WithEnv("KAFKA_OPTS=-Djava.security.auth.login.config="+securityDir+"/jaas.conf"),
+			gnomock.WithEnv("KAFKA_SASL_ENABLED_MECHANISMS="+SASLPlain+","+SASLScramSHA256+","+SASLScramSHA512),
+			gnomock.WithEnv("KAFKA_SASL_MECHANISM_INTER_BROKER_PROTOCOL="+SASLPlain),
+		)
+	}
+
+	if p.TLSCert != "" {
+		script += tlsSetup
+		opts = append(
+			opts,
+			gnomock.WithEnv("GNOMOCK_TLS_CERT="+p.TLSCert),
+			gnomock.WithEnv("GNOMOCK_TLS_KEY="+p.TLSKey),
+			gnomock.WithEnv("KAFKA_SSL_KEYSTORE_LOCATION="+securityDir+"/keystore.p12"),
+			gnomock.WithEnv("KAFKA_SSL_KEYSTORE_TYPE=PKCS12"),
+			gnomock.WithEnv("KAFKA_SSL_KEYSTORE_PASSWORD="+storePassword),
+			gnomock.WithEnv("KAFKA_SSL_KEY_PASSWORD="+storePassword),
+			gnomock.WithEnv("KAFKA_SSL_TRUSTSTORE_LOCATION="+securityDir+"/truststore.p12"),
+			gnomock.WithEnv("KAFKA_SSL_TRUSTSTORE_TYPE=PKCS12"),
+			gnomock.WithEnv("KAFKA_SSL_TRUSTSTORE_PASSWORD="+storePassword),
+		)
+	}
+
+	script += "exec " + setupEntrypoint
+
+	return append(opts, gnomock.WithEntrypoint("/bin/bash", "-c", script))
+}
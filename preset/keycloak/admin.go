@@ -0,0 +1,112 @@
+package keycloak
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+)
+
+// adminClientID is a public client in the master realm that can be used to
+// get admin tokens.
+const adminClientID = "admin-cli"
+
+var errConflict = errors.New("already exists")
+
+// adminToken returns an access token of the admin user that can be used with
+// the admin API.
+func (p *P) adminToken(ctx context.Context, c *gnomock.Container) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "password")
+	form.Set("client_id", adminClientID)
+	form.Set("username", p.AdminUser)
+	form.Set("password", p.AdminPassword)
+
+	addr := fmt.Sprintf("%s/protocol/openid-connect/token", Issuer(c, MasterRealm))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	bs, err := do(req)
+	if err != nil {
+		return "", err
+	}
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+	}
+
+	if err := json.Unmarshal(bs, &out); err != nil {
+		return "", fmt.Errorf("can't parse token response: %w", err)
+	}
+
+	return out.AccessToken, nil
+}
+
+// request sends a request to the provided path, authorized with the token
+// unless it is empty, and returns the response body.
+func request(ctx context.Context, c *gnomock.Container, token, method, path string, body []byte) ([]byte, error) {
+	addr := fmt.Sprintf("http://%s%s", c.DefaultAddress(), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return do(req)
+}
+
+func do(req *http.Request) ([]byte, error) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		return nil, errConflict
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+			ErrorMessage     string `json:"errorMessage"`
+		}
+
+		if err := json.Unmarshal(bs, &apiErr); err == nil {
+			for _, msg := range []string{apiErr.ErrorMessage, apiErr.ErrorDescription, apiErr.Error} {
+				if msg != "" {
+					return nil, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, msg)
+				}
+			}
+		}
+
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return bs, nil
+}
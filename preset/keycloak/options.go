@@ -0,0 +1,46 @@
+package keycloak
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version, for example "21.0.2".
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithAdmin sets the credentials of the admin user in the master realm. If
+// not used, DefaultAdminUser and DefaultAdminPassword are used.
+func WithAdmin(user, password string) Option {
+	return func(o *P) {
+		o.AdminUser = user
+		o.AdminPassword = password
+	}
+}
+
+// WithRealmFiles imports realms from JSON files, such as the ones exported
+// from Keycloak admin console. Every file must include a single realm
+// representation. This option can be used multiple times.
+func WithRealmFiles(files ...string) Option {
+	return func(o *P) {
+		o.RealmFiles = append(o.RealmFiles, files...)
+	}
+}
+
+// WithClients creates OpenID Connect clients after the realms are imported.
+// This option can be used multiple times.
+func WithClients(clients ...Client) Option {
+	return func(o *P) {
+		o.Clients = append(o.Clients, clients...)
+	}
+}
+
+// WithUsers creates users after the realms are imported. This option can be
+// used multiple times.
+func WithUsers(users ...User) Option {
+	return func(o *P) {
+		o.Users = append(o.Users, users...)
+	}
+}
@@ -0,0 +1,304 @@
+// Package keycloak includes Keycloak implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create
+// a configured Keycloak container to use in tests.
+//
+// Keycloak runs in development mode, and issues tokens for the address used
+// to access it. Use Issuer to get the issuer URL of a realm, and configure
+// OpenID Connect clients using its discovery document.
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// Default admin credentials used unless different credentials are
+// configured using WithAdmin.
+const (
+	DefaultAdminUser     = "gnomock"
+	DefaultAdminPassword = "gnomick"
+)
+
+// MasterRealm is the realm that always exists in Keycloak. Clients and users
+// without a realm are created in it.
+const MasterRealm = "master"
+
+const (
+	defaultVersion = "21.0.2"
+	defaultPort    = 8080
+)
+
+func init() {
+	registry.Register("keycloak", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Keycloak preset. This preset includes a
+// Keycloak specific healthcheck function and default Keycloak image and port,
+// and allows to optionally import realms, and create clients and users.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Keycloak.
+type P struct {
+	Version       string   `json:"version"`
+	AdminUser     string   `json:"admin_user"`
+	AdminPassword string   `json:"admin_password"`
+	RealmFiles    []string `json:"realm_files"`
+	Clients       []Client `json:"clients"`
+	Users         []User   `json:"users"`
+}
+
+// Client is an OpenID Connect client created during initial setup.
+// Confidential clients use Secret to authenticate, and have a service
+// account, while public clients don't. All clients can use standard flow and
+// direct access grants.
+type Client struct {
+	// Realm of the client, created if it doesn't exist. MasterRealm is used
+	// if empty.
+	Realm string `json:"realm"`
+
+	ClientID     string   `json:"client_id"`
+	Secret       string   `json:"secret"`
+	Public       bool     `json:"public"`
+	RedirectURIs []string `json:"redirect_uris"`
+}
+
+// User is an enabled user with a verified email created during initial
+// setup.
+type User struct {
+	// Realm of the user, created if it doesn't exist. MasterRealm is used if
+	// empty.
+	Realm string `json:"realm"`
+
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("quay.io/keycloak/keycloak:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithCommand("start-dev", fmt.Sprintf("--http-port=%d", defaultPort)),
+		gnomock.WithEnv("KEYCLOAK_ADMIN=" + p.AdminUser),
+		gnomock.WithEnv("KEYCLOAK_ADMIN_PASSWORD=" + p.AdminPassword),
+	}
+
+	if len(p.RealmFiles) > 0 || len(p.Clients) > 0 || len(p.Users) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.AdminUser == "" && p.AdminPassword == "" {
+		p.AdminUser = DefaultAdminUser
+		p.AdminPassword = DefaultAdminPassword
+	}
+}
+
+// Issuer returns the issuer URL of the provided realm. OpenID Connect
+// discovery document of the realm is available under
+// "/.well-known/openid-configuration" path of this URL.
+func Issuer(c *gnomock.Container, realm string) string {
+	return fmt.Sprintf("http://%s/realms/%s", c.DefaultAddress(), realm)
+}
+
+// healthcheck makes sure that the discovery document of the master realm is
+// served.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	path := fmt.Sprintf("/realms/%s/.well-known/openid-configuration", MasterRealm)
+
+	bs, err := request(ctx, c, "", http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	var doc struct {
+		Issuer string `json:"issuer"`
+	}
+
+	if err := json.Unmarshal(bs, &doc); err != nil {
+		return fmt.Errorf("can't parse discovery document: %w", err)
+	}
+
+	if doc.Issuer == "" {
+		return fmt.Errorf("discovery document has no issuer")
+	}
+
+	return nil
+}
+
+// initf imports the configured realms, then creates realms of the configured
+// clients and users, and then the clients and the users.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	token, err := p.adminToken(ctx, c)
+	if err != nil {
+		return fmt.Errorf("can't get admin token: %w", err)
+	}
+
+	for _, realmFile := range p.RealmFiles {
+		if err := importRealm(ctx, c, token, realmFile); err != nil {
+			return err
+		}
+	}
+
+	for _, realm := range p.realms() {
+		body := map[string]interface{}{"realm": realm, "enabled": true}
+
+		if err := create(ctx, c, token, "/admin/realms", body); err != nil {
+			return fmt.Errorf("can't create realm '%s': %w", realm, err)
+		}
+	}
+
+	for _, client := range p.Clients {
+		if err := createClient(ctx, c, token, client); err != nil {
+			return fmt.Errorf("can't create client '%s': %w", client.ClientID, err)
+		}
+	}
+
+	for _, user := range p.Users {
+		if err := createUser(ctx, c, token, user); err != nil {
+			return fmt.Errorf("can't create user '%s': %w", user.Username, err)
+		}
+	}
+
+	return nil
+}
+
+// realms returns sorted names of the realms used by the configured clients
+// and users, except the master realm.
+func (p *P) realms() []string {
+	unique := map[string]bool{}
+
+	for _, client := range p.Clients {
+		unique[realmOrMaster(client.Realm)] = true
+	}
+
+	for _, user := range p.Users {
+		unique[realmOrMaster(user.Realm)] = true
+	}
+
+	delete(unique, MasterRealm)
+
+	realms := make([]string, 0, len(unique))
+	for realm := range unique {
+		realms = append(realms, realm)
+	}
+
+	sort.Strings(realms)
+
+	return realms
+}
+
+func importRealm(ctx context.Context, c *gnomock.Container, token, realmFile string) error {
+	bs, err := os.ReadFile(realmFile) // nolint:gosec
+	if err != nil {
+		return fmt.Errorf("can't read realm file '%s': %w", realmFile, err)
+	}
+
+	var realm map[string]interface{}
+
+	if err := json.Unmarshal(bs, &realm); err != nil {
+		return fmt.Errorf("can't parse realm file '%s': %w", realmFile, err)
+	}
+
+	if err := create(ctx, c, token, "/admin/realms", realm); err != nil {
+		return fmt.Errorf("can't import realm file '%s': %w", realmFile, err)
+	}
+
+	return nil
+}
+
+func createClient(ctx context.Context, c *gnomock.Container, token string, client Client) error {
+	body := map[string]interface{}{
+		"clientId":                  client.ClientID,
+		"enabled":                   true,
+		"publicClient":              client.Public,
+		"standardFlowEnabled":       true,
+		"directAccessGrantsEnabled": true,
+		"serviceAccountsEnabled":    !client.Public,
+		"redirectUris":              client.RedirectURIs,
+	}
+
+	if !client.Public {
+		body["secret"] = client.Secret
+	}
+
+	path := fmt.Sprintf("/admin/realms/%s/clients", realmOrMaster(client.Realm))
+
+	return create(ctx, c, token, path, body)
+}
+
+func createUser(ctx context.Context, c *gnomock.Container, token string, user User) error {
+	body := map[string]interface{}{
+		"username":      user.Username,
+		"email":         user.Email,
+		"enabled":       true,
+		"emailVerified": true,
+		"credentials": []map[string]interface{}{
+			{"type": "password", "value": user.Password, "temporary": false},
+		},
+	}
+
+	path := fmt.Sprintf("/admin/realms/%s/users", realmOrMaster(user.Realm))
+
+	return create(ctx, c, token, path, body)
+}
+
+// create sends the provided representation of a resource to the admin API,
+// unless the resource already exists.
+func create(ctx context.Context, c *gnomock.Container, token, path string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = request(ctx, c, token, http.MethodPost, path, body)
+	if errors.Is(err, errConflict) {
+		// resources already exist when an existing container is reused
+		return nil
+	}
+
+	return err
+}
+
+func realmOrMaster(realm string) string {
+	if realm == "" {
+		return MasterRealm
+	}
+
+	return realm
+}
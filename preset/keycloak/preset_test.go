@@ -0,0 +1,176 @@
+package keycloak_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/keycloak"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"20.0.5", "21.0.2"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := keycloak.Preset(
+			keycloak.WithVersion(version),
+			keycloak.WithAdmin("root", "root-password"),
+			keycloak.WithRealmFiles("testdata/realm.json"),
+			keycloak.WithClients(
+				keycloak.Client{Realm: "app", ClientID: "backend", Secret: "backend-secret"},
+				keycloak.Client{
+					Realm:        "imported",
+					ClientID:     "frontend",
+					Public:       true,
+					RedirectURIs: []string{"http://localhost:3000/*"},
+				},
+			),
+			keycloak.WithUsers(keycloak.User{
+				Realm:    "app",
+				Username: "alice",
+				Password: "alice-password",
+				Email:    "alice@example.com",
+			}),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		appIssuer := keycloak.Issuer(container, "app")
+		tokenEndpoint := discover(t, appIssuer)
+
+		status, token := requestToken(t, tokenEndpoint, url.Values{
+			"grant_type":    {"password"},
+			"client_id":     {"backend"},
+			"client_secret": {"backend-secret"},
+			"username":      {"alice"},
+			"password":      {"alice-password"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, token)
+
+		status, token = requestToken(t, tokenEndpoint, url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {"backend"},
+			"client_secret": {"backend-secret"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, token)
+
+		status, _ = requestToken(t, tokenEndpoint, url.Values{
+			"grant_type":    {"password"},
+			"client_id":     {"backend"},
+			"client_secret": {"backend-secret"},
+			"username":      {"alice"},
+			"password":      {"wrong-password"},
+		})
+		require.Equal(t, http.StatusUnauthorized, status)
+
+		importedTokenEndpoint := discover(t, keycloak.Issuer(container, "imported"))
+
+		for _, clientID := range []string{"imported-app", "frontend"} {
+			status, token = requestToken(t, importedTokenEndpoint, url.Values{
+				"grant_type": {"password"},
+				"client_id":  {clientID},
+				"username":   {"bob"},
+				"password":   {"bob-password"},
+			})
+			require.Equal(t, http.StatusOK, status, clientID)
+			require.NotEmpty(t, token)
+		}
+
+		status, token = requestToken(t, discover(t, keycloak.Issuer(container, keycloak.MasterRealm)), url.Values{
+			"grant_type": {"password"},
+			"client_id":  {"admin-cli"},
+			"username":   {"root"},
+			"password":   {"root-password"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, token)
+	}
+}
+
+func TestPreset_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	p := keycloak.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	status, token := requestToken(t, discover(t, keycloak.Issuer(container, keycloak.MasterRealm)), url.Values{
+		"grant_type": {"password"},
+		"client_id":  {"admin-cli"},
+		"username":   {keycloak.DefaultAdminUser},
+		"password":   {keycloak.DefaultAdminPassword},
+	})
+	require.Equal(t, http.StatusOK, status)
+	require.NotEmpty(t, token)
+}
+
+func TestPreset_wrongRealmFile(t *testing.T) {
+	t.Parallel()
+
+	p := keycloak.Preset(keycloak.WithRealmFiles("testdata/missing.json"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read realm file")
+}
+
+// discover returns token endpoint from the discovery document of the issuer.
+func discover(t *testing.T, issuer string) string {
+	t.Helper()
+
+	resp, err := http.Get(issuer + "/.well-known/openid-configuration") // nolint:gosec,noctx
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var doc struct {
+		Issuer        string `json:"issuer"`
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	require.Equal(t, issuer, doc.Issuer)
+
+	return doc.TokenEndpoint
+}
+
+func requestToken(t *testing.T, tokenEndpoint string, form url.Values) (int, string) {
+	t.Helper()
+
+	resp, err := http.Post( // nolint:gosec,noctx
+		tokenEndpoint, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()),
+	)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+	return resp.StatusCode, out.AccessToken
+}
@@ -0,0 +1,62 @@
+package localstack_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/localstack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDynamoTables(t *testing.T) {
+	t.Parallel()
+
+	p := localstack.Preset(
+		localstack.WithServices(localstack.DynamoDB),
+		localstack.WithDynamoTables(localstack.Table{
+			Name:         "users",
+			PartitionKey: localstack.KeyAttribute{Name: "id", Type: "S"},
+			GlobalIndexes: []localstack.Index{
+				{Name: "by-email", PartitionKey: localstack.KeyAttribute{Name: "email", Type: "S"}},
+			},
+			TTLAttribute: "expires_at",
+			ItemsFile:    "testdata/dynamodb/users.json",
+		}),
+	)
+	c, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(c)) }()
+
+	require.NoError(t, err)
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(fmt.Sprintf("http://%s", c.Address(localstack.APIPort))),
+		Credentials: credentials.NewStaticCredentials("a", "b", "c"),
+	})
+	require.NoError(t, err)
+
+	svc := dynamodb.New(sess)
+
+	out, err := svc.Query(&dynamodb.QueryInput{
+		TableName:              aws.String("users"),
+		IndexName:              aws.String("by-email"),
+		KeyConditionExpression: aws.String("email = :email"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":email": {S: aws.String("bob@example.com")},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, out.Items, 1)
+	require.Equal(t, "2", *out.Items[0]["id"].S)
+	require.Equal(t, "25", *out.Items[0]["age"].N)
+
+	ttl, err := svc.DescribeTimeToLive(&dynamodb.DescribeTimeToLiveInput{TableName: aws.String("users")})
+	require.NoError(t, err)
+	require.Equal(t, "expires_at", *ttl.TimeToLiveDescription.AttributeName)
+}
@@ -0,0 +1,56 @@
+package localstack_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/localstack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLambda(t *testing.T) {
+	t.Parallel()
+
+	p := localstack.Preset(
+		localstack.WithServices(localstack.Lambda, localstack.SQS),
+		localstack.WithQueues(localstack.Queue{Name: "greetings"}),
+		localstack.WithLambdaTrigger("greeter", "greetings"),
+		localstack.WithLambda(
+			"greeter", "testdata/lambda/handler.zip", "python3.8", "handler.main",
+			map[string]string{"GREETING": "hello"},
+		),
+	)
+	c, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(c)) }()
+
+	require.NoError(t, err)
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(fmt.Sprintf("http://%s", c.Address(localstack.APIPort))),
+		Credentials: credentials.NewStaticCredentials("a", "b", "c"),
+	})
+	require.NoError(t, err)
+
+	svc := lambda.New(sess)
+
+	out, err := svc.Invoke(&lambda.InvokeInput{
+		FunctionName: aws.String("greeter"),
+		Payload:      []byte(`{"name":"gnomock"}`),
+	})
+	require.NoError(t, err)
+	require.Nil(t, out.FunctionError)
+	require.JSONEq(t, `{"greeting":"hello, gnomock"}`, string(out.Payload))
+
+	mappings, err := svc.ListEventSourceMappings(&lambda.ListEventSourceMappingsInput{
+		FunctionName: aws.String("greeter"),
+	})
+	require.NoError(t, err)
+	require.Len(t, mappings.EventSourceMappings, 1)
+}
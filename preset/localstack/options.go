@@ -45,7 +45,17 @@ func (s *Service) UnmarshalJSON(bs []byte) error {
 		SQS,
 		SSM,
 		STS,
-		StepFunctions:
+		StepFunctions,
+		AppSync,
+		Athena,
+		CognitoIdentity,
+		CognitoIDP,
+		ECR,
+		ECS,
+		EKS,
+		ElastiCache,
+		Glue,
+		RDS:
 		*s = svc
 		return nil
 	default:
@@ -81,6 +91,20 @@ const (
 	StepFunctions    Service = "stepfunctions"
 )
 
+// These services are only available in localstack pro image, see WithPro.
+const (
+	AppSync         Service = "appsync"
+	Athena          Service = "athena"
+	CognitoIdentity Service = "cognito-identity"
+	CognitoIDP      Service = "cognito-idp"
+	ECR             Service = "ecr"
+	ECS             Service = "ecs"
+	EKS             Service = "eks"
+	ElastiCache     Service = "elasticache"
+	Glue            Service = "glue"
+	RDS             Service = "rds"
+)
+
 // WithServices selects localstack services to spin up. It is OK to not select
 // any services, but in such case the container will be useless.
 func WithServices(services ...Service) Option {
@@ -89,6 +113,17 @@ func WithServices(services ...Service) Option {
 	}
 }
 
+// WithPro uses localstack pro image, activated using the provided auth
+// token (or API key for older versions). Pro image is required to use pro-only
+// services, such as localstack.RDS or localstack.ECS. When this option is
+// used, WithVersion sets the version of localstack-pro image; by default,
+// version 1.4.0 is used.
+func WithPro(authToken string) Option {
+	return func(o *P) {
+		o.AuthToken = authToken
+	}
+}
+
 // WithVersion sets image version.
 func WithVersion(version string) Option {
 	return func(o *P) {
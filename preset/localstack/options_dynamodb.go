@@ -0,0 +1,187 @@
+package localstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/orlangure/gnomock"
+)
+
+// Table is a DynamoDB table created during initial setup. Tables use
+// on-demand billing mode.
+type Table struct {
+	Name string `json:"name"`
+
+	// PartitionKey is required, while SortKey is optional.
+	PartitionKey KeyAttribute  `json:"partition_key"`
+	SortKey      *KeyAttribute `json:"sort_key"`
+
+	GlobalIndexes []Index `json:"global_indexes"`
+
+	// TTLAttribute, if set, enables time to live using this attribute.
+	TTLAttribute string `json:"ttl_attribute"`
+
+	// ItemsFile is a path to a JSON file with an array of items to put into
+	// the table after it is created.
+	ItemsFile string `json:"items_file"`
+}
+
+// KeyAttribute is an attribute used in a table or index key. Type is one of
+// "S", "N" or "B", for string, number and binary attributes.
+type KeyAttribute struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Index is a global secondary index of a table, projecting all the
+// attributes.
+type Index struct {
+	Name         string        `json:"name"`
+	PartitionKey KeyAttribute  `json:"partition_key"`
+	SortKey      *KeyAttribute `json:"sort_key"`
+}
+
+// WithDynamoTables creates DynamoDB tables during initial setup, and
+// optionally puts items into them. This option does nothing if you don't
+// provide localstack.DynamoDB as one of the services in WithServices.
+func WithDynamoTables(tables ...Table) Option {
+	return func(p *P) {
+		p.DynamoTables = append(p.DynamoTables, tables...)
+	}
+}
+
+func (p *P) initDynamoDB(c *gnomock.Container) error {
+	if len(p.DynamoTables) == 0 {
+		return nil
+	}
+
+	sess, err := p.session(c)
+	if err != nil {
+		return fmt.Errorf("can't create dynamodb session: %w", err)
+	}
+
+	svc := dynamodb.New(sess)
+
+	for _, t := range p.DynamoTables {
+		if err := createTable(svc, t); err != nil {
+			return err
+		}
+
+		if err := putItems(svc, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createTable(svc *dynamodb.DynamoDB, t Table) error {
+	// every attribute used in any key must be defined exactly once
+	defs := map[string]string{}
+	addDef := func(attrs ...*KeyAttribute) {
+		for _, a := range attrs {
+			if a != nil {
+				defs[a.Name] = a.Type
+			}
+		}
+	}
+
+	addDef(&t.PartitionKey, t.SortKey)
+
+	input := &dynamodb.CreateTableInput{
+		TableName:   aws.String(t.Name),
+		BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+		KeySchema:   keySchema(t.PartitionKey, t.SortKey),
+	}
+
+	for _, idx := range t.GlobalIndexes {
+		idx := idx
+
+		addDef(&idx.PartitionKey, idx.SortKey)
+
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, &dynamodb.GlobalSecondaryIndex{
+			IndexName:  aws.String(idx.Name),
+			KeySchema:  keySchema(idx.PartitionKey, idx.SortKey),
+			Projection: &dynamodb.Projection{ProjectionType: aws.String(dynamodb.ProjectionTypeAll)},
+		})
+	}
+
+	for name, typ := range defs {
+		input.AttributeDefinitions = append(input.AttributeDefinitions, &dynamodb.AttributeDefinition{
+			AttributeName: aws.String(name),
+			AttributeType: aws.String(typ),
+		})
+	}
+
+	if _, err := svc.CreateTable(input); err != nil {
+		return fmt.Errorf("can't create table '%s': %w", t.Name, err)
+	}
+
+	if t.TTLAttribute != "" {
+		_, err := svc.UpdateTimeToLive(&dynamodb.UpdateTimeToLiveInput{
+			TableName: aws.String(t.Name),
+			TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+				AttributeName: aws.String(t.TTLAttribute),
+				Enabled:       aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("can't enable ttl of table '%s': %w", t.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func keySchema(partitionKey KeyAttribute, sortKey *KeyAttribute) []*dynamodb.KeySchemaElement {
+	schema := []*dynamodb.KeySchemaElement{
+		{AttributeName: aws.String(partitionKey.Name), KeyType: aws.String(dynamodb.KeyTypeHash)},
+	}
+
+	if sortKey != nil {
+		schema = append(schema, &dynamodb.KeySchemaElement{
+			AttributeName: aws.String(sortKey.Name),
+			KeyType:       aws.String(dynamodb.KeyTypeRange),
+		})
+	}
+
+	return schema
+}
+
+func putItems(svc *dynamodb.DynamoDB, t Table) error {
+	if t.ItemsFile == "" {
+		return nil
+	}
+
+	bs, err := os.ReadFile(t.ItemsFile) // nolint:gosec
+	if err != nil {
+		return fmt.Errorf("can't read items file '%s': %w", t.ItemsFile, err)
+	}
+
+	var items []map[string]interface{}
+
+	if err := json.Unmarshal(bs, &items); err != nil {
+		return fmt.Errorf("can't parse items file '%s': %w", t.ItemsFile, err)
+	}
+
+	for _, item := range items {
+		av, err := dynamodbattribute.MarshalMap(item)
+		if err != nil {
+			return fmt.Errorf("can't convert item of table '%s': %w", t.Name, err)
+		}
+
+		_, err = svc.PutItem(&dynamodb.PutItemInput{
+			TableName: aws.String(t.Name),
+			Item:      av,
+		})
+		if err != nil {
+			return fmt.Errorf("can't put item into table '%s': %w", t.Name, err)
+		}
+	}
+
+	return nil
+}
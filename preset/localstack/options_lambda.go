@@ -0,0 +1,151 @@
+package localstack
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/orlangure/gnomock"
+)
+
+// lambdaRole is used for all the functions, since localstack doesn't enforce
+// IAM policies.
+const lambdaRole = "arn:aws:iam::000000000000:role/gnomock-lambda"
+
+// Function is a Lambda function deployed during initial setup.
+type Function struct {
+	Name        string            `json:"name"`
+	ZipFile     string            `json:"zip_file"`
+	Runtime     string            `json:"runtime"`
+	Handler     string            `json:"handler"`
+	Environment map[string]string `json:"environment"`
+
+	// Queues are the names of SQS queues, created using WithQueues option,
+	// that trigger this function.
+	Queues []string `json:"queues"`
+}
+
+// WithLambda deploys a Lambda function from a zip file during initial setup.
+// Runtime and handler use the same format as in AWS, for example "python3.8"
+// and "handler.main". Environment variables, if provided, are available to
+// the function.
+//
+// This option does nothing if you don't provide localstack.Lambda as one of
+// the services in WithServices.
+func WithLambda(name, zipPath, runtime, handler string, env map[string]string) Option {
+	return func(p *P) {
+		p.Functions = append(p.Functions, Function{
+			Name:        name,
+			ZipFile:     zipPath,
+			Runtime:     runtime,
+			Handler:     handler,
+			Environment: env,
+		})
+	}
+}
+
+// Trigger is an event source mapping that invokes a Lambda function with
+// messages from an SQS queue.
+type Trigger struct {
+	Function string `json:"function"`
+	Queue    string `json:"queue"`
+}
+
+// WithLambdaTrigger creates an event source mapping that invokes a function,
+// deployed using WithLambda, with messages from an SQS queue, created using
+// WithQueues. Localstack.SQS service is required to use this option. The
+// function may be deployed using options that follow this one; initial setup
+// fails if the function is not deployed at all.
+func WithLambdaTrigger(function, queue string) Option {
+	return func(p *P) {
+		p.Triggers = append(p.Triggers, Trigger{Function: function, Queue: queue})
+	}
+}
+
+func (p *P) initLambda(c *gnomock.Container, queueARNs map[string]string) error {
+	triggers, err := p.functionQueues()
+	if err != nil {
+		return err
+	}
+
+	if len(p.Functions) == 0 {
+		return nil
+	}
+
+	sess, err := p.session(c)
+	if err != nil {
+		return fmt.Errorf("can't create lambda session: %w", err)
+	}
+
+	svc := lambda.New(sess)
+
+	for _, fn := range p.Functions {
+		if err := createFunction(svc, fn); err != nil {
+			return err
+		}
+
+		for _, q := range triggers[fn.Name] {
+			arn, ok := queueARNs[q]
+			if !ok {
+				return fmt.Errorf("queue '%s' triggering function '%s' not found", q, fn.Name)
+			}
+
+			_, err := svc.CreateEventSourceMapping(&lambda.CreateEventSourceMappingInput{
+				FunctionName:   aws.String(fn.Name),
+				EventSourceArn: aws.String(arn),
+			})
+			if err != nil {
+				return fmt.Errorf("can't trigger function '%s' by queue '%s': %w", fn.Name, q, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// functionQueues returns the names of queues that trigger every function,
+// including the ones set using WithLambdaTrigger. Triggers of functions that
+// are not deployed are reported as errors.
+func (p *P) functionQueues() (map[string][]string, error) {
+	queues := make(map[string][]string, len(p.Functions))
+
+	for _, fn := range p.Functions {
+		queues[fn.Name] = append(queues[fn.Name], fn.Queues...)
+	}
+
+	for _, t := range p.Triggers {
+		if _, ok := queues[t.Function]; !ok {
+			return nil, fmt.Errorf("function '%s' triggered by queue '%s' not found", t.Function, t.Queue)
+		}
+
+		queues[t.Function] = append(queues[t.Function], t.Queue)
+	}
+
+	return queues, nil
+}
+
+func createFunction(svc *lambda.Lambda, fn Function) error {
+	code, err := os.ReadFile(fn.ZipFile) // nolint:gosec
+	if err != nil {
+		return fmt.Errorf("can't read function '%s' code: %w", fn.Name, err)
+	}
+
+	input := &lambda.CreateFunctionInput{
+		FunctionName: aws.String(fn.Name),
+		Runtime:      aws.String(fn.Runtime),
+		Handler:      aws.String(fn.Handler),
+		Role:         aws.String(lambdaRole),
+		Code:         &lambda.FunctionCode{ZipFile: code},
+	}
+
+	if len(fn.Environment) > 0 {
+		input.Environment = &lambda.Environment{Variables: aws.StringMap(fn.Environment)}
+	}
+
+	if _, err := svc.CreateFunction(input); err != nil {
+		return fmt.Errorf("can't create function '%s': %w", fn.Name, err)
+	}
+
+	return nil
+}
@@ -30,8 +30,25 @@ func WithS3Files(path string) Option {
 	}
 }
 
+// WithS3Bucket sets up S3 service running in localstack with a bucket with
+// the provided name, and uploads the contents of `dir` directory into it. The
+// keys of the uploaded objects are their paths relative to `dir`.
+//
+// This option can be used multiple times to create multiple buckets, and can
+// be combined with WithS3Files. It does nothing if you don't provide
+// localstack.S3 as one of the services in WithServices.
+func WithS3Bucket(bucket, dir string) Option {
+	return func(p *P) {
+		if p.S3Buckets == nil {
+			p.S3Buckets = make(map[string]string)
+		}
+
+		p.S3Buckets[bucket] = dir
+	}
+}
+
 func (p *P) initS3(c *gnomock.Container) error {
-	if p.S3Path == "" {
+	if p.S3Path == "" && len(p.S3Buckets) == 0 {
 		return nil
 	}
 
@@ -63,28 +80,38 @@ func (p *P) initS3(c *gnomock.Container) error {
 	return nil
 }
 
-func (p *P) createBuckets(svc *s3.S3) ([]string, error) {
-	files, err := os.ReadDir(p.S3Path)
-	if err != nil {
-		return nil, fmt.Errorf("can't read s3 initial files: %w", err)
-	}
+// createBuckets creates all the configured buckets, and returns local
+// directories to upload into each of them.
+func (p *P) createBuckets(svc *s3.S3) (map[string]string, error) {
+	buckets := make(map[string]string, len(p.S3Buckets))
 
-	buckets := []string{}
+	if p.S3Path != "" {
+		files, err := os.ReadDir(p.S3Path)
+		if err != nil {
+			return nil, fmt.Errorf("can't read s3 initial files: %w", err)
+		}
 
-	// create buckets from top-level folders under `path`
-	for _, f := range files {
-		if !f.IsDir() {
-			continue
+		// create buckets from top-level folders under `path`
+		for _, f := range files {
+			if f.IsDir() {
+				buckets[f.Name()] = path.Join(p.S3Path, f.Name())
+			}
 		}
+	}
 
-		bucket := f.Name()
+	for bucket, dir := range p.S3Buckets {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("can't read s3 initial files: %w", err)
+		}
+
+		buckets[bucket] = dir
+	}
 
+	for bucket := range buckets {
 		err := p.createBucket(svc, bucket)
 		if err != nil {
 			return nil, fmt.Errorf("can't create bucket '%s': %w", bucket, err)
 		}
-
-		buckets = append(buckets, bucket)
 	}
 
 	return buckets, nil
@@ -100,12 +127,12 @@ func (p *P) createBucket(svc *s3.S3, bucket string) error {
 	return nil
 }
 
-func (p *P) uploadFiles(svc *s3.S3, buckets []string) error {
-	for _, bucket := range buckets {
-		bucket := bucket
+func (p *P) uploadFiles(svc *s3.S3, buckets map[string]string) error {
+	for bucket, dir := range buckets {
+		bucket, dir := bucket, dir
 
 		err := filepath.Walk(
-			path.Join(p.S3Path, bucket),
+			dir,
 			func(fPath string, file os.FileInfo, err error) error {
 				if err != nil {
 					return fmt.Errorf("error reading input file '%s': %w", fPath, err)
@@ -115,7 +142,7 @@ func (p *P) uploadFiles(svc *s3.S3, buckets []string) error {
 					return nil
 				}
 
-				err = p.uploadFile(svc, bucket, fPath)
+				err = p.uploadFile(svc, bucket, dir, fPath)
 				if err != nil {
 					return err
 				}
@@ -131,7 +158,7 @@ func (p *P) uploadFiles(svc *s3.S3, buckets []string) error {
 	return nil
 }
 
-func (p *P) uploadFile(svc *s3.S3, bucket, file string) (err error) {
+func (p *P) uploadFile(svc *s3.S3, bucket, dir, file string) (err error) {
 	inputFile, err := os.Open(file) //nolint:gosec
 	if err != nil {
 		return fmt.Errorf("can't open file '%s': %w", file, err)
@@ -144,8 +171,12 @@ func (p *P) uploadFile(svc *s3.S3, bucket, file string) (err error) {
 		}
 	}()
 
-	localPath := path.Join(p.S3Path, bucket)
-	key := file[len(localPath):]
+	key, err := filepath.Rel(dir, file)
+	if err != nil {
+		return fmt.Errorf("can't get key of file '%s': %w", file, err)
+	}
+
+	key = filepath.ToSlash(key)
 
 	input := &s3.PutObjectInput{
 		Bucket: aws.String(bucket),
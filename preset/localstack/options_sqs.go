@@ -0,0 +1,219 @@
+package localstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/orlangure/gnomock"
+)
+
+const fifoSuffix = ".fifo"
+
+// Queue is an SQS queue created during initial setup.
+type Queue struct {
+	// Name of the queue. FIFO queue names get ".fifo" suffix if it is not
+	// already present.
+	Name string `json:"name"`
+
+	// FIFO creates a first-in-first-out queue.
+	FIFO bool `json:"fifo"`
+
+	// DeadLetterQueue is the name of another queue, created using the same
+	// option, to move messages to after MaxReceiveCount receives.
+	DeadLetterQueue string `json:"dead_letter_queue"`
+	MaxReceiveCount int    `json:"max_receive_count"`
+
+	// Attributes are any other queue attributes, such as
+	// "VisibilityTimeout".
+	Attributes map[string]string `json:"attributes"`
+}
+
+// Topic is an SNS topic created during initial setup.
+type Topic struct {
+	// Name of the topic. FIFO topic names get ".fifo" suffix if it is not
+	// already present.
+	Name string `json:"name"`
+
+	// FIFO creates a first-in-first-out topic.
+	FIFO bool `json:"fifo"`
+
+	// Queues are the names of queues, created using WithQueues option, to
+	// subscribe to this topic.
+	Queues []string `json:"queues"`
+
+	// RawMessageDelivery sends messages to the subscribed queues without
+	// wrapping them in SNS envelope.
+	RawMessageDelivery bool `json:"raw_message_delivery"`
+}
+
+// WithQueues creates SQS queues during initial setup. Dead letter queues
+// should be included in the list as well. This option does nothing if you
+// don't provide localstack.SQS as one of the services in WithServices.
+func WithQueues(queues ...Queue) Option {
+	return func(p *P) {
+		p.Queues = append(p.Queues, queues...)
+	}
+}
+
+// WithTopics creates SNS topics during initial setup, and subscribes the
+// requested SQS queues to them. This option does nothing if you don't provide
+// localstack.SNS as one of the services in WithServices. Localstack.SQS is
+// also required when topics have subscriptions.
+func WithTopics(topics ...Topic) Option {
+	return func(p *P) {
+		p.Topics = append(p.Topics, topics...)
+	}
+}
+
+func fifoName(name string, fifo bool) string {
+	if fifo && !strings.HasSuffix(name, fifoSuffix) {
+		return name + fifoSuffix
+	}
+
+	return name
+}
+
+// initSQS creates the queues, and returns their ARNs by the names used in
+// the options.
+func (p *P) initSQS(c *gnomock.Container) (map[string]string, error) {
+	if len(p.Queues) == 0 {
+		return nil, nil
+	}
+
+	sess, err := p.session(c)
+	if err != nil {
+		return nil, fmt.Errorf("can't create sqs session: %w", err)
+	}
+
+	svc := sqs.New(sess)
+	urls := make(map[string]*string, len(p.Queues))
+	arns := make(map[string]string, len(p.Queues))
+
+	for _, q := range p.Queues {
+		attrs := make(map[string]*string, len(q.Attributes)+1)
+		for k, v := range q.Attributes {
+			attrs[k] = aws.String(v)
+		}
+
+		if q.FIFO {
+			attrs[sqs.QueueAttributeNameFifoQueue] = aws.String("true")
+		}
+
+		out, err := svc.CreateQueue(&sqs.CreateQueueInput{
+			QueueName:  aws.String(fifoName(q.Name, q.FIFO)),
+			Attributes: attrs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("can't create queue '%s': %w", q.Name, err)
+		}
+
+		attrsOut, err := svc.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+			QueueUrl:       out.QueueUrl,
+			AttributeNames: []*string{aws.String(sqs.QueueAttributeNameQueueArn)},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("can't get queue '%s' arn: %w", q.Name, err)
+		}
+
+		urls[q.Name] = out.QueueUrl
+		arns[q.Name] = aws.StringValue(attrsOut.Attributes[sqs.QueueAttributeNameQueueArn])
+	}
+
+	// redrive policies are set after all the queues exist, so that the order
+	// of the queues doesn't matter
+	for _, q := range p.Queues {
+		if q.DeadLetterQueue == "" {
+			continue
+		}
+
+		if err := setRedrivePolicy(svc, urls[q.Name], arns, q); err != nil {
+			return nil, err
+		}
+	}
+
+	return arns, nil
+}
+
+func setRedrivePolicy(svc *sqs.SQS, url *string, arns map[string]string, q Queue) error {
+	dlq, ok := arns[q.DeadLetterQueue]
+	if !ok {
+		return fmt.Errorf("dead letter queue '%s' of queue '%s' not found", q.DeadLetterQueue, q.Name)
+	}
+
+	policy, err := json.Marshal(map[string]string{
+		"deadLetterTargetArn": dlq,
+		"maxReceiveCount":     strconv.Itoa(q.MaxReceiveCount),
+	})
+	if err != nil {
+		return fmt.Errorf("can't create redrive policy of queue '%s': %w", q.Name, err)
+	}
+
+	_, err = svc.SetQueueAttributes(&sqs.SetQueueAttributesInput{
+		QueueUrl: url,
+		Attributes: map[string]*string{
+			sqs.QueueAttributeNameRedrivePolicy: aws.String(string(policy)),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("can't set redrive policy of queue '%s': %w", q.Name, err)
+	}
+
+	return nil
+}
+
+func (p *P) initSNS(c *gnomock.Container, queueARNs map[string]string) error {
+	if len(p.Topics) == 0 {
+		return nil
+	}
+
+	sess, err := p.session(c)
+	if err != nil {
+		return fmt.Errorf("can't create sns session: %w", err)
+	}
+
+	svc := sns.New(sess)
+
+	for _, t := range p.Topics {
+		attrs := map[string]*string{}
+		if t.FIFO {
+			attrs["FifoTopic"] = aws.String("true")
+		}
+
+		out, err := svc.CreateTopic(&sns.CreateTopicInput{
+			Name:       aws.String(fifoName(t.Name, t.FIFO)),
+			Attributes: attrs,
+		})
+		if err != nil {
+			return fmt.Errorf("can't create topic '%s': %w", t.Name, err)
+		}
+
+		for _, q := range t.Queues {
+			arn, ok := queueARNs[q]
+			if !ok {
+				return fmt.Errorf("queue '%s' subscribed to topic '%s' not found", q, t.Name)
+			}
+
+			subAttrs := map[string]*string{}
+			if t.RawMessageDelivery {
+				subAttrs["RawMessageDelivery"] = aws.String("true")
+			}
+
+			_, err := svc.Subscribe(&sns.SubscribeInput{
+				TopicArn:   out.TopicArn,
+				Protocol:   aws.String("sqs"),
+				Endpoint:   aws.String(arn),
+				Attributes: subAttrs,
+			})
+			if err != nil {
+				return fmt.Errorf("can't subscribe queue '%s' to topic '%s': %w", q, t.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
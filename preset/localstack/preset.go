@@ -11,6 +11,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/orlangure/gnomock"
 	"github.com/orlangure/gnomock/internal/registry"
 )
@@ -22,7 +25,10 @@ const (
 	APIPort = "api"
 )
 
-const defaultVersion = "0.14.0"
+const (
+	defaultVersion    = "0.14.0"
+	defaultProVersion = "1.4.0"
+)
 
 func init() {
 	registry.Register("localstack", func() gnomock.Preset { return &P{} })
@@ -49,10 +55,24 @@ type P struct {
 	Services []Service `json:"services"`
 	S3Path   string    `json:"s3_path"`
 	Version  string    `json:"version"`
+
+	S3Buckets map[string]string `json:"s3_buckets"`
+	Queues    []Queue           `json:"queues"`
+	Topics    []Topic           `json:"topics"`
+
+	DynamoTables []Table    `json:"dynamo_tables"`
+	Functions    []Function `json:"functions"`
+	Triggers     []Trigger  `json:"lambda_triggers"`
+
+	AuthToken string `json:"auth_token"`
 }
 
 // Image returns an image that should be pulled to create this container.
 func (p *P) Image() string {
+	if p.AuthToken != "" {
+		return fmt.Sprintf("docker.io/localstack/localstack-pro:%s", p.Version)
+	}
+
 	return fmt.Sprintf("docker.io/localstack/localstack:%s", p.Version)
 }
 
@@ -81,12 +101,25 @@ func (p *P) Options() []gnomock.Option {
 		gnomock.WithInit(p.initf()),
 	}
 
+	if p.AuthToken != "" {
+		// older versions of localstack use API key to activate pro features
+		opts = append(
+			opts,
+			gnomock.WithEnv("LOCALSTACK_AUTH_TOKEN="+p.AuthToken),
+			gnomock.WithEnv("LOCALSTACK_API_KEY="+p.AuthToken),
+		)
+	}
+
 	return opts
 }
 
 func (p *P) setDefaults() {
 	if p.Version == "" {
 		p.Version = defaultVersion
+
+		if p.AuthToken != "" {
+			p.Version = defaultProVersion
+		}
 	}
 }
 
@@ -191,15 +224,64 @@ type healthResponse struct {
 
 func (p *P) initf() gnomock.InitFunc {
 	return func(ctx context.Context, c *gnomock.Container) error {
-		for _, s := range p.Services {
-			if s == S3 {
-				err := p.initS3(c)
-				if err != nil {
-					return fmt.Errorf("can't init s3 storage: %w", err)
-				}
+		if p.hasService(S3) {
+			err := p.initS3(c)
+			if err != nil {
+				return fmt.Errorf("can't init s3 storage: %w", err)
+			}
+		}
+
+		if p.hasService(DynamoDB) {
+			err := p.initDynamoDB(c)
+			if err != nil {
+				return fmt.Errorf("can't init dynamodb tables: %w", err)
+			}
+		}
+
+		var queueARNs map[string]string
+
+		if p.hasService(SQS) {
+			arns, err := p.initSQS(c)
+			if err != nil {
+				return fmt.Errorf("can't init sqs queues: %w", err)
+			}
+
+			queueARNs = arns
+		}
+
+		if p.hasService(SNS) {
+			err := p.initSNS(c, queueARNs)
+			if err != nil {
+				return fmt.Errorf("can't init sns topics: %w", err)
+			}
+		}
+
+		if p.hasService(Lambda) {
+			err := p.initLambda(c, queueARNs)
+			if err != nil {
+				return fmt.Errorf("can't init lambda functions: %w", err)
 			}
 		}
 
 		return nil
 	}
 }
+
+func (p *P) hasService(svc Service) bool {
+	for _, s := range p.Services {
+		if s == svc {
+			return true
+		}
+	}
+
+	return false
+}
+
+// session returns an AWS session configured to use this container.
+func (p *P) session(c *gnomock.Container) (*session.Session, error) {
+	return session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(fmt.Sprintf("http://%s", c.Address(APIPort))),
+		Credentials: credentials.NewStaticCredentials("a", "b", "c"),
+	})
+}
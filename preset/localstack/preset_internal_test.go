@@ -88,3 +88,37 @@ func TestHealthCheckAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestProImage(t *testing.T) {
+	p := Preset(WithPro("token")).(*P)
+	_ = p.Options()
+	require.Equal(t, "docker.io/localstack/localstack-pro:1.4.0", p.Image())
+
+	p = Preset(WithPro("token"), WithVersion("2.0.0")).(*P)
+	_ = p.Options()
+	require.Equal(t, "docker.io/localstack/localstack-pro:2.0.0", p.Image())
+
+	p = Preset().(*P)
+	_ = p.Options()
+	require.Equal(t, "docker.io/localstack/localstack:0.14.0", p.Image())
+}
+
+func TestFunctionQueues(t *testing.T) {
+	p := Preset(
+		WithLambdaTrigger("greeter", "greetings"),
+		WithLambda("greeter", "handler.zip", "python3.8", "handler.main", nil),
+		WithLambdaTrigger("greeter", "farewells"),
+	).(*P)
+
+	queues, err := p.functionQueues()
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"greeter": {"greetings", "farewells"}}, queues)
+
+	p = Preset(
+		WithLambda("greeter", "handler.zip", "python3.8", "handler.main", nil),
+		WithLambdaTrigger("unknown", "greetings"),
+	).(*P)
+
+	_, err = p.functionQueues()
+	require.EqualError(t, err, "function 'unknown' triggered by queue 'greetings' not found")
+}
@@ -83,3 +83,36 @@ func TestWithS3Files(t *testing.T) {
 		require.True(t, strings.HasPrefix(*f.Key, "dir/f-"))
 	}
 }
+
+func TestWithS3Bucket(t *testing.T) {
+	t.Parallel()
+
+	p := localstack.Preset(
+		localstack.WithServices(localstack.S3),
+		localstack.WithS3Bucket("fixtures", "testdata/s3-bucket"),
+	)
+	c, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(c)) }()
+
+	require.NoError(t, err)
+
+	s3Endpoint := fmt.Sprintf("http://%s/", c.Address(localstack.APIPort))
+	config := &aws.Config{
+		Region:           aws.String("us-east-1"),
+		Endpoint:         aws.String(s3Endpoint),
+		S3ForcePathStyle: aws.Bool(true),
+		Credentials:      credentials.NewStaticCredentials("a", "b", "c"),
+	}
+
+	sess, err := session.NewSession(config)
+	require.NoError(t, err)
+
+	svc := s3.New(sess)
+
+	files, err := svc.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String("fixtures")})
+	require.NoError(t, err)
+	require.Len(t, files.Contents, 2)
+	require.Equal(t, "a.txt", *files.Contents[0].Key)
+	require.Equal(t, "nested/b.txt", *files.Contents[1].Key)
+}
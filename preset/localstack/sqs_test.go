@@ -0,0 +1,83 @@
+package localstack_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/localstack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithQueuesAndTopics(t *testing.T) {
+	t.Parallel()
+
+	p := localstack.Preset(
+		localstack.WithServices(localstack.SQS, localstack.SNS),
+		localstack.WithQueues(
+			localstack.Queue{Name: "orders", DeadLetterQueue: "orders-dlq", MaxReceiveCount: 3},
+			localstack.Queue{Name: "orders-dlq"},
+			localstack.Queue{Name: "payments", FIFO: true},
+		),
+		localstack.WithTopics(localstack.Topic{
+			Name:               "events",
+			Queues:             []string{"orders"},
+			RawMessageDelivery: true,
+		}),
+	)
+	c, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(c)) }()
+
+	require.NoError(t, err)
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(fmt.Sprintf("http://%s", c.Address(localstack.APIPort))),
+		Credentials: credentials.NewStaticCredentials("a", "b", "c"),
+	})
+	require.NoError(t, err)
+
+	sqsService := sqs.New(sess)
+	snsService := sns.New(sess)
+
+	queues, err := sqsService.ListQueues(&sqs.ListQueuesInput{})
+	require.NoError(t, err)
+	require.Len(t, queues.QueueUrls, 3)
+
+	orders, err := sqsService.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String("orders")})
+	require.NoError(t, err)
+
+	attrs, err := sqsService.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       orders.QueueUrl,
+		AttributeNames: []*string{aws.String(sqs.QueueAttributeNameRedrivePolicy)},
+	})
+	require.NoError(t, err)
+	require.Contains(t, *attrs.Attributes[sqs.QueueAttributeNameRedrivePolicy], "orders-dlq")
+
+	_, err = sqsService.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String("payments.fifo")})
+	require.NoError(t, err)
+
+	topics, err := snsService.ListTopics(&sns.ListTopicsInput{})
+	require.NoError(t, err)
+	require.Len(t, topics.Topics, 1)
+
+	_, err = snsService.Publish(&sns.PublishInput{
+		TopicArn: topics.Topics[0].TopicArn,
+		Message:  aws.String("foobar"),
+	})
+	require.NoError(t, err)
+
+	messages, err := sqsService.ReceiveMessage(&sqs.ReceiveMessageInput{
+		QueueUrl:        orders.QueueUrl,
+		WaitTimeSeconds: aws.Int64(5),
+	})
+	require.NoError(t, err)
+	require.Len(t, messages.Messages, 1)
+	require.Equal(t, "foobar", *messages.Messages[0].Body)
+}
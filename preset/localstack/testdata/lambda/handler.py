@@ -0,0 +1,5 @@
+import os
+
+
+def main(event, context):
+    return {"greeting": os.environ["GREETING"] + ", " + event["name"]}
@@ -20,6 +20,15 @@ func WithByteValues(vs map[string][]byte) Option {
 	}
 }
 
+// WithItems initializes Memcached with the provided items. Unlike WithValues
+// and WithByteValues, items can have either string or binary values, and can
+// expire after the configured number of seconds.
+func WithItems(items ...Item) Option {
+	return func(p *P) {
+		p.Items = append(p.Items, items...)
+	}
+}
+
 // WithVersion sets image version.
 func WithVersion(version string) Option {
 	return func(o *P) {
@@ -4,6 +4,7 @@
 package memcached
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 
@@ -12,7 +13,10 @@ import (
 	"github.com/orlangure/gnomock/internal/registry"
 )
 
-const defaultVersion = "1.6.9"
+const (
+	defaultVersion = "1.6.9"
+	healthcheckKey = "gnomock-healthcheck"
+)
 
 func init() {
 	registry.Register("memcached", func() gnomock.Preset { return &P{} })
@@ -36,6 +40,33 @@ type P struct {
 	Values     map[string]string `json:"values"`
 	ByteValues map[string][]byte `json:"byteValues"`
 	Version    string            `json:"version"`
+	Items      []Item            `json:"items"`
+}
+
+// Item is a single value set in the container during initial setup.
+type Item struct {
+	// Key is the key of this item.
+	Key string `json:"key"`
+
+	// Value is a string value of this item. It is ignored when ByteValue is
+	// set.
+	Value string `json:"value"`
+
+	// ByteValue is a binary value of this item.
+	ByteValue []byte `json:"byte_value"`
+
+	// Expiration is the time this item expires in, in seconds. Values larger
+	// than 30 days are interpreted by memcached as unix timestamps. Zero
+	// means the item never expires.
+	Expiration int32 `json:"expiration"`
+}
+
+func (i Item) value() []byte {
+	if i.ByteValue != nil {
+		return i.ByteValue
+	}
+
+	return []byte(i.Value)
 }
 
 // Image returns an image that should be pulled to create this container.
@@ -56,33 +87,8 @@ func (p *P) Options() []gnomock.Option {
 		gnomock.WithHealthCheck(healthcheck),
 	}
 
-	if p.ByteValues != nil || p.Values != nil {
-		initf := func(ctx context.Context, c *gnomock.Container) error {
-			addr := c.Address(gnomock.DefaultPort)
-			client := memcache.New(addr)
-
-			if p.ByteValues != nil {
-				for k, v := range p.ByteValues {
-					err := client.Set(&memcache.Item{Key: k, Value: v, Expiration: 0})
-					if err != nil {
-						return fmt.Errorf("can't set '%s'='%v': %w", k, v, err)
-					}
-				}
-			}
-
-			if p.Values != nil {
-				for k, v := range p.Values {
-					err := client.Set(&memcache.Item{Key: k, Value: []byte(v), Expiration: 0})
-					if err != nil {
-						return fmt.Errorf("can't set '%s'='%v': %w", k, v, err)
-					}
-				}
-			}
-
-			return nil
-		}
-
-		opts = append(opts, gnomock.WithInit(initf))
+	if p.ByteValues != nil || p.Values != nil || len(p.Items) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
 	}
 
 	return opts
@@ -94,9 +100,63 @@ func (p *P) setDefaults() {
 	}
 }
 
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	addr := c.Address(gnomock.DefaultPort)
+	client := memcache.New(addr)
+
+	if p.ByteValues != nil {
+		for k, v := range p.ByteValues {
+			err := client.Set(&memcache.Item{Key: k, Value: v, Expiration: 0})
+			if err != nil {
+				return fmt.Errorf("can't set '%s'='%v': %w", k, v, err)
+			}
+		}
+	}
+
+	if p.Values != nil {
+		for k, v := range p.Values {
+			err := client.Set(&memcache.Item{Key: k, Value: []byte(v), Expiration: 0})
+			if err != nil {
+				return fmt.Errorf("can't set '%s'='%v': %w", k, v, err)
+			}
+		}
+	}
+
+	for _, i := range p.Items {
+		err := client.Set(&memcache.Item{Key: i.Key, Value: i.value(), Expiration: i.Expiration})
+		if err != nil {
+			return fmt.Errorf("can't set '%s': %w", i.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// healthcheck makes sure memcached responds to version command, and stores
+// and returns values. The value used for the check is removed, so that it
+// doesn't affect the tests.
 func healthcheck(ctx context.Context, c *gnomock.Container) error {
 	addr := c.Address(gnomock.DefaultPort)
 	client := memcache.New(addr)
 
-	return client.Ping()
+	if err := client.Ping(); err != nil {
+		return fmt.Errorf("can't get version: %w", err)
+	}
+
+	value := []byte(c.ID)
+
+	if err := client.Set(&memcache.Item{Key: healthcheckKey, Value: value}); err != nil {
+		return fmt.Errorf("can't set value: %w", err)
+	}
+
+	item, err := client.Get(healthcheckKey)
+	if err != nil {
+		return fmt.Errorf("can't get value: %w", err)
+	}
+
+	if !bytes.Equal(item.Value, value) {
+		return fmt.Errorf("unexpected value: want '%s', got '%s'", value, item.Value)
+	}
+
+	return client.Delete(healthcheckKey)
 }
@@ -2,8 +2,10 @@ package memcached_test
 
 import (
 	"encoding/binary"
+	"errors"
 	"strconv"
 	"testing"
+	"time"
 
 	memcachedclient "github.com/bradfitz/gomemcache/memcache"
 	"github.com/orlangure/gnomock"
@@ -78,3 +80,38 @@ func TestPreset_withDefaults(t *testing.T) {
 	client := memcachedclient.New(addr)
 	require.NoError(t, client.Ping())
 }
+
+func TestPreset_withItems(t *testing.T) {
+	t.Parallel()
+
+	p := memcached.Preset(
+		memcached.WithItems(
+			memcached.Item{Key: "a", Value: "foo"},
+			memcached.Item{Key: "b", ByteValue: []byte{0, 42}},
+			memcached.Item{Key: "c", Value: "bar", Expiration: 1},
+		),
+	)
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	client := memcachedclient.New(container.DefaultAddress())
+
+	itemA, err := client.Get("a")
+	require.NoError(t, err)
+	require.Equal(t, "foo", string(itemA.Value))
+
+	itemB, err := client.Get("b")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 42}, itemB.Value)
+
+	require.Eventually(t, func() bool {
+		_, err := client.Get("c")
+		return errors.Is(err, memcachedclient.ErrCacheMiss)
+	}, time.Second*5, time.Millisecond*250)
+
+	_, err = client.Get("gnomock-healthcheck")
+	require.ErrorIs(t, err, memcachedclient.ErrCacheMiss)
+}
@@ -0,0 +1,50 @@
+package minio
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version, for example
+// "RELEASE.2023-03-24T21-41-23Z".
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithCredentials sets the access key and the secret key of the root user.
+// The access key must be at least 3 characters long, and the secret key must
+// be at least 8 characters long. If not used, DefaultAccessKey and
+// DefaultSecretKey are used.
+func WithCredentials(accessKey, secretKey string) Option {
+	return func(o *P) {
+		o.AccessKey = accessKey
+		o.SecretKey = secretKey
+	}
+}
+
+// WithBuckets creates empty buckets with the provided names. This option can
+// be used multiple times.
+func WithBuckets(buckets ...string) Option {
+	return func(o *P) {
+		o.Buckets = append(o.Buckets, buckets...)
+	}
+}
+
+// WithData sets up initial container state according to the directory
+// structure at the given path:
+//
+//   - path/first/one.txt
+//   - path/first/dir/two.json
+//   - path/second/three.csv
+//
+// For such directory structure, two buckets are created: "first" and
+// "second". Under "first" bucket there are two objects, "one.txt" and
+// "dir/two.json", and under "second" bucket - one object "three.csv".
+//
+// Top level files under "path" are ignored, only directories are used.
+func WithData(path string) Option {
+	return func(o *P) {
+		o.DataPath = path
+	}
+}
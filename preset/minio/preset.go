@@ -0,0 +1,262 @@
+// Package minio includes MinIO implementation of Gnomock Preset interface.
+// This Preset can be passed to gnomock.Start() function to create a
+// configured MinIO container to use in tests.
+//
+// The default port serves S3 compatible API, and ConsolePort serves MinIO
+// web console. S3 clients must use path-style addressing, and the access and
+// secret keys configured using WithCredentials, or DefaultAccessKey and
+// DefaultSecretKey.
+package minio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// ConsolePort is a name of the port exposed by MinIO containers in addition
+// to the default S3 API port.
+const ConsolePort = "console"
+
+// Default credentials used unless different credentials are configured using
+// WithCredentials.
+const (
+	DefaultAccessKey = "gnomock"
+	DefaultSecretKey = "gnomock-secret"
+)
+
+const (
+	defaultVersion    = "RELEASE.2023-03-24T21-41-23Z"
+	defaultPort       = 9000
+	consolePort       = 9001
+	region            = "us-east-1"
+	healthcheckBucket = "gnomock-healthcheck"
+)
+
+func init() {
+	registry.Register("minio", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock MinIO preset. This preset includes a MinIO
+// specific healthcheck function and default MinIO image and ports, and allows
+// to optionally create buckets and upload objects.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for MinIO.
+type P struct {
+	Version   string   `json:"version"`
+	AccessKey string   `json:"access_key"`
+	SecretKey string   `json:"secret_key"`
+	Buckets   []string `json:"buckets"`
+	DataPath  string   `json:"data_path"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/minio/minio:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[ConsolePort] = gnomock.Port{Protocol: "tcp", Port: consolePort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithCommand("server", "/data", "--console-address", fmt.Sprintf(":%d", consolePort)),
+		gnomock.WithEnv("MINIO_ROOT_USER=" + p.AccessKey),
+		gnomock.WithEnv("MINIO_ROOT_PASSWORD=" + p.SecretKey),
+	}
+
+	if len(p.Buckets) > 0 || p.DataPath != "" {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.AccessKey == "" && p.SecretKey == "" {
+		p.AccessKey = DefaultAccessKey
+		p.SecretKey = DefaultSecretKey
+	}
+}
+
+// healthcheck makes sure that S3 API serves requests signed with the
+// configured credentials. It uses HeadBucket request with a bucket that does
+// not exist, so "not found" response means that the server is ready.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	svc, err := p.client(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = svc.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(healthcheckBucket)})
+	if err == nil {
+		return nil
+	}
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == "NotFound" {
+		return nil
+	}
+
+	return fmt.Errorf("head bucket failed: %w", err)
+}
+
+// initf creates the configured buckets, and then creates a bucket for every
+// top level directory under the data path and uploads its files.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	svc, err := p.client(c)
+	if err != nil {
+		return err
+	}
+
+	for _, bucket := range p.Buckets {
+		if err := createBucket(ctx, svc, bucket); err != nil {
+			return err
+		}
+	}
+
+	if p.DataPath == "" {
+		return nil
+	}
+
+	topLevelDirs, err := os.ReadDir(p.DataPath)
+	if err != nil {
+		return fmt.Errorf("can't read test data path: %w", err)
+	}
+
+	for _, topLevelDir := range topLevelDirs {
+		if !topLevelDir.IsDir() {
+			continue
+		}
+
+		bucket := topLevelDir.Name()
+
+		if err := createBucket(ctx, svc, bucket); err != nil {
+			return err
+		}
+
+		if err := uploadFiles(ctx, svc, bucket, path.Join(p.DataPath, bucket)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *P) client(c *gnomock.Container) (*s3.S3, error) {
+	config := &aws.Config{
+		Region:           aws.String(region),
+		Endpoint:         aws.String(fmt.Sprintf("http://%s", c.DefaultAddress())),
+		S3ForcePathStyle: aws.Bool(true),
+		Credentials:      credentials.NewStaticCredentials(p.AccessKey, p.SecretKey, ""),
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("can't create s3 session: %w", err)
+	}
+
+	return s3.New(sess), nil
+}
+
+// createBucket creates a bucket with the provided name, unless it already
+// exists.
+func createBucket(ctx context.Context, svc *s3.S3, bucket string) error {
+	_, err := svc.CreateBucketWithContext(ctx, &s3.CreateBucketInput{Bucket: aws.String(bucket)})
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeBucketAlreadyOwnedByYou {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("can't create bucket '%s': %w", bucket, err)
+	}
+
+	return nil
+}
+
+// uploadFiles uploads all the files under the provided directory to the
+// bucket, using their paths relative to the directory as keys.
+func uploadFiles(ctx context.Context, svc *s3.S3, bucket, dir string) error {
+	err := filepath.Walk(dir, func(fPath string, file os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("can't read file '%s': %w", fPath, err)
+		}
+
+		if file.IsDir() {
+			return nil
+		}
+
+		return uploadFile(ctx, svc, bucket, dir, fPath)
+	})
+	if err != nil {
+		return fmt.Errorf("can't upload files to bucket '%s': %w", bucket, err)
+	}
+
+	return nil
+}
+
+func uploadFile(ctx context.Context, svc *s3.S3, bucket, dir, file string) (err error) {
+	inputFile, err := os.Open(file) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("can't open file '%s': %w", file, err)
+	}
+
+	defer func() {
+		closeErr := inputFile.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	key, err := filepath.Rel(dir, file)
+	if err != nil {
+		return fmt.Errorf("can't get key of file '%s': %w", file, err)
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(filepath.ToSlash(key)),
+		Body:   inputFile,
+	}
+
+	if _, err := svc.PutObjectWithContext(ctx, input); err != nil {
+		return fmt.Errorf("can't upload file '%s': %w", file, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package minio_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/minio"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"RELEASE.2023-03-20T20-16-18Z", "RELEASE.2023-03-24T21-41-23Z"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := minio.Preset(
+			minio.WithVersion(version),
+			minio.WithCredentials("access", "secret-key"),
+			minio.WithBuckets("empty", "images"),
+			minio.WithData("./testdata"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		svc := client(t, container, "access", "secret-key")
+
+		buckets, err := svc.ListBuckets(&s3.ListBucketsInput{})
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(buckets.Buckets))
+		for _, b := range buckets.Buckets {
+			names = append(names, *b.Name)
+		}
+
+		require.ElementsMatch(t, []string{"empty", "images", "reports"}, names)
+
+		objects, err := svc.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String("reports")})
+		require.NoError(t, err)
+		require.Len(t, objects.Contents, 2)
+
+		obj, err := svc.GetObject(&s3.GetObjectInput{
+			Bucket: aws.String("reports"),
+			Key:    aws.String("2023/march.csv"),
+		})
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, obj.Body.Close()) }()
+
+		bs, err := io.ReadAll(obj.Body)
+		require.NoError(t, err)
+		require.Equal(t, "id,total\n1,42\n", string(bs))
+
+		// wrong credentials are rejected
+		_, err = client(t, container, "access", "wrong-key").ListBuckets(&s3.ListBucketsInput{})
+		require.Error(t, err)
+	}
+}
+
+func TestPreset_wrongDataPath(t *testing.T) {
+	t.Parallel()
+
+	p := minio.Preset(minio.WithData("./testdata/missing"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read test data path")
+}
+
+func client(t *testing.T, c *gnomock.Container, accessKey, secretKey string) *s3.S3 {
+	t.Helper()
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:           aws.String("us-east-1"),
+		Endpoint:         aws.String(fmt.Sprintf("http://%s", c.DefaultAddress())),
+		S3ForcePathStyle: aws.Bool(true),
+		Credentials:      credentials.NewStaticCredentials(accessKey, secretKey, ""),
+	})
+	require.NoError(t, err)
+
+	return s3.New(sess)
+}
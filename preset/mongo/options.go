@@ -15,8 +15,13 @@ type Option func(*P)
 // "second". Under "first" database there are two collections, "one" and "two",
 // and under "second" database - one collection "three".
 //
-// Files "one", "two" and "three" are text files with JSON documents to be
-// inserted into the database. One line should include one document.
+// Files "one", "two" and "three" contain documents to be inserted into the
+// database. Collection name is the file name without its extension. Files
+// with ".bson" extension are read as a sequence of raw BSON documents, such as
+// the files created by mongodump. Any other files are read as documents in
+// extended JSON format, either one document per line, or a single array of
+// documents, such as the files created by "mongoexport --jsonArray".
+// Documents are inserted in batches.
 //
 // Top level files under "path" are ignored, only directories are used.
 // Similarly, directories located anywhere besides top-level "path", are also
@@ -38,7 +43,48 @@ func WithUser(user, pass string) Option {
 	}
 }
 
-// WithVersion sets image version.
+// WithReplicaSet starts mongod as the only member of a replica set with the
+// provided name, and waits until this member becomes primary. Replica set is
+// required to use multi-document transactions and change streams.
+//
+// The member is known to the replica set as "localhost:27017", which is not
+// reachable from the host, so clients should connect to the container
+// directly, for example using "directConnection=true" connection string
+// parameter.
+func WithReplicaSet(name string) Option {
+	return func(p *P) {
+		p.ReplicaSet = name
+	}
+}
+
+// WithDatabaseUser creates a user in the provided database, with the provided
+// roles of this database, such as "read" or "dbAdmin". By default, the user is
+// granted "readWrite" role. Such users allow to connect to the database with
+// limited privileges, but access control is only enabled when a root user is
+// created using WithUser option. Note that the connection string should
+// specify the database of the user as "authSource" parameter.
+//
+// This option can be used multiple times to create multiple users.
+func WithDatabaseUser(db, user, password string, roles ...string) Option {
+	return func(p *P) {
+		p.Users = append(p.Users, User{DB: db, Name: user, Password: password, Roles: roles})
+	}
+}
+
+// WithTLS configures mongod to require TLS for all connections, using the
+// provided PEM encoded certificate and private key. Use TLSConfig to
+// configure clients to trust the certificate. The certificate must be valid
+// for the host used to connect to the container, for example for "127.0.0.1"
+// or "localhost". TLS options of mongod are supported since MongoDB 4.2.
+func WithTLS(certPEM, keyPEM []byte) Option {
+	return func(p *P) {
+		p.TLSCert = string(certPEM)
+		p.TLSKey = string(keyPEM)
+	}
+}
+
+// WithVersion sets image version, which is any tag of the official "mongo"
+// image, for example "6.0" or "4.4.18". Default version is 4.4.
 func WithVersion(version string) Option {
 	return func(o *P) {
 		o.Version = version
@@ -4,6 +4,7 @@
 package mongo
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/orlangure/gnomock"
 	"github.com/orlangure/gnomock/internal/registry"
@@ -20,7 +22,30 @@ import (
 	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const defaultVersion = "4.4"
+const (
+	defaultVersion = "4.4"
+	defaultPort    = 27017
+
+	keyFile = "/gnomock/keyfile"
+
+	primaryTimeout = time.Second * 30
+
+	insertBatchSize = 1000
+
+	userExistsCode = 51003
+)
+
+// keyFileSetup creates a key file used by replica set members to authenticate
+// each other, which is required when access control is enabled.
+const keyFileSetup = `mkdir -p /gnomock
+echo gnomock-replica-set-key > ` + keyFile + `
+chmod 400 ` + keyFile + `
+chown mongodb ` + keyFile + `
+`
+
+// entrypoint starts the container as usual, with the command passed as
+// arguments, once any custom setup is done.
+const entrypoint = `exec docker-entrypoint.sh "$0" "$@"`
 
 func init() {
 	registry.Register("mongo", func() gnomock.Preset { return &P{} })
@@ -47,6 +72,21 @@ type P struct {
 	User     string `json:"user"`
 	Password string `json:"password"`
 	Version  string `json:"version"`
+
+	ReplicaSet string `json:"replica_set"`
+	Users      []User `json:"users"`
+
+	TLSCert string `json:"tls_cert"`
+	TLSKey  string `json:"tls_key"`
+}
+
+// User is a database user with roles scoped to its database, created in the
+// container during initial setup.
+type User struct {
+	DB       string   `json:"db"`
+	Name     string   `json:"name"`
+	Password string   `json:"password"`
+	Roles    []string `json:"roles"`
 }
 
 // Image returns an image that should be pulled to create this container.
@@ -56,7 +96,7 @@ func (p *P) Image() string {
 
 // Ports returns ports that should be used to access this container.
 func (p *P) Ports() gnomock.NamedPorts {
-	return gnomock.DefaultTCP(27017)
+	return gnomock.DefaultTCP(defaultPort)
 }
 
 // Options returns a list of options to configure this container.
@@ -64,13 +104,15 @@ func (p *P) Options() []gnomock.Option {
 	p.setDefaults()
 
 	opts := []gnomock.Option{
-		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithHealthCheck(p.healthcheck),
 	}
 
-	if p.DataPath != "" {
+	if p.DataPath != "" || p.ReplicaSet != "" || len(p.Users) > 0 {
 		opts = append(opts, gnomock.WithInit(p.initf))
 	}
 
+	opts = append(opts, p.mongodOptions()...)
+
 	if p.User != "" && p.Password != "" {
 		opts = append(
 			opts,
@@ -88,15 +130,80 @@ func (p *P) setDefaults() {
 	}
 }
 
-func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+// mongodOptions configure mongod to run as a replica set member, or to
+// require TLS, if any of these features is enabled.
+func (p *P) mongodOptions() []gnomock.Option {
+	cmd := []string{}
+	setup := ""
+
+	if p.ReplicaSet != "" {
+		cmd = append(cmd, "--replSet", p.ReplicaSet)
+
+		if p.useCustomUser() {
+			cmd = append(cmd, "--keyFile", keyFile)
+			setup += keyFileSetup
+		}
+	}
+
+	opts := []gnomock.Option{}
+
+	if p.TLSCert != "" {
+		cmd = append(cmd, "--tlsMode", "requireTLS", "--tlsCertificateKeyFile", tlsFile)
+		setup += tlsSetup
+		opts = append(
+			opts,
+			gnomock.WithEnv("GNOMOCK_TLS_CERT="+p.TLSCert),
+			gnomock.WithEnv("GNOMOCK_TLS_KEY="+p.TLSKey),
+		)
+	}
+
+	if len(cmd) == 0 {
+		return nil
+	}
+
+	opts = append(opts, gnomock.WithCommand("mongod", cmd...))
+
+	// docker resets the command when entrypoint is replaced, so it is always
+	// set explicitly
+	if setup != "" {
+		opts = append(opts, gnomock.WithEntrypoint("/bin/sh", "-c", setup+entrypoint))
+	}
+
+	return opts
+}
+
+// clientOptions returns options of a client connected directly to the
+// container, using TLS if it is enabled. Credentials are only used if auth is
+// true.
+func (p *P) clientOptions(c *gnomock.Container, auth bool) (*mongooptions.ClientOptions, error) {
 	addr := c.Address(gnomock.DefaultPort)
 	uri := "mongodb://" + addr
 
-	if p.useCustomUser() {
+	if auth && p.useCustomUser() {
 		uri = fmt.Sprintf("mongodb://%s:%s@%s", p.User, p.Password, addr)
 	}
 
-	clientOptions := mongooptions.Client().ApplyURI(uri)
+	// replica set members are only reachable from inside the container, so
+	// the client must not try to discover them
+	clientOptions := mongooptions.Client().ApplyURI(uri).SetDirect(true)
+
+	if p.TLSCert != "" {
+		tlsConfig, err := p.tlsConfig(c.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		clientOptions.SetTLSConfig(tlsConfig)
+	}
+
+	return clientOptions, nil
+}
+
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	clientOptions, err := p.clientOptions(c, true)
+	if err != nil {
+		return err
+	}
 
 	client, err := mongodb.NewClient(clientOptions)
 	if err != nil {
@@ -108,6 +215,22 @@ func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
 		return fmt.Errorf("can't connect: %w", err)
 	}
 
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	if p.ReplicaSet != "" {
+		if err := p.initiateReplicaSet(ctx, client); err != nil {
+			return err
+		}
+	}
+
+	if err := p.createUsers(ctx, client); err != nil {
+		return err
+	}
+
+	if p.DataPath == "" {
+		return nil
+	}
+
 	topLevelDirs, err := os.ReadDir(p.DataPath)
 	if err != nil {
 		return fmt.Errorf("can't read test data path: %w", err)
@@ -127,6 +250,73 @@ func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
 	return nil
 }
 
+// initiateReplicaSet creates a single member replica set, and waits until this
+// member becomes primary. Replica set that already exists, for example when
+// the container is reused, is not initiated again.
+func (p *P) initiateReplicaSet(ctx context.Context, client *mongodb.Client) error {
+	admin := client.Database("admin")
+
+	err := admin.RunCommand(ctx, bson.D{
+		{Key: "replSetInitiate", Value: bson.D{
+			{Key: "_id", Value: p.ReplicaSet},
+			{Key: "members", Value: bson.A{
+				bson.D{
+					{Key: "_id", Value: 0},
+					{Key: "host", Value: fmt.Sprintf("localhost:%d", defaultPort)},
+				},
+			}},
+		}},
+	}).Err()
+
+	var cmdErr mongodb.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Name == "AlreadyInitialized") {
+		return fmt.Errorf("can't initiate replica set '%s': %w", p.ReplicaSet, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, primaryTimeout)
+	defer cancel()
+
+	for {
+		var res struct {
+			IsMaster bool `bson:"ismaster"`
+		}
+
+		err := admin.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&res)
+		if err == nil && res.IsMaster {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("replica set member didn't become primary: %w", ctx.Err())
+		case <-time.After(time.Millisecond * 250):
+		}
+	}
+}
+
+func (p *P) createUsers(ctx context.Context, client *mongodb.Client) error {
+	for _, u := range p.Users {
+		roles := u.Roles
+		if len(roles) == 0 {
+			roles = []string{"readWrite"}
+		}
+
+		err := client.Database(u.DB).RunCommand(ctx, bson.D{
+			{Key: "createUser", Value: u.Name},
+			{Key: "pwd", Value: u.Password},
+			{Key: "roles", Value: roles},
+		}).Err()
+
+		// users already exist when an existing container is reused
+		var cmdErr mongodb.CommandError
+		if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Code == userExistsCode) {
+			return fmt.Errorf("can't create user '%s' in database '%s': %w", u.Name, u.DB, err)
+		}
+	}
+
+	return nil
+}
+
 func (p *P) useCustomUser() bool {
 	return p.User != "" && p.Password != ""
 }
@@ -161,40 +351,121 @@ func (p *P) setupCollection(client *mongodb.Client, dirName, dataFileName string
 		return fmt.Errorf("can't open file '%s': %w", dataFileName, err)
 	}
 
-	vr, err := bsonrw.NewExtJSONValueReader(file, false)
+	defer func() { _ = file.Close() }()
+
+	var docs []interface{}
+
+	if path.Ext(dataFileName) == ".bson" {
+		docs, err = readBSON(file)
+	} else {
+		docs, err = readExtJSON(file)
+	}
+
+	if err != nil {
+		return fmt.Errorf("can't read documents from '%s': %w", dataFileName, err)
+	}
+
+	ctx := context.Background()
+	coll := client.Database(dirName).Collection(collectionName)
+
+	for len(docs) > 0 {
+		n := insertBatchSize
+		if len(docs) < n {
+			n = len(docs)
+		}
+
+		_, err = coll.InsertMany(ctx, docs[:n])
+		if err != nil {
+			return fmt.Errorf("can't insert documents from '%s': %w", dataFileName, err)
+		}
+
+		docs = docs[n:]
+	}
+
+	return nil
+}
+
+// readExtJSON reads documents in extended JSON format. Documents can be
+// either separated by whitespace, or wrapped in a single top level array.
+func readExtJSON(r io.Reader) ([]interface{}, error) {
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bs = bytes.TrimSpace(bs)
+
+	if bytes.HasPrefix(bs, []byte("[")) {
+		var wrapper struct {
+			Docs []bson.D `bson:"docs"`
+		}
+
+		bs = append(append([]byte(`{"docs":`), bs...), '}')
+
+		if err := bson.UnmarshalExtJSON(bs, false, &wrapper); err != nil {
+			return nil, err
+		}
+
+		docs := make([]interface{}, 0, len(wrapper.Docs))
+		for _, doc := range wrapper.Docs {
+			docs = append(docs, doc)
+		}
+
+		return docs, nil
+	}
+
+	vr, err := bsonrw.NewExtJSONValueReader(bytes.NewReader(bs), false)
 	if err != nil {
-		return fmt.Errorf("can't read file '%s': %w", dataFileName, err)
+		return nil, err
 	}
 
 	dec, err := bson.NewDecoder(vr)
 	if err != nil {
-		return fmt.Errorf("can't create BSON decoder for '%s': %w", dataFileName, err)
+		return nil, err
 	}
 
-	ctx := context.Background()
+	var docs []interface{}
 
 	for {
-		var val interface{}
+		var doc bson.D
 
-		err = dec.Decode(&val)
+		err = dec.Decode(&doc)
 		if errors.Is(err, io.EOF) {
-			return nil
+			return docs, nil
 		}
 
 		if err != nil {
-			return fmt.Errorf("can't decode file '%s': %w", dataFileName, err)
+			return nil, err
+		}
+
+		docs = append(docs, doc)
+	}
+}
+
+// readBSON reads a sequence of raw BSON documents, such as files created by
+// mongodump.
+func readBSON(r io.Reader) ([]interface{}, error) {
+	var docs []interface{}
+
+	for {
+		doc, err := bson.NewFromIOReader(r)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
 		}
 
-		_, err = client.Database(dirName).Collection(collectionName).InsertOne(ctx, val)
 		if err != nil {
-			return fmt.Errorf("can't insert value from '%s' (%v): %w", dataFileName, val, err)
+			return nil, err
 		}
+
+		docs = append(docs, doc)
 	}
 }
 
-func healthcheck(ctx context.Context, c *gnomock.Container) error {
-	addr := c.Address(gnomock.DefaultPort)
-	clientOptions := mongooptions.Client().ApplyURI("mongodb://" + addr)
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	clientOptions, err := p.clientOptions(c, false)
+	if err != nil {
+		return err
+	}
 
 	client, err := mongodb.NewClient(clientOptions)
 	if err != nil {
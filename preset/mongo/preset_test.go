@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/tlsutil"
 	"github.com/orlangure/gnomock/preset/mongo"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
@@ -17,7 +19,7 @@ import (
 func TestPreset(t *testing.T) {
 	t.Parallel()
 
-	for _, version := range []string{"4.4", "3.6.21"} {
+	for _, version := range []string{"6.0", "5.0", "4.4", "3.6.21"} {
 		t.Run(version, testPreset(version))
 	}
 }
@@ -59,6 +61,18 @@ func testPreset(version string) func(t *testing.T) {
 		count, err = client.Database("db2").Collection("countries").CountDocuments(ctx, bson.D{})
 		require.NoError(t, err)
 		require.Equal(t, int64(3), count)
+
+		count, err = client.Database("db3").Collection("items").CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+
+		var order struct {
+			Created time.Time `bson:"created"`
+		}
+
+		err = client.Database("db3").Collection("orders").FindOne(ctx, bson.D{}).Decode(&order)
+		require.NoError(t, err)
+		require.Equal(t, 2021, order.Created.Year())
 	}
 }
 
@@ -86,6 +100,95 @@ func TestPreset_withDefaults(t *testing.T) {
 	require.NoError(t, client.Disconnect(ctx))
 }
 
+func TestPreset_withReplicaSet(t *testing.T) {
+	t.Parallel()
+
+	p := mongo.Preset(
+		mongo.WithReplicaSet("rs0"),
+		mongo.WithUser("gnomock", "gnomick"),
+	)
+	c, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(c)) }()
+
+	require.NoError(t, err)
+
+	uri := fmt.Sprintf("mongodb://gnomock:gnomick@%s/?directConnection=true", c.DefaultAddress())
+	clientOptions := mongooptions.Client().ApplyURI(uri)
+
+	client, err := mongodb.NewClient(clientOptions)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	require.NoError(t, client.Connect(ctx))
+
+	defer func() { require.NoError(t, client.Disconnect(ctx)) }()
+
+	coll := client.Database("db").Collection("accounts")
+	_, err = coll.InsertOne(ctx, bson.M{"name": "setup"})
+	require.NoError(t, err)
+
+	session, err := client.StartSession()
+	require.NoError(t, err)
+
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongodb.SessionContext) (interface{}, error) {
+		if _, err := coll.InsertOne(sc, bson.M{"name": "foo"}); err != nil {
+			return nil, err
+		}
+
+		return coll.InsertOne(sc, bson.M{"name": "bar"})
+	})
+	require.NoError(t, err)
+
+	count, err := coll.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+}
+
+func TestPreset_withDatabaseUser(t *testing.T) {
+	t.Parallel()
+
+	p := mongo.Preset(
+		mongo.WithUser("gnomock", "gnomick"),
+		mongo.WithDatabaseUser("app", "reader", "r34d3r", "read"),
+		mongo.WithDatabaseUser("app", "writer", "wr1t3r"),
+	)
+	c, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(c)) }()
+
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	connect := func(user, password string) *mongodb.Client {
+		uri := fmt.Sprintf("mongodb://%s:%s@%s/?authSource=app", user, password, c.DefaultAddress())
+
+		client, err := mongodb.NewClient(mongooptions.Client().ApplyURI(uri))
+		require.NoError(t, err)
+		require.NoError(t, client.Connect(ctx))
+
+		return client
+	}
+
+	writer := connect("writer", "wr1t3r")
+	_, err = writer.Database("app").Collection("things").InsertOne(ctx, bson.M{"a": 1})
+	require.NoError(t, err)
+	_, err = writer.Database("other").Collection("things").InsertOne(ctx, bson.M{"a": 1})
+	require.Error(t, err)
+	require.NoError(t, writer.Disconnect(ctx))
+
+	reader := connect("reader", "r34d3r")
+	count, err := reader.Database("app").Collection("things").CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+	_, err = reader.Database("app").Collection("things").InsertOne(ctx, bson.M{"a": 2})
+	require.Error(t, err)
+	require.NoError(t, reader.Disconnect(ctx))
+}
+
 func TestPreset_wrongDataFolder(t *testing.T) {
 	t.Parallel()
 
@@ -94,3 +197,42 @@ func TestPreset_wrongDataFolder(t *testing.T) {
 	require.Error(t, err)
 	require.NoError(t, gnomock.Stop(c))
 }
+
+func TestPreset_withTLS(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM, err := tlsutil.SelfSignedCert()
+	require.NoError(t, err)
+
+	opts := []mongo.Option{
+		mongo.WithTLS(certPEM, keyPEM),
+		mongo.WithUser("gnomock", "gnomick"),
+	}
+	c, err := gnomock.Start(mongo.Preset(opts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(c)) }()
+
+	require.NoError(t, err)
+
+	tlsConfig, err := mongo.TLSConfig(c, opts...)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	uri := fmt.Sprintf("mongodb://gnomock:gnomick@%s", c.DefaultAddress())
+	clientOptions := mongooptions.Client().ApplyURI(uri).SetTLSConfig(tlsConfig)
+
+	client, err := mongodb.Connect(ctx, clientOptions)
+	require.NoError(t, err)
+	require.NoError(t, client.Ping(ctx, nil))
+	require.NoError(t, client.Disconnect(ctx))
+
+	// plain text connections are rejected
+	clientOptions = mongooptions.Client().
+		ApplyURI(uri).
+		SetServerSelectionTimeout(time.Second * 3)
+
+	client, err = mongodb.Connect(ctx, clientOptions)
+	require.NoError(t, err)
+	require.Error(t, client.Ping(ctx, nil))
+	require.NoError(t, client.Disconnect(ctx))
+}
@@ -0,0 +1,37 @@
+package mongo
+
+import (
+	"crypto/tls"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/tlsutil"
+)
+
+const tlsFile = "/gnomock/tls.pem"
+
+// tlsSetup writes server certificate and key from GNOMOCK_TLS_CERT and
+// GNOMOCK_TLS_KEY variables into a single file, as mongod expects.
+const tlsSetup = `mkdir -p /gnomock
+(umask 077 && printf '%s\n%s\n' "$GNOMOCK_TLS_CERT" "$GNOMOCK_TLS_KEY" > ` + tlsFile + `)
+chown mongodb ` + tlsFile + `
+`
+
+// TLSConfig returns TLS configuration that trusts the certificate provided
+// using WithTLS option, and can be used to verify the server in the provided
+// container, for example using SetTLSConfig of mongo client options. Use the
+// same options that were used to create the preset. The certificate must be
+// valid for the host that runs the container, for example for "127.0.0.1" or
+// "localhost".
+func TLSConfig(c *gnomock.Container, opts ...Option) (*tls.Config, error) {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p.tlsConfig(c.Host)
+}
+
+func (p *P) tlsConfig(host string) (*tls.Config, error) {
+	return tlsutil.Config(p.TLSCert, host)
+}
@@ -0,0 +1,49 @@
+package mosquitto
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithUser requires clients to authenticate using the provided credentials.
+// If not used, anonymous clients are allowed.
+func WithUser(user, password string) Option {
+	return func(o *P) {
+		o.User = user
+		o.Password = password
+	}
+}
+
+// WithTLS adds a listener that accepts MQTT connections over TLS on TLSPort,
+// using the provided PEM encoded certificate and private key. Clients are not
+// required to present certificates. The default port keeps accepting plain
+// text connections. Use TLSConfig to configure clients to trust this
+// certificate.
+func WithTLS(certPEM, keyPEM []byte) Option {
+	return func(o *P) {
+		o.TLSCert = string(certPEM)
+		o.TLSKey = string(keyPEM)
+	}
+}
+
+// WithRetainedMessages publishes the provided payloads to the provided topics
+// as retained messages once the container is ready, so that the tested code
+// receives them when it subscribes. This option can be used multiple times;
+// later payloads of the same topic replace earlier ones.
+func WithRetainedMessages(messages map[string]string) Option {
+	return func(o *P) {
+		if o.Retained == nil {
+			o.Retained = make(map[string]string, len(messages))
+		}
+
+		for topic, payload := range messages {
+			o.Retained[topic] = payload
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package mosquitto includes Eclipse Mosquitto implementation of Gnomock
+// Preset interface. This Preset can be passed to gnomock.Start() function to
+// create a configured Mosquitto MQTT broker to use in tests.
+//
+// The default port accepts plain text MQTT connections. When TLS is
+// configured using WithTLS, TLSPort accepts MQTT connections over TLS.
+package mosquitto
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// TLSPort is a name of the port exposed by Mosquitto containers in addition
+// to the default port, when TLS is configured.
+const TLSPort = "tls"
+
+const (
+	defaultVersion = "2.0.15"
+	defaultPort    = 1883
+	tlsPort        = 8883
+	connectTimeout = time.Second * 5
+)
+
+// entrypoint writes broker configuration from MOSQUITTO_CONFIG variable, and
+// the password file and TLS certificate and key when they are configured,
+// and starts the broker.
+const entrypoint = `mkdir -p /gnomock
+printf '%s' "$MOSQUITTO_CONFIG" > /gnomock/mosquitto.conf
+if [ -n "$MOSQUITTO_USER" ]; then
+	mosquitto_passwd -b -c /gnomock/passwd "$MOSQUITTO_USER" "$MOSQUITTO_PASSWORD"
+	chmod 0700 /gnomock/passwd
+fi
+if [ -n "$MOSQUITTO_TLS_CERT" ]; then
+	printf '%s' "$MOSQUITTO_TLS_CERT" > /gnomock/server.crt
+	(umask 077 && printf '%s' "$MOSQUITTO_TLS_KEY" > /gnomock/server.key)
+fi
+chown -R mosquitto:mosquitto /gnomock
+exec mosquitto -c /gnomock/mosquitto.conf`
+
+func init() {
+	registry.Register("mosquitto", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Mosquitto preset. This preset includes a
+// Mosquitto specific healthcheck function and default Mosquitto image and
+// ports, and allows to optionally require authentication, enable TLS and
+// publish retained messages.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Mosquitto.
+type P struct {
+	Version  string            `json:"version"`
+	User     string            `json:"user"`
+	Password string            `json:"password"`
+	TLSCert  string            `json:"tls_cert"`
+	TLSKey   string            `json:"tls_key"`
+	Retained map[string]string `json:"retained"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/library/eclipse-mosquitto:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+
+	if p.TLSCert != "" {
+		namedPorts[TLSPort] = gnomock.Port{Protocol: "tcp", Port: tlsPort}
+	}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithEnv("MOSQUITTO_CONFIG=" + p.config()),
+		gnomock.WithEntrypoint("/bin/sh", "-c", entrypoint),
+	}
+
+	if p.User != "" {
+		opts = append(
+			opts,
+			gnomock.WithEnv("MOSQUITTO_USER="+p.User),
+			gnomock.WithEnv("MOSQUITTO_PASSWORD="+p.Password),
+		)
+	}
+
+	if p.TLSCert != "" {
+		opts = append(
+			opts,
+			gnomock.WithEnv("MOSQUITTO_TLS_CERT="+p.TLSCert),
+			gnomock.WithEnv("MOSQUITTO_TLS_KEY="+p.TLSKey),
+		)
+	}
+
+	if len(p.Retained) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// config returns broker configuration. Anonymous clients are allowed unless
+// a user is configured.
+func (p *P) config() string {
+	lines := []string{
+		"persistence false",
+		"log_dest stdout",
+		fmt.Sprintf("listener %d", defaultPort),
+	}
+
+	if p.User != "" {
+		lines = append(lines, "allow_anonymous false", "password_file /gnomock/passwd")
+	} else {
+		lines = append(lines, "allow_anonymous true")
+	}
+
+	if p.TLSCert != "" {
+		lines = append(
+			lines,
+			fmt.Sprintf("listener %d", tlsPort),
+			"certfile /gnomock/server.crt",
+			"keyfile /gnomock/server.key",
+		)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// healthcheck makes sure that the broker accepts MQTT connections with the
+// configured credentials.
+func (p *P) healthcheck(_ context.Context, c *gnomock.Container) error {
+	client, err := p.connect(c, "gnomock-healthcheck")
+	if err != nil {
+		return err
+	}
+
+	client.Disconnect(0)
+
+	return nil
+}
+
+// initf publishes the configured retained messages in order of their topics.
+func (p *P) initf(_ context.Context, c *gnomock.Container) error {
+	client, err := p.connect(c, "gnomock-init")
+	if err != nil {
+		return err
+	}
+
+	defer client.Disconnect(0)
+
+	topics := make([]string, 0, len(p.Retained))
+
+	for topic := range p.Retained {
+		topics = append(topics, topic)
+	}
+
+	sort.Strings(topics)
+
+	for _, topic := range topics {
+		token := client.Publish(topic, 1, true, p.Retained[topic])
+		if !token.WaitTimeout(connectTimeout) {
+			return fmt.Errorf("can't publish to '%s': timeout", topic)
+		}
+
+		if err := token.Error(); err != nil {
+			return fmt.Errorf("can't publish to '%s': %w", topic, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *P) connect(c *gnomock.Container, clientID string) (mqtt.Client, error) {
+	opts := mqtt.NewClientOptions().
+		AddBroker("tcp://" + c.DefaultAddress()).
+		SetClientID(clientID).
+		SetUsername(p.User).
+		SetPassword(p.Password).
+		SetConnectTimeout(connectTimeout).
+		SetAutoReconnect(false)
+
+	client := mqtt.NewClient(opts)
+
+	token := client.Connect()
+	if !token.WaitTimeout(connectTimeout) {
+		return nil, fmt.Errorf("can't connect: timeout")
+	}
+
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("can't connect: %w", err)
+	}
+
+	return client, nil
+}
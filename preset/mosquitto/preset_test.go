@@ -0,0 +1,105 @@
+package mosquitto_test
+
+import (
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/tlsutil"
+	"github.com/orlangure/gnomock/preset/mosquitto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"2.0.14", "2.0.15"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := mosquitto.Preset(
+			mosquitto.WithVersion(version),
+			mosquitto.WithUser("gnomock", "gnomick"),
+			mosquitto.WithRetainedMessages(map[string]string{"devices/1/state": "on"}),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		anonymous := mqtt.NewClientOptions().AddBroker("tcp://" + container.DefaultAddress())
+		token := mqtt.NewClient(anonymous).Connect()
+		require.True(t, token.WaitTimeout(time.Second*5))
+		require.Error(t, token.Error())
+
+		opts := mqtt.NewClientOptions().
+			AddBroker("tcp://" + container.DefaultAddress()).
+			SetUsername("gnomock").
+			SetPassword("gnomick")
+
+		require.Equal(t, "on", receiveRetained(t, opts, "devices/+/state"))
+	}
+}
+
+func TestPreset_withTLS(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM, err := tlsutil.SelfSignedCert()
+	require.NoError(t, err)
+	presetOpts := []mosquitto.Option{
+		mosquitto.WithTLS(certPEM, keyPEM),
+		mosquitto.WithRetainedMessages(map[string]string{"config": "{}"}),
+	}
+	container, err := gnomock.Start(mosquitto.Preset(presetOpts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	tlsConfig, err := mosquitto.TLSConfig(container, presetOpts...)
+	require.NoError(t, err)
+
+	opts := mqtt.NewClientOptions().
+		AddBroker("ssl://" + container.Address(mosquitto.TLSPort)).
+		SetTLSConfig(tlsConfig)
+
+	require.Equal(t, "{}", receiveRetained(t, opts, "config"))
+
+	// plain text connections are accepted on the default port
+	opts = mqtt.NewClientOptions().AddBroker("tcp://" + container.DefaultAddress())
+	require.Equal(t, "{}", receiveRetained(t, opts, "config"))
+}
+
+func receiveRetained(t *testing.T, opts *mqtt.ClientOptions, topic string) string {
+	t.Helper()
+
+	client := mqtt.NewClient(opts)
+
+	token := client.Connect()
+	require.True(t, token.WaitTimeout(time.Second*5))
+	require.NoError(t, token.Error())
+
+	defer client.Disconnect(0)
+
+	messages := make(chan mqtt.Message, 1)
+
+	token = client.Subscribe(topic, 1, func(_ mqtt.Client, m mqtt.Message) { messages <- m })
+	require.True(t, token.WaitTimeout(time.Second*5))
+	require.NoError(t, token.Error())
+
+	select {
+	case m := <-messages:
+		require.True(t, m.Retained())
+
+		return string(m.Payload())
+	case <-time.After(time.Second * 5):
+		require.FailNow(t, "retained message not received")
+	}
+
+	return ""
+}
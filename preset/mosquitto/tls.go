@@ -0,0 +1,23 @@
+package mosquitto
+
+import (
+	"crypto/tls"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/tlsutil"
+)
+
+// TLSConfig returns TLS configuration that trusts the certificate provided
+// using WithTLS option, and can be used to verify the broker in the provided
+// container when connecting to TLSPort. Use the same options that were used
+// to create the preset. The certificate must be valid for the host that runs
+// the container, for example for "127.0.0.1" or "localhost".
+func TLSConfig(c *gnomock.Container, opts ...Option) (*tls.Config, error) {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return tlsutil.Config(p.TLSCert, c.Host)
+}
@@ -21,14 +21,35 @@ func WithDatabase(db string) Option {
 	}
 }
 
+// WithCollation sets the collation of the database created in the container,
+// for example "Latin1_General_CI_AS". If not provided, the server default
+// collation is used.
+func WithCollation(collation string) Option {
+	return func(o *P) {
+		o.Collation = collation
+	}
+}
+
 // WithQueries executes the provided queries against the database created with
-// WithDatabase, or against default "mydb" database.
+// WithDatabase, or against default "mydb" database. If any of them fails, the
+// returned error includes the failed query.
 func WithQueries(queries ...string) Option {
 	return func(o *P) {
 		o.Queries = append(o.Queries, queries...)
 	}
 }
 
+// WithTransaction runs all the setup queries, including the ones from
+// WithQueriesFile and CSV seeds, in a single transaction. If any of them
+// fails, none of the changes are applied. Statements that are not allowed
+// inside a transaction, such as ALTER DATABASE, CREATE FULLTEXT INDEX or
+// BACKUP, can't be used with this option.
+func WithTransaction() Option {
+	return func(o *P) {
+		o.Transaction = true
+	}
+}
+
 // WithLicense sets EULA acceptance state. To accept the license, use true. See
 // https://hub.docker.com/_/microsoft-mssql-server?tab=description for more
 // information.
@@ -38,6 +59,15 @@ func WithLicense(accept bool) Option {
 	}
 }
 
+// WithAgent enables SQL Server Agent in the container. When enabled, the
+// container is considered ready only when the Agent is running, so jobs and
+// schedules can be used right away.
+func WithAgent() Option {
+	return func(o *P) {
+		o.Agent = true
+	}
+}
+
 // WithQueriesFile sets a file name to read initial queries from. Queries from
 // this file are executed before any other queries provided in WithQueries.
 func WithQueriesFile(file string) Option {
@@ -46,6 +76,157 @@ func WithQueriesFile(file string) Option {
 	}
 }
 
+// WithAzureSQLEdge makes the preset use Azure SQL Edge image instead of
+// Microsoft SQL Server image. Azure SQL Edge is built for both amd64 and arm64
+// architectures, so it can be used on hosts where SQL Server image is not
+// available, for example on Apple Silicon. Use WithVersion to select one of
+// Azure SQL Edge tags (default: 1.0.7).
+//
+// Azure SQL Edge shares the database engine with SQL Server, but some features
+// are not supported, for example SQL Server Agent. See
+// https://learn.microsoft.com/en-us/azure/azure-sql-edge/features for more
+// information.
+func WithAzureSQLEdge() Option {
+	return func(o *P) {
+		o.AzureSQLEdge = true
+	}
+}
+
+// WithDataVolume persists SQL Server data directory (/var/opt/mssql) across
+// container restarts. If an absolute path is provided, this host path is
+// mounted into the container. Otherwise, the value is used as a name of a
+// docker volume, which is created if it doesn't exist.
+//
+// When the database already exists in the provided volume, for example when a
+// long-lived development container is started again, it is used as-is: the
+// database is not created, and none of the queries are executed.
+//
+// Note that SQL Server 2019 and newer images run as a non-root user, and this
+// user must have write access to the mounted host path.
+func WithDataVolume(nameOrPath string) Option {
+	return func(o *P) {
+		o.DataVolume = nameOrPath
+	}
+}
+
+// WithFullTextSearch enables SQL Server full-text search feature. Official
+// SQL Server images do not include it, so when this option is used, the
+// feature is installed from Microsoft package repository when the container
+// starts. This requires network access, makes the startup noticeably slower,
+// and makes the server process run as root. The feature is verified to be
+// installed during initial setup.
+//
+// This option is not supported by Azure SQL Edge images.
+func WithFullTextSearch() Option {
+	return func(o *P) {
+		o.FullText = true
+	}
+}
+
+// WithCSVSeed loads data from a local CSV file into the provided table during
+// initial setup using BULK INSERT. The file is mounted into the container, so
+// it must be readable by SQL Server process. The first line of the file must
+// be a header, and is skipped. The table must exist by the time the data is
+// loaded, for example it can be created using WithQueries.
+//
+// This option can be used multiple times. The data is loaded after all the
+// queries are executed, in the same transaction. This option requires SQL
+// Server 2017 or newer.
+func WithCSVSeed(table, csvPath string) Option {
+	return func(o *P) {
+		o.CSVSeeds = append(o.CSVSeeds, CSVSeed{Table: table, Path: csvPath})
+	}
+}
+
+// WithPort sets the port SQL Server listens on inside the container
+// (MSSQL_TCP_PORT). By default, port 1433 is used. This port is still
+// published on a random host port, available in `Container.DefaultPort()`.
+func WithPort(port int) Option {
+	return func(o *P) {
+		o.Port = port
+	}
+}
+
+// WithInstance sets SQL Server instance name used in connection strings
+// created by this package. Official SQL Server images always run the default
+// instance, so this option is useful to reproduce connection settings used
+// with named instances in other environments. Since the connection strings
+// always include the port, SQL Server Browser service is not required.
+func WithInstance(name string) Option {
+	return func(o *P) {
+		o.Instance = name
+	}
+}
+
+// WithAttachedDatabase creates the database by attaching existing SQL Server
+// data (.mdf) and log (.ldf) files instead of creating an empty one. The
+// files are copied into the container on startup, so the originals are never
+// modified, but they must be readable by any user. If log file path is empty,
+// a new log file is created.
+//
+// Queries and other seed data are applied to the attached database as usual.
+// Collation option is ignored when attaching a database.
+func WithAttachedDatabase(mdfPath, ldfPath string) Option {
+	return func(o *P) {
+		o.AttachMDF = mdfPath
+		o.AttachLDF = ldfPath
+	}
+}
+
+// WithMigrations applies golang-migrate migrations from the provided source
+// URL, for example "file://migrations", to the database during initial setup.
+// Migrations run after the database is created, and before any queries or
+// other seed data are applied, so they can be used to create the schema.
+func WithMigrations(sourceURL string) Option {
+	return func(o *P) {
+		o.Migrations = sourceURL
+	}
+}
+
+// WithTLS configures SQL Server to use the provided PEM encoded certificate
+// and private key, and to require encryption for all connections. Only TLS
+// 1.2 is enabled. The certificate must be valid for the host used to connect
+// to the container, see TLSConfig.
+func WithTLS(certPEM, keyPEM []byte) Option {
+	return func(o *P) {
+		o.TLSCert = string(certPEM)
+		o.TLSKey = string(keyPEM)
+	}
+}
+
+// WithProductID sets SQL Server edition or product key (MSSQL_PID), for
+// example "Express", "Standard" or "Enterprise". By default, "Developer"
+// edition is used.
+func WithProductID(pid string) Option {
+	return func(o *P) {
+		o.ProductID = pid
+	}
+}
+
+// WithLCID sets SQL Server locale identifier (MSSQL_LCID), which affects the
+// default server collation and language, for example 1036 for French.
+func WithLCID(lcid int) Option {
+	return func(o *P) {
+		o.LCID = lcid
+	}
+}
+
+// WithMemoryLimit sets the maximum amount of memory, in megabytes, that SQL
+// Server can use (MSSQL_MEMORY_LIMIT_MB).
+func WithMemoryLimit(mb int) Option {
+	return func(o *P) {
+		o.MemoryLimit = mb
+	}
+}
+
+// WithTraceFlags enables the provided trace flags globally on server startup,
+// as if they were passed using `-T` command line argument.
+func WithTraceFlags(flags ...int) Option {
+	return func(o *P) {
+		o.TraceFlags = append(o.TraceFlags, flags...)
+	}
+}
+
 // WithVersion sets image version.
 func WithVersion(version string) Option {
 	return func(o *P) {
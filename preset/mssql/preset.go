@@ -3,13 +3,24 @@ package mssql
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
+	"errors"
 	"fmt"
-	"os"
-
-	_ "github.com/denisenkom/go-mssqldb" // mssql driver
+	"net"
+	"net/url"
+	"path/filepath"
+	"strconv"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/denisenkom/go-mssqldb/msdsn"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/sqlserver"
 	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/hostpath"
 	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+	"github.com/orlangure/gnomock/internal/tlsutil"
 )
 
 const (
@@ -18,8 +29,58 @@ const (
 	defaultDatabase = "mydb"
 	defaultPort     = 1433
 	defaultVersion  = "2019-latest"
+
+	defaultAzureSQLEdgeVersion = "1.0.7"
+
+	dataDir = "/var/opt/mssql"
+	server  = "/opt/mssql/bin/sqlservr"
+	csvDir  = "/gnomock/csv"
+
+	attachDir = "/gnomock/attach"
+	attachMDF = dataDir + "/data/gnomock-attach.mdf"
+	attachLDF = dataDir + "/data/gnomock-attach.ldf"
 )
 
+// fullTextSetup installs full-text search package from Microsoft repository.
+// GNOMOCK_MSSQL_RELEASE variable holds sql server major version, for example
+// 2019, and is used to pick the right repository.
+const fullTextSetup = `. /etc/os-release
+apt-get update
+apt-get install -y gnupg curl apt-transport-https
+curl -fsSL https://packages.microsoft.com/keys/microsoft.asc | apt-key add -
+curl -fsSL https://packages.microsoft.com/config/ubuntu/${VERSION_ID}/mssql-server-${GNOMOCK_MSSQL_RELEASE}.list \
+	-o /etc/apt/sources.list.d/mssql-server.list
+apt-get update
+apt-get install -y mssql-server-fts
+`
+
+// tlsSetup writes server certificate and key from GNOMOCK_TLS_CERT and
+// GNOMOCK_TLS_KEY variables, and configures sql server to use them for all
+// connections. Existing network section is replaced, so that restarting a
+// container with a persistent data volume does not duplicate it.
+const tlsSetup = `printf '%s' "$GNOMOCK_TLS_CERT" > /var/opt/mssql/gnomock-tls.pem
+(umask 077 && printf '%s' "$GNOMOCK_TLS_KEY" > /var/opt/mssql/gnomock-tls.key)
+touch /var/opt/mssql/mssql.conf
+awk '/^\[/ { skip = ($0 == "[network]") } !skip' /var/opt/mssql/mssql.conf > /tmp/gnomock-mssql.conf
+cat /tmp/gnomock-mssql.conf - > /var/opt/mssql/mssql.conf <<EOF
+[network]
+tlscert = /var/opt/mssql/gnomock-tls.pem
+tlskey = /var/opt/mssql/gnomock-tls.key
+tlsprotocols = 1.2
+forceencryption = 1
+EOF
+`
+
+// attachSetup copies database files mounted for attachment into sql server
+// data directory, so that the server can modify them without affecting the
+// original files. Existing files are not overwritten.
+const attachSetup = `mkdir -p /var/opt/mssql/data
+for f in /gnomock/attach/*; do
+	dst=/var/opt/mssql/data/gnomock-attach.${f##*.}
+	[ -f "$dst" ] || cp "$f" "$dst"
+done
+`
+
 func init() {
 	registry.Register("mssql", func() gnomock.Preset { return &P{} })
 }
@@ -31,6 +92,14 @@ func init() {
 // When used without any configuration, it uses `mydb` database, and `Gn0m!ck~`
 // administrator password (user: `sa`). You must accept EULA to use this image
 // (`WithLicense` option). By default, version `2019-latest` is used.
+//
+// SQL Server images are not available for arm64 architecture. To run the
+// tests on such hosts, use `WithAzureSQLEdge` option.
+//
+// This preset does not limit the time it takes the container to become ready,
+// so the default Gnomock timeout applies. Slow environments, such as CI runners
+// pulling the image for the first time, may require a longer timeout: use
+// `gnomock.WithTimeout` together with this preset to change it.
 func Preset(opts ...Option) gnomock.Preset {
 	p := &P{}
 
@@ -43,22 +112,54 @@ func Preset(opts ...Option) gnomock.Preset {
 
 // P is a Gnomock Preset implementation of Microsoft SQL Server database.
 type P struct {
-	DB           string   `json:"db"`
-	Password     string   `json:"password"`
-	Queries      []string `json:"queries"`
-	QueriesFiles []string `json:"queries_files"`
-	License      bool     `json:"license"`
-	Version      string   `json:"version"`
+	DB           string    `json:"db"`
+	Password     string    `json:"password"`
+	Queries      []string  `json:"queries"`
+	QueriesFiles []string  `json:"queries_files"`
+	Transaction  bool      `json:"transaction"`
+	License      bool      `json:"license"`
+	Version      string    `json:"version"`
+	Collation    string    `json:"collation"`
+	Agent        bool      `json:"agent"`
+	AzureSQLEdge bool      `json:"azure_sql_edge"`
+	DataVolume   string    `json:"data_volume"`
+	FullText     bool      `json:"full_text"`
+	ProductID    string    `json:"product_id"`
+	LCID         int       `json:"lcid"`
+	MemoryLimit  int       `json:"memory_limit_mb"`
+	TraceFlags   []int     `json:"trace_flags"`
+	CSVSeeds     []CSVSeed `json:"csv_seeds"`
+	Port         int       `json:"port"`
+	Instance     string    `json:"instance"`
+	TLSCert      string    `json:"tls_cert"`
+	TLSKey       string    `json:"tls_key"`
+	AttachMDF    string    `json:"attach_mdf"`
+	AttachLDF    string    `json:"attach_ldf"`
+	Migrations   string    `json:"migrations"`
+}
+
+// CSVSeed is a CSV file with data to load into a table on initial setup.
+type CSVSeed struct {
+	Table string `json:"table"`
+	Path  string `json:"path"`
 }
 
 // Image returns an image that should be pulled to create this container.
 func (p *P) Image() string {
+	if p.AzureSQLEdge {
+		return fmt.Sprintf("mcr.microsoft.com/azure-sql-edge:%s", p.Version)
+	}
+
 	return fmt.Sprintf("mcr.microsoft.com/mssql/server:%s", p.Version)
 }
 
 // Ports returns ports that should be used to access this container.
 func (p *P) Ports() gnomock.NamedPorts {
-	return gnomock.DefaultTCP(defaultPort)
+	if p.Port == 0 {
+		return gnomock.DefaultTCP(defaultPort)
+	}
+
+	return gnomock.DefaultTCP(p.Port)
 }
 
 // Options returns a list of options to configure this container.
@@ -67,14 +168,99 @@ func (p *P) Options() []gnomock.Option {
 
 	opts := []gnomock.Option{
 		gnomock.WithHealthCheck(p.healthcheck),
-		gnomock.WithEnv("SA_PASSWORD=" + p.Password),
 		gnomock.WithInit(p.initf()),
 	}
 
+	// azure sql edge only supports the newer variable name, while older
+	// versions of sql server only support the legacy one
+	if p.AzureSQLEdge {
+		opts = append(opts, gnomock.WithEnv("MSSQL_SA_PASSWORD="+p.Password))
+	} else {
+		opts = append(opts, gnomock.WithEnv("SA_PASSWORD="+p.Password))
+	}
+
 	if p.License {
 		opts = append(opts, gnomock.WithEnv("ACCEPT_EULA=Y"))
 	}
 
+	if p.Agent {
+		opts = append(opts, gnomock.WithEnv("MSSQL_AGENT_ENABLED=true"))
+	}
+
+	if p.Port != defaultPort {
+		opts = append(opts, gnomock.WithEnv("MSSQL_TCP_PORT="+strconv.Itoa(p.Port)))
+	}
+
+	if p.ProductID != "" {
+		opts = append(opts, gnomock.WithEnv("MSSQL_PID="+p.ProductID))
+	}
+
+	if p.LCID > 0 {
+		opts = append(opts, gnomock.WithEnv("MSSQL_LCID="+strconv.Itoa(p.LCID)))
+	}
+
+	if p.MemoryLimit > 0 {
+		opts = append(opts, gnomock.WithEnv("MSSQL_MEMORY_LIMIT_MB="+strconv.Itoa(p.MemoryLimit)))
+	}
+
+	for i, seed := range p.CSVSeeds {
+		opts = append(opts, gnomock.WithHostMounts(hostpath.Abs(seed.Path), csvFile(i)))
+	}
+
+	args := make([]string, 0, len(p.TraceFlags))
+	for _, flag := range p.TraceFlags {
+		args = append(args, "-T"+strconv.Itoa(flag))
+	}
+
+	// some features require additional setup before the server starts, so
+	// they replace image entrypoint with a script that does this setup, and
+	// then runs the server with the same arguments
+	script := ""
+
+	if p.FullText {
+		script += fullTextSetup
+		opts = append(
+			opts,
+			gnomock.WithUser("root"),
+			gnomock.WithEnv("GNOMOCK_MSSQL_RELEASE="+p.serverRelease()),
+		)
+	}
+
+	if p.AttachMDF != "" {
+		script += attachSetup
+		opts = append(opts, gnomock.WithHostMounts(hostpath.Abs(p.AttachMDF), attachDir+"/data.mdf"))
+
+		if p.AttachLDF != "" {
+			opts = append(opts, gnomock.WithHostMounts(hostpath.Abs(p.AttachLDF), attachDir+"/data.ldf"))
+		}
+	}
+
+	if p.TLSCert != "" {
+		script += tlsSetup
+		opts = append(
+			opts,
+			gnomock.WithEnv("GNOMOCK_TLS_CERT="+p.TLSCert),
+			gnomock.WithEnv("GNOMOCK_TLS_KEY="+p.TLSKey),
+		)
+	}
+
+	switch {
+	case script != "":
+		script = "set -e\n" + script + "exec " + server + ` "$@"`
+		args = append([]string{"-c", script, "mssql"}, args...)
+		opts = append(opts, gnomock.WithEntrypoint("/bin/bash", args...))
+	case len(args) > 0:
+		opts = append(opts, gnomock.WithCommand(server, args...))
+	}
+
+	if p.DataVolume != "" {
+		if filepath.IsAbs(p.DataVolume) {
+			opts = append(opts, gnomock.WithHostMounts(p.DataVolume, dataDir))
+		} else {
+			opts = append(opts, gnomock.WithVolume(p.DataVolume, dataDir))
+		}
+	}
+
 	return opts
 }
 
@@ -92,7 +278,103 @@ func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
 
 	var one int
 
-	return db.QueryRow(`select 1`).Scan(&one)
+	if err := db.QueryRow(`select 1`).Scan(&one); err != nil {
+		return err
+	}
+
+	if err := p.databaseHealthcheck(addr, db); err != nil {
+		return err
+	}
+
+	if p.Agent {
+		return agentHealthcheck(db)
+	}
+
+	return nil
+}
+
+// databaseHealthcheck returns an error if the configured database exists, but
+// is not yet ready to use. This can happen when the database is recovered
+// from existing data files on startup. If the database does not exist, it is
+// created later during initial setup.
+func (p *P) databaseHealthcheck(addr string, master *sql.DB) error {
+	var state string
+
+	err := master.QueryRow(`select state_desc from sys.databases where name = @p1`, p.DB).Scan(&state)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("can't get database '%s' state: %w", p.DB, err)
+	}
+
+	if state != "ONLINE" {
+		return fmt.Errorf("database '%s' is not online: %s", p.DB, state)
+	}
+
+	db, err := p.connect(addr, p.DB)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	var one int
+
+	if err := db.QueryRow(`select 1`).Scan(&one); err != nil {
+		return fmt.Errorf("database '%s' is not accessible: %w", p.DB, err)
+	}
+
+	return nil
+}
+
+// agentHealthcheck returns an error unless SQL Server Agent is connected to
+// the server.
+func agentHealthcheck(db *sql.DB) error {
+	var sessions int
+
+	err := db.QueryRow(
+		`select count(*) from sys.dm_exec_sessions where program_name like 'SQLAgent%'`,
+	).Scan(&sessions)
+	if err != nil {
+		return fmt.Errorf("can't get agent status: %w", err)
+	}
+
+	if sessions == 0 {
+		return fmt.Errorf("sql server agent is not running")
+	}
+
+	return nil
+}
+
+// fullTextCheck returns an error unless full-text search feature is
+// installed on the server.
+func fullTextCheck(db *sql.DB) error {
+	var installed int
+
+	err := db.QueryRow(`select fulltextserviceproperty('IsFullTextInstalled')`).Scan(&installed)
+	if err != nil {
+		return fmt.Errorf("can't get full-text search status: %w", err)
+	}
+
+	if installed != 1 {
+		return fmt.Errorf("full-text search is not installed")
+	}
+
+	return nil
+}
+
+// serverRelease returns sql server major version, such as 2019, based on the
+// configured image version.
+func (p *P) serverRelease() string {
+	if len(p.Version) >= 4 {
+		if _, err := strconv.Atoi(p.Version[:4]); err == nil {
+			return p.Version[:4]
+		}
+	}
+
+	return defaultVersion[:4]
 }
 
 func (p *P) initf() gnomock.InitFunc {
@@ -106,7 +388,26 @@ func (p *P) initf() gnomock.InitFunc {
 
 		defer func() { _ = db.Close() }()
 
-		_, err = db.Exec("create database " + p.DB)
+		if p.FullText {
+			if err := fullTextCheck(db); err != nil {
+				return err
+			}
+		}
+
+		var dbID sql.NullInt64
+
+		err = db.QueryRow("select db_id(@p1)", p.DB).Scan(&dbID)
+		if err != nil {
+			return fmt.Errorf("can't check database '%s': %w", p.DB, err)
+		}
+
+		// the database exists when the container uses data from a previous
+		// run (see WithDataVolume); it is already set up in this case
+		if dbID.Valid {
+			return nil
+		}
+
+		_, err = db.Exec(p.createDatabaseQuery())
 		if err != nil {
 			return fmt.Errorf("can't create database '%s': %w", p.DB, err)
 		}
@@ -116,35 +417,201 @@ func (p *P) initf() gnomock.InitFunc {
 			return err
 		}
 
-		if len(p.QueriesFiles) > 0 {
-			for _, f := range p.QueriesFiles {
-				bs, err := os.ReadFile(f) // nolint:gosec
-				if err != nil {
-					return fmt.Errorf("can't read queries file '%s': %w", f, err)
-				}
+		setup := sqlsetup.Setup{
+			Queries:      p.Queries,
+			QueriesFiles: p.QueriesFiles,
+			Migrations:   p.Migrations,
+		}
 
-				p.Queries = append([]string{string(bs)}, p.Queries...)
-			}
+		if err := setup.Migrate(db, p.DB, migrationsDriver); err != nil {
+			return err
 		}
 
-		for _, q := range p.Queries {
-			_, err = db.Exec(q)
-			if err != nil {
-				return err
-			}
+		queries, err := setup.AllQueries()
+		if err != nil {
+			return err
 		}
 
+		for i, seed := range p.CSVSeeds {
+			queries = append(queries, fmt.Sprintf(
+				"bulk insert %s from '%s' with (format = 'CSV', firstrow = 2)",
+				seed.Table, csvFile(i),
+			))
+		}
+
+		return p.executeQueries(ctx, db, queries)
+	}
+}
+
+// createDatabaseQuery returns a query that creates the configured database,
+// either from scratch, or by attaching existing data files.
+func (p *P) createDatabaseQuery() string {
+	switch {
+	case p.AttachMDF != "" && p.AttachLDF != "":
+		return fmt.Sprintf(
+			"create database %s on (filename = '%s'), (filename = '%s') for attach",
+			p.DB, attachMDF, attachLDF,
+		)
+	case p.AttachMDF != "":
+		return fmt.Sprintf(
+			"create database %s on (filename = '%s') for attach_rebuild_log",
+			p.DB, attachMDF,
+		)
+	}
+
+	q := "create database " + p.DB
+	if p.Collation != "" {
+		q += " collate " + p.Collation
+	}
+
+	return q
+}
+
+// csvFile returns a path inside the container where i-th CSV seed file is
+// mounted.
+func csvFile(i int) string {
+	return fmt.Sprintf("%s/%d.csv", csvDir, i)
+}
+
+// executeQueries runs the queries one by one. With WithTransaction option,
+// they run in a single transaction, so that a failure in any of them leaves
+// the database empty instead of partially seeded.
+func (p *P) executeQueries(ctx context.Context, db *sql.DB, queries []string) error {
+	if len(queries) == 0 {
 		return nil
 	}
+
+	if !p.Transaction {
+		return sqlsetup.Execute(ctx, db, queries)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction: %w", err)
+	}
+
+	if err := sqlsetup.Execute(ctx, tx, queries); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("can't commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func migrationsDriver(db *sql.DB) (database.Driver, error) {
+	return sqlserver.WithInstance(db, &sqlserver.Config{})
+}
+
+// ConnString returns a connection string that can be used to connect to the
+// database created in the provided container. Use the same options that were
+// used to create the preset, so that the connection string includes the right
+// database name and administrator password. Default values are used for
+// options that are not provided.
+//
+// When WithTLS option is used, the connection string requires encryption.
+// Drivers verify server certificate using system roots, so either add the
+// certificate to them, or use Open or TLSConfig to trust it explicitly.
+func ConnString(c *gnomock.Container, opts ...Option) string {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.setDefaults()
+
+	return p.connString(c.DefaultAddress(), p.DB)
+}
+
+// Open returns a connection to the database created in the provided
+// container. See ConnString for information on the provided options.
+//
+// When WithTLS option is used, the connection is encrypted, and the server
+// certificate is verified using TLSConfig.
+func Open(c *gnomock.Container, opts ...Option) (*sql.DB, error) {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.setDefaults()
+
+	return p.connect(c.DefaultAddress(), p.DB)
+}
+
+// TLSConfig returns TLS configuration that trusts the certificate provided
+// using WithTLS option, and can be used to verify the server in the provided
+// container. Use the same options that were used to create the preset. The
+// certificate must be valid for the host that runs the container, for example
+// for "127.0.0.1" or "localhost".
+func TLSConfig(c *gnomock.Container, opts ...Option) (*tls.Config, error) {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p.tlsConfig(c.Host)
+}
+
+func (p *P) tlsConfig(host string) (*tls.Config, error) {
+	cfg, err := tlsutil.Config(p.TLSCert, host)
+	if err != nil {
+		return nil, err
+	}
+
+	// sql server expects one tcp segment per encrypted tds package, see
+	// https://github.com/denisenkom/go-mssqldb/issues/166
+	cfg.DynamicRecordSizingDisabled = true
+
+	return cfg, nil
 }
 
 func (p *P) connect(addr, db string) (*sql.DB, error) {
-	connStr := fmt.Sprintf(
-		"sqlserver://sa:%s@%s?database=%s",
-		p.Password, addr, db,
-	)
+	if p.TLSCert == "" {
+		return sql.Open("sqlserver", p.connString(addr, db))
+	}
+
+	cfg, _, err := msdsn.Parse(p.connString(addr, db))
+	if err != nil {
+		return nil, fmt.Errorf("can't parse connection string: %w", err)
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address '%s': %w", addr, err)
+	}
+
+	cfg.TLSConfig, err = p.tlsConfig(host)
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.OpenDB(mssql.NewConnectorConfig(cfg)), nil
+}
+
+func (p *P) connString(addr, db string) string {
+	u := url.URL{
+		Scheme: "sqlserver",
+		User:   url.UserPassword("sa", p.Password),
+		Host:   addr,
+		Path:   p.Instance,
+	}
+
+	query := url.Values{"database": {db}}
+	if p.TLSCert != "" {
+		query.Set("encrypt", "true")
+	}
+
+	u.RawQuery = query.Encode()
 
-	return sql.Open("sqlserver", connStr)
+	return u.String()
 }
 
 func (p *P) setDefaults() {
@@ -156,7 +623,15 @@ func (p *P) setDefaults() {
 		p.Password = defaultPassword
 	}
 
+	if p.Port == 0 {
+		p.Port = defaultPort
+	}
+
 	if p.Version == "" {
 		p.Version = defaultVersion
+
+		if p.AzureSQLEdge {
+			p.Version = defaultAzureSQLEdgeVersion
+		}
 	}
 }
@@ -1,12 +1,18 @@
 package mssql_test
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"io"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/docker/docker/api/types"
+	dockerclient "github.com/docker/docker/client"
 	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/tlsutil"
 	"github.com/orlangure/gnomock/preset/mssql"
 	"github.com/stretchr/testify/require"
 )
@@ -61,6 +67,38 @@ func testPreset(version string) func(t *testing.T) {
 	}
 }
 
+func TestPreset_azureSQLEdge(t *testing.T) {
+	t.Parallel()
+
+	p := mssql.Preset(
+		mssql.WithLicense(true),
+		mssql.WithAzureSQLEdge(),
+		mssql.WithAdminPassword("Passw0rd-"),
+		mssql.WithDatabase("foobar"),
+		mssql.WithQueriesFile("./testdata/queries.sql"),
+		mssql.WithQueries("insert into t (a) values (1)"),
+	)
+
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+	require.Equal(t, "mcr.microsoft.com/azure-sql-edge:1.0.7", p.Image())
+
+	addr := container.DefaultAddress()
+	connStr := fmt.Sprintf("sqlserver://sa:Passw0rd-@%s?database=foobar", addr)
+
+	db, err := sql.Open("sqlserver", connStr)
+	require.NoError(t, err)
+
+	var count int
+
+	require.NoError(t, db.QueryRow("select count(a) from t").Scan(&count))
+	require.Equal(t, 1, count)
+	require.NoError(t, db.Close())
+}
+
 func TestPreset_withDefaults(t *testing.T) {
 	t.Parallel()
 
@@ -79,6 +117,408 @@ func TestPreset_withDefaults(t *testing.T) {
 	require.NoError(t, db.Close())
 }
 
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	opts := []mssql.Option{
+		mssql.WithLicense(true),
+		mssql.WithAdminPassword("Passw0rd-"),
+		mssql.WithDatabase("foobar"),
+	}
+
+	container, err := gnomock.Start(mssql.Preset(opts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	connStr := fmt.Sprintf("sqlserver://sa:Passw0rd-@%s?database=foobar", container.DefaultAddress())
+	require.Equal(t, connStr, mssql.ConnString(container, opts...))
+
+	db, err := mssql.Open(container, opts...)
+	require.NoError(t, err)
+
+	var name string
+
+	require.NoError(t, db.QueryRow("select db_name()").Scan(&name))
+	require.Equal(t, "foobar", name)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withCollation(t *testing.T) {
+	t.Parallel()
+
+	p := mssql.Preset(
+		mssql.WithLicense(true),
+		mssql.WithCollation("Latin1_General_CS_AS"),
+	)
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	addr := container.DefaultAddress()
+	connStr := fmt.Sprintf("sqlserver://sa:Gn0m!ck~@%s?database=mydb", addr)
+
+	db, err := sql.Open("sqlserver", connStr)
+	require.NoError(t, err)
+
+	var collation string
+
+	row := db.QueryRow("select collation_name from sys.databases where name = 'mydb'")
+	require.NoError(t, row.Scan(&collation))
+	require.Equal(t, "Latin1_General_CS_AS", collation)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withAgent(t *testing.T) {
+	t.Parallel()
+
+	p := mssql.Preset(
+		mssql.WithLicense(true),
+		mssql.WithAgent(),
+	)
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	addr := container.DefaultAddress()
+	connStr := fmt.Sprintf("sqlserver://sa:Gn0m!ck~@%s?database=msdb", addr)
+
+	db, err := sql.Open("sqlserver", connStr)
+	require.NoError(t, err)
+
+	_, err = db.Exec("exec dbo.sp_add_job @job_name = N'gnomock'")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withFullTextSearch(t *testing.T) {
+	t.Parallel()
+
+	opts := []mssql.Option{
+		mssql.WithLicense(true),
+		mssql.WithFullTextSearch(),
+		mssql.WithQueries(
+			"create table docs (id int not null constraint pk_docs primary key, body nvarchar(max))",
+			"insert into docs (id, body) values (1, 'the quick brown fox')",
+		),
+	}
+	container, err := gnomock.Start(mssql.Preset(opts...), gnomock.WithTimeout(time.Minute*10))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err := mssql.Open(container, opts...)
+	require.NoError(t, err)
+
+	_, err = db.Exec("create fulltext catalog docs_catalog as default")
+	require.NoError(t, err)
+
+	_, err = db.Exec("create fulltext index on docs (body) key index pk_docs with change_tracking auto")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withEngineConfig(t *testing.T) {
+	t.Parallel()
+
+	p := mssql.Preset(
+		mssql.WithLicense(true),
+		mssql.WithProductID("Express"),
+		mssql.WithLCID(1036),
+		mssql.WithMemoryLimit(2048),
+		mssql.WithTraceFlags(1222),
+	)
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err := mssql.Open(container)
+	require.NoError(t, err)
+
+	var edition string
+
+	require.NoError(t, db.QueryRow("select serverproperty('Edition')").Scan(&edition))
+	require.Contains(t, edition, "Express")
+
+	var flag, status, global, session int
+
+	require.NoError(t, db.QueryRow("dbcc tracestatus(1222)").Scan(&flag, &status, &global, &session))
+	require.Equal(t, 1222, flag)
+	require.Equal(t, 1, global)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withCSVSeed(t *testing.T) {
+	t.Parallel()
+
+	p := mssql.Preset(
+		mssql.WithLicense(true),
+		mssql.WithQueries("create table people (id int, name nvarchar(64))"),
+		mssql.WithCSVSeed("people", "./testdata/people.csv"),
+	)
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err := mssql.Open(container)
+	require.NoError(t, err)
+
+	var name string
+
+	require.NoError(t, db.QueryRow("select name from people where id = 3").Scan(&name))
+	require.Equal(t, "baz, qux", name)
+
+	var count int
+
+	require.NoError(t, db.QueryRow("select count(*) from people").Scan(&count))
+	require.Equal(t, 3, count)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withPort(t *testing.T) {
+	t.Parallel()
+
+	opts := []mssql.Option{
+		mssql.WithLicense(true),
+		mssql.WithPort(11433),
+		mssql.WithInstance("gnomock"),
+	}
+	container, err := gnomock.Start(mssql.Preset(opts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+	require.Contains(t, mssql.ConnString(container, opts...), "/gnomock?")
+
+	db, err := mssql.Open(container, opts...)
+	require.NoError(t, err)
+
+	var port int
+
+	require.NoError(t, db.QueryRow("select local_tcp_port from sys.dm_exec_connections where session_id = @@spid").Scan(&port))
+	require.Equal(t, 11433, port)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withTLS(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM, err := tlsutil.SelfSignedCert()
+	require.NoError(t, err)
+	opts := []mssql.Option{
+		mssql.WithLicense(true),
+		mssql.WithTLS(certPEM, keyPEM),
+	}
+	container, err := gnomock.Start(mssql.Preset(opts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+	require.Contains(t, mssql.ConnString(container, opts...), "encrypt=true")
+
+	db, err := mssql.Open(container, opts...)
+	require.NoError(t, err)
+
+	var encrypted string
+
+	require.NoError(t, db.QueryRow("select encrypt_option from sys.dm_exec_connections where session_id = @@spid").Scan(&encrypted))
+	require.Equal(t, "TRUE", encrypted)
+	require.NoError(t, db.Close())
+
+	// unencrypted connections are rejected
+	db, err = sql.Open("sqlserver", fmt.Sprintf(
+		"sqlserver://sa:Gn0m!ck~@%s?database=mydb&encrypt=disable", container.DefaultAddress(),
+	))
+	require.NoError(t, err)
+	require.Error(t, db.Ping())
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withAttachedDatabase(t *testing.T) {
+	t.Parallel()
+
+	// 2017 images run as root, so they can read and write the files in a
+	// temporary directory regardless of its permissions
+	dir := t.TempDir()
+	p := mssql.Preset(
+		mssql.WithLicense(true),
+		mssql.WithVersion("2017-latest"),
+		mssql.WithDataVolume(dir),
+		mssql.WithQueries("create table t (a int)", "insert into t (a) values (42)"),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	db, err := mssql.Open(container, mssql.WithDatabase("master"))
+	require.NoError(t, err)
+
+	_, err = db.Exec("exec sp_detach_db 'mydb'")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// files created by root must be removable by the user running the tests
+	// when the temporary directory is cleaned up
+	execInContainer(t, container, "chmod", "-R", "a+rwX", "/var/opt/mssql")
+	require.NoError(t, gnomock.Stop(container))
+
+	p = mssql.Preset(
+		mssql.WithLicense(true),
+		mssql.WithVersion("2017-latest"),
+		mssql.WithDatabase("attached"),
+		mssql.WithAttachedDatabase(dir+"/data/mydb.mdf", dir+"/data/mydb_log.ldf"),
+	)
+	container, err = gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err = mssql.Open(container, mssql.WithDatabase("attached"))
+	require.NoError(t, err)
+
+	var a int
+
+	require.NoError(t, db.QueryRow("select a from t").Scan(&a))
+	require.Equal(t, 42, a)
+	require.NoError(t, db.Close())
+}
+
+func execInContainer(t *testing.T, c *gnomock.Container, cmd ...string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	cli, err := dockerclient.NewClientWithOpts(dockerclient.FromEnv, dockerclient.WithAPIVersionNegotiation())
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, cli.Close()) }()
+
+	created, err := cli.ContainerExecCreate(ctx, c.DockerID(), types.ExecConfig{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	require.NoError(t, err)
+
+	attached, err := cli.ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{})
+	require.NoError(t, err)
+
+	defer attached.Close()
+
+	out, err := io.ReadAll(attached.Reader)
+	require.NoError(t, err)
+
+	inspect, err := cli.ContainerExecInspect(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equalf(t, 0, inspect.ExitCode, string(out))
+}
+
+func TestPreset_withMigrations(t *testing.T) {
+	t.Parallel()
+
+	opts := []mssql.Option{
+		mssql.WithLicense(true),
+		mssql.WithMigrations("file://./testdata/migrations"),
+		mssql.WithQueries("insert into accounts (id, name, email) values (1, 'foo', 'foo@example.com')"),
+	}
+	container, err := gnomock.Start(mssql.Preset(opts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err := mssql.Open(container, opts...)
+	require.NoError(t, err)
+
+	var version int
+
+	require.NoError(t, db.QueryRow("select version from schema_migrations").Scan(&version))
+	require.Equal(t, 2, version)
+
+	var email string
+
+	require.NoError(t, db.QueryRow("select email from accounts where id = 1").Scan(&email))
+	require.Equal(t, "foo@example.com", email)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withDataVolume(t *testing.T) {
+	t.Parallel()
+
+	volume := fmt.Sprintf("gnomock-mssql-%d", time.Now().UnixNano())
+
+	t.Cleanup(func() {
+		cli, err := dockerclient.NewClientWithOpts(dockerclient.FromEnv, dockerclient.WithAPIVersionNegotiation())
+		require.NoError(t, err)
+		require.NoError(t, cli.VolumeRemove(context.Background(), volume, true))
+		require.NoError(t, cli.Close())
+	})
+
+	opts := []mssql.Option{
+		mssql.WithLicense(true),
+		mssql.WithDataVolume(volume),
+		mssql.WithQueries("create table t(a int)", "insert into t (a) values (1)"),
+	}
+
+	for i := 0; i < 2; i++ {
+		container, err := gnomock.Start(mssql.Preset(opts...))
+		require.NoError(t, err)
+
+		db, err := mssql.Open(container, opts...)
+		require.NoError(t, err)
+
+		var count int
+
+		// queries only run once, when the database is created for the
+		// first time
+		require.NoError(t, db.QueryRow("select count(a) from t").Scan(&count))
+		require.Equal(t, 1, count)
+		require.NoError(t, db.Close())
+		require.NoError(t, gnomock.Stop(container))
+	}
+}
+
+func TestPreset_wrongQuery(t *testing.T) {
+	t.Parallel()
+
+	p := mssql.Preset(
+		mssql.WithLicense(true),
+		mssql.WithTransaction(),
+		mssql.WithQueries(
+			"create table t(a int)",
+			"insert into t (a) values (1)",
+			"insert into missing (a) values (2)",
+		),
+	)
+	c, err := gnomock.Start(p, gnomock.WithDebugMode())
+
+	defer func() { require.NoError(t, gnomock.Stop(c)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "query 3 of 3 failed (insert into missing (a) values (2))")
+
+	db, err := mssql.Open(c)
+	require.NoError(t, err)
+
+	var count int
+
+	// the whole transaction is rolled back, including the table creation
+	row := db.QueryRow("select count(*) from sys.tables where name = 't'")
+	require.NoError(t, row.Scan(&count))
+	require.Zero(t, count)
+	require.NoError(t, db.Close())
+}
+
 func TestPreset_wrongQueriesFile(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1 @@
+alter table accounts add email nvarchar(128);
@@ -38,6 +38,69 @@ func WithQueriesFile(file string) Option {
 	}
 }
 
+// WithMigrations applies golang-migrate migrations from the provided source
+// URL, for example "file://migrations", to the database during initial setup.
+// Migrations run after dump files are loaded, and before any queries are
+// executed, so they can be used to create the schema.
+func WithMigrations(sourceURL string) Option {
+	return func(p *P) {
+		p.Migrations = sourceURL
+	}
+}
+
+// WithDumpFile loads the provided mysqldump output into the database created
+// with WithDatabase, or into default "mydb" database. Gzip compressed files
+// with ".gz" extension are supported too. This option can be used multiple
+// times, and the files are loaded in the same order.
+//
+// The files are mounted into the container, and executed by the mysql client
+// when the database is initialized, so they can be of any size, and can use
+// any client features, such as DELIMITER. The files must be readable by any
+// user. Dumps are loaded before any queries are executed. If a dump can't be
+// loaded, the container fails to start; use gnomock.WithDebugMode to see the
+// error.
+func WithDumpFile(path string) Option {
+	return func(p *P) {
+		p.DumpFiles = append(p.DumpFiles, path)
+	}
+}
+
+// WithServerVariables sets MySQL server system variables, such as "sql_mode",
+// "lower_case_table_names" or "max_allowed_packet". The variables are passed
+// to the server as command line options, so they are applied when the data
+// directory is initialized as well. This option can be used multiple times.
+func WithServerVariables(vars map[string]string) Option {
+	return func(p *P) {
+		if p.ServerVariables == nil {
+			p.ServerVariables = make(map[string]string, len(vars))
+		}
+
+		for k, v := range vars {
+			p.ServerVariables[k] = v
+		}
+	}
+}
+
+// WithImageVariant uses an image of MySQL compatible server distribution,
+// such as MariaDB or Percona Server, instead of the official MySQL image. If
+// version is empty, the default version of the variant is used, unless it is
+// set using WithVersion.
+//
+// Unlike mariadb preset, which only supports initial queries, MariaDB variant
+// of this preset can be used with every option of this package, such as
+// WithDumpFile, WithServerVariables or WithMigrations, so that the same test
+// setup can run against any of the variants. StartReplicated only supports
+// MySQL and Percona variants.
+func WithImageVariant(variant ImageVariant, version string) Option {
+	return func(p *P) {
+		p.Variant = variant
+
+		if version != "" {
+			p.Version = version
+		}
+	}
+}
+
 // WithVersion sets image version.
 func WithVersion(version string) Option {
 	return func(o *P) {
@@ -14,12 +14,17 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"os"
+	"sort"
+	"strings"
 	"sync"
 
 	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/golang-migrate/migrate/v4/database"
+	migratemysql "github.com/golang-migrate/migrate/v4/database/mysql"
 	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/hostpath"
 	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
 )
 
 const (
@@ -28,6 +33,21 @@ const (
 	defaultDatabase = "mydb"
 	defaultPort     = 3306
 	defaultVersion  = "8.0.22"
+
+	initDir = "/docker-entrypoint-initdb.d"
+
+	defaultMariaDBVersion = "10.5.8"
+	defaultPerconaVersion = "8.0.22-13"
+)
+
+// ImageVariant is a MySQL compatible server distribution.
+type ImageVariant string
+
+// Supported image variants. MySQL is used by default.
+const (
+	MySQL   ImageVariant = "mysql"
+	MariaDB ImageVariant = "mariadb"
+	Percona ImageVariant = "percona"
 )
 
 var setLoggerOnce sync.Once
@@ -54,12 +74,21 @@ func Preset(opts ...Option) gnomock.Preset {
 
 // P is a Gnomock Preset implementation of MySQL database.
 type P struct {
-	DB           string   `json:"db"`
-	User         string   `json:"user"`
-	Password     string   `json:"password"`
-	Queries      []string `json:"queries"`
-	QueriesFiles []string `json:"queries_files"`
-	Version      string   `json:"version"`
+	DB           string       `json:"db"`
+	User         string       `json:"user"`
+	Password     string       `json:"password"`
+	Queries      []string     `json:"queries"`
+	QueriesFiles []string     `json:"queries_files"`
+	Version      string       `json:"version"`
+	DumpFiles    []string     `json:"dump_files"`
+	Variant      ImageVariant `json:"variant"`
+
+	ServerVariables map[string]string `json:"server_variables"`
+	Migrations      string            `json:"migrations"`
+
+	// rootPassword is only set for containers that require administrative
+	// access, such as replicated topologies; otherwise it is random
+	rootPassword string
 }
 
 // Ports returns ports that should be used to access this container.
@@ -76,18 +105,72 @@ func (p *P) Options() []gnomock.Option {
 
 	p.setDefaults()
 
+	// newer mariadb images also accept MARIADB_ prefixed variables, but older
+	// ones, including the default version, only support MYSQL_ prefix, which
+	// works with every version of all the variants
 	opts := []gnomock.Option{
 		gnomock.WithHealthCheck(p.healthcheck),
 		gnomock.WithEnv("MYSQL_USER=" + p.User),
 		gnomock.WithEnv("MYSQL_PASSWORD=" + p.Password),
 		gnomock.WithEnv("MYSQL_DATABASE=" + p.DB),
-		gnomock.WithEnv("MYSQL_RANDOM_ROOT_PASSWORD=yes"),
 		gnomock.WithInit(p.initf()),
 	}
 
+	if p.rootPassword != "" {
+		opts = append(opts, gnomock.WithEnv("MYSQL_ROOT_PASSWORD="+p.rootPassword))
+	} else {
+		opts = append(opts, gnomock.WithEnv("MYSQL_RANDOM_ROOT_PASSWORD=yes"))
+	}
+
+	if len(p.ServerVariables) > 0 {
+		opts = append(opts, gnomock.WithCommand("mysqld", p.serverArgs()...))
+	}
+
+	for i, f := range p.DumpFiles {
+		opts = append(opts, gnomock.WithHostMounts(hostpath.Abs(f), dumpFile(i, f)))
+	}
+
 	return opts
 }
 
+// variantImage returns an image of the configured variant, or an empty string
+// if the default MySQL image should be used.
+func (p *P) variantImage() string {
+	switch p.Variant {
+	case MariaDB:
+		return fmt.Sprintf("docker.io/library/mariadb:%s", p.Version)
+	case Percona:
+		return fmt.Sprintf("docker.io/percona/percona-server:%s", p.Version)
+	default:
+		return ""
+	}
+}
+
+// serverArgs returns server command line arguments that set server variables,
+// sorted by variable name.
+func (p *P) serverArgs() []string {
+	args := make([]string, 0, len(p.ServerVariables))
+	for k, v := range p.ServerVariables {
+		args = append(args, fmt.Sprintf("--%s=%s", k, v))
+	}
+
+	sort.Strings(args)
+
+	return args
+}
+
+// dumpFile returns a path inside the container where i-th dump file is
+// mounted. The image executes these files in alphabetical order when the
+// database is initialized.
+func dumpFile(i int, f string) string {
+	ext := ".sql"
+	if strings.HasSuffix(f, ".gz") {
+		ext = ".sql.gz"
+	}
+
+	return fmt.Sprintf("%s/gnomock-%03d%s", initDir, i, ext)
+}
+
 func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
 	addr := c.Address(gnomock.DefaultPort)
 
@@ -116,35 +199,46 @@ func (p *P) initf() gnomock.InitFunc {
 
 		defer func() { _ = db.Close() }()
 
-		if len(p.QueriesFiles) > 0 {
-			for _, f := range p.QueriesFiles {
-				bs, err := os.ReadFile(f) // nolint:gosec
-				if err != nil {
-					return fmt.Errorf("can't read queries file '%s': %w", f, err)
-				}
-
-				p.Queries = append([]string{string(bs)}, p.Queries...)
-			}
+		setup := sqlsetup.Setup{
+			Queries:      p.Queries,
+			QueriesFiles: p.QueriesFiles,
+			Migrations:   p.Migrations,
 		}
 
-		for _, q := range p.Queries {
-			_, err = db.Exec(q)
-			if err != nil {
-				return err
-			}
-		}
+		return setup.Apply(ctx, db, p.DB, migrationsDriver)
+	}
+}
 
-		return nil
+func migrationsDriver(db *sql.DB) (database.Driver, error) {
+	return migratemysql.WithInstance(db, &migratemysql.Config{})
+}
+
+// ConnString returns a connection string (DSN) that can be used to connect to
+// the database created in the provided container. Use the same options that
+// were used to create the preset, so that the connection string includes the
+// right database name and credentials. Default values are used for options
+// that are not provided.
+func ConnString(c *gnomock.Container, opts ...Option) string {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
 	}
+
+	p.setDefaults()
+
+	return p.connString(c.Address(gnomock.DefaultPort))
 }
 
-func (p *P) connect(addr string) (*sql.DB, error) {
-	connStr := fmt.Sprintf(
+func (p *P) connString(addr string) string {
+	return fmt.Sprintf(
 		"%s:%s@tcp(%s)/%s?multiStatements=true",
 		p.User, p.Password, addr, p.DB,
 	)
+}
 
-	db, err := sql.Open("mysql", connStr)
+func (p *P) connect(addr string) (*sql.DB, error) {
+	db, err := sql.Open("mysql", p.connString(addr))
 	if err != nil {
 		return nil, err
 	}
@@ -165,7 +259,18 @@ func (p *P) setDefaults() {
 		p.Password = defaultPassword
 	}
 
+	if p.Variant == "" {
+		p.Variant = MySQL
+	}
+
 	if p.Version == "" {
-		p.Version = defaultVersion
+		switch p.Variant {
+		case MariaDB:
+			p.Version = defaultMariaDBVersion
+		case Percona:
+			p.Version = defaultPerconaVersion
+		default:
+			p.Version = defaultVersion
+		}
 	}
 }
@@ -6,5 +6,9 @@ import (
 
 // Image returns an image that should be pulled to create this container.
 func (p *P) Image() string {
+	if image := p.variantImage(); image != "" {
+		return image
+	}
+
 	return fmt.Sprintf("docker.io/mysql/mysql-server:%s", p.Version)
 }
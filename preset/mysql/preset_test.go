@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/orlangure/gnomock"
 	"github.com/orlangure/gnomock/preset/mysql"
@@ -62,6 +63,124 @@ func testPreset(version string) func(t *testing.T) {
 	}
 }
 
+func TestPreset_withDumpFile(t *testing.T) {
+	t.Parallel()
+
+	p := mysql.Preset(
+		mysql.WithDumpFile("./testdata/dump.sql"),
+		mysql.WithQueries("insert into accounts (id, name) values (3, 'qux')"),
+	)
+
+	container, err := gnomock.Start(p)
+
+	defer func() { _ = gnomock.Stop(container) }()
+
+	require.NoError(t, err)
+
+	addr := container.DefaultAddress()
+	connStr := fmt.Sprintf(
+		"%s:%s@tcp(%s)/%s",
+		"gnomock", "gnomick", addr, "mydb",
+	)
+
+	db, err := sql.Open("mysql", connStr)
+	require.NoError(t, err)
+
+	// session variables are only visible to the same connection
+	db.SetMaxOpenConns(1)
+
+	var name string
+
+	require.NoError(t, db.QueryRow("select name from accounts where id = 2").Scan(&name))
+	require.Equal(t, "bar; baz", name)
+
+	var total int
+
+	_, err = db.Exec("call count_accounts(@total)")
+	require.NoError(t, err)
+	require.NoError(t, db.QueryRow("select @total").Scan(&total))
+	require.Equal(t, 3, total)
+}
+
+func TestPreset_withServerVariables(t *testing.T) {
+	t.Parallel()
+
+	p := mysql.Preset(
+		mysql.WithServerVariables(map[string]string{
+			"sql_mode":               "ANSI_QUOTES",
+			"lower_case_table_names": "1",
+			"max_allowed_packet":     "33554432",
+		}),
+	)
+
+	container, err := gnomock.Start(p)
+
+	defer func() { _ = gnomock.Stop(container) }()
+
+	require.NoError(t, err)
+
+	addr := container.DefaultAddress()
+	connStr := fmt.Sprintf(
+		"%s:%s@tcp(%s)/%s",
+		"gnomock", "gnomick", addr, "mydb",
+	)
+
+	db, err := sql.Open("mysql", connStr)
+	require.NoError(t, err)
+
+	var sqlMode string
+
+	var lowerCaseTableNames, maxAllowedPacket int
+
+	row := db.QueryRow("select @@global.sql_mode, @@lower_case_table_names, @@max_allowed_packet")
+	require.NoError(t, row.Scan(&sqlMode, &lowerCaseTableNames, &maxAllowedPacket))
+	require.Equal(t, "ANSI_QUOTES", sqlMode)
+	require.Equal(t, 1, lowerCaseTableNames)
+	require.Equal(t, 33554432, maxAllowedPacket)
+}
+
+func TestPreset_withImageVariant(t *testing.T) {
+	t.Parallel()
+
+	variants := map[mysql.ImageVariant]string{
+		mysql.MariaDB: "mariadb",
+		mysql.Percona: "percona",
+	}
+
+	for variant, expected := range variants {
+		variant, expected := variant, expected
+
+		t.Run(string(variant), func(t *testing.T) {
+			t.Parallel()
+
+			p := mysql.Preset(
+				mysql.WithImageVariant(variant, ""),
+				mysql.WithQueries("create table t (a int)"),
+			)
+
+			container, err := gnomock.Start(p)
+
+			defer func() { _ = gnomock.Stop(container) }()
+
+			require.NoError(t, err)
+
+			addr := container.DefaultAddress()
+			connStr := fmt.Sprintf(
+				"%s:%s@tcp(%s)/%s",
+				"gnomock", "gnomick", addr, "mydb",
+			)
+
+			db, err := sql.Open("mysql", connStr)
+			require.NoError(t, err)
+
+			var version string
+
+			require.NoError(t, db.QueryRow("select lower(concat(@@version, @@version_comment))").Scan(&version))
+			require.Contains(t, version, expected)
+		})
+	}
+}
+
 func TestPreset_withDefaults(t *testing.T) {
 	t.Parallel()
 
@@ -92,3 +211,79 @@ func TestPreset_wrongQueriesFile(t *testing.T) {
 	require.Contains(t, err.Error(), "can't read queries file")
 	require.NoError(t, gnomock.Stop(c))
 }
+
+func TestStartReplicated(t *testing.T) {
+	t.Parallel()
+
+	opts := []mysql.Option{
+		mysql.WithQueries("create table t (a int primary key)", "insert into t (a) values (1)"),
+	}
+
+	topology, err := mysql.StartReplicated(2, opts)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, topology.Stop()) }()
+
+	require.Len(t, topology.Replicas, 2)
+	require.Len(t, topology.Containers(), 3)
+
+	connect := func(c *gnomock.Container) *sql.DB {
+		connStr := fmt.Sprintf("gnomock:gnomick@tcp(%s)/mydb", c.DefaultAddress())
+
+		db, err := sql.Open("mysql", connStr)
+		require.NoError(t, err)
+
+		return db
+	}
+
+	primary := connect(topology.Primary)
+	defer func() { require.NoError(t, primary.Close()) }()
+
+	_, err = primary.Exec("insert into t (a) values (2)")
+	require.NoError(t, err)
+
+	for _, c := range topology.Replicas {
+		replica := connect(c)
+
+		require.Eventually(t, func() bool {
+			var count int
+
+			err := replica.QueryRow("select count(*) from t").Scan(&count)
+
+			return err == nil && count == 2
+		}, time.Second*10, time.Millisecond*100)
+
+		_, err = replica.Exec("insert into t (a) values (3)")
+		require.Error(t, err)
+		require.NoError(t, replica.Close())
+	}
+}
+
+func TestPreset_withMigrations(t *testing.T) {
+	t.Parallel()
+
+	opts := []mysql.Option{
+		mysql.WithDatabase("accounts_db"),
+		mysql.WithMigrations("file://./testdata/migrations"),
+		mysql.WithQueries("insert into accounts (id, name, email) values (1, 'foo', 'foo@example.com')"),
+	}
+	container, err := gnomock.Start(mysql.Preset(opts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err := sql.Open("mysql", mysql.ConnString(container, opts...))
+	require.NoError(t, err)
+
+	var version int
+
+	require.NoError(t, db.QueryRow("select version from schema_migrations").Scan(&version))
+	require.Equal(t, 2, version)
+
+	var email string
+
+	require.NoError(t, db.QueryRow("select email from accounts where id = 1").Scan(&email))
+	require.Equal(t, "foo@example.com", email)
+	require.NoError(t, db.Close())
+}
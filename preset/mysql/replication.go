@@ -0,0 +1,241 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/orlangure/gnomock"
+)
+
+const (
+	replicationUser     = "replicator"
+	replicationPassword = "replicator"
+	replicationTimeout  = time.Second * 30
+
+	// primaryHost is the address replicas use to reach the primary server
+	// using its port published on the docker host.
+	primaryHost = "host.docker.internal"
+)
+
+// Topology is a group of MySQL containers with GTID based replication set up
+// between them. All write queries should be sent to Primary, while any of the
+// Replicas can be used for reads.
+type Topology struct {
+	Primary  *gnomock.Container
+	Replicas []*gnomock.Container
+}
+
+// Containers returns all the containers of this topology, starting with the
+// primary.
+func (t *Topology) Containers() []*gnomock.Container {
+	return append([]*gnomock.Container{t.Primary}, t.Replicas...)
+}
+
+// StartReplicated starts a primary MySQL container and the requested number
+// of read-only replicas. The provided preset options are applied to every
+// container, so that all of them are initialized with the same state: the
+// same database, user and data. Once initialized, every replica is set up to
+// replicate all further changes from the primary. Gnomock options, if
+// provided, are applied to every container as well.
+//
+// Replicas connect to the primary using the port published on the docker
+// host, which is reachable from the containers using "host.docker.internal"
+// name. For this reason, gnomock.WithExtraHosts option is not supported.
+//
+// Use Stop to stop all the containers. If any of the containers fails to
+// start, the containers that are already running are stopped.
+func StartReplicated(replicas int, opts []Option, gnomockOpts ...gnomock.Option) (*Topology, error) {
+	t := &Topology{}
+
+	primary, err := startNode(1, opts, gnomockOpts)
+	if err != nil {
+		return nil, fmt.Errorf("can't start primary: %w", err)
+	}
+
+	t.Primary = primary.container
+
+	if err := primary.createReplicationUser(); err != nil {
+		return nil, t.stopWithError(err)
+	}
+
+	for i := 0; i < replicas; i++ {
+		replica, err := startNode(i+2, opts, gnomockOpts)
+		if err != nil {
+			return nil, t.stopWithError(fmt.Errorf("can't start replica %d: %w", i+1, err))
+		}
+
+		t.Replicas = append(t.Replicas, replica.container)
+
+		if err := replica.replicate(primary); err != nil {
+			return nil, t.stopWithError(fmt.Errorf("can't set up replica %d: %w", i+1, err))
+		}
+	}
+
+	return t, nil
+}
+
+// Stop stops all the containers of this topology.
+func (t *Topology) Stop() error {
+	return gnomock.Stop(t.Containers()...)
+}
+
+func (t *Topology) stopWithError(err error) error {
+	if stopErr := t.Stop(); stopErr != nil {
+		return fmt.Errorf("%w; can't stop containers: %s", err, stopErr.Error())
+	}
+
+	return err
+}
+
+// node is a single server of a replicated topology.
+type node struct {
+	p         *P
+	container *gnomock.Container
+}
+
+func startNode(serverID int, opts []Option, gnomockOpts []gnomock.Option) (*node, error) {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.rootPassword = p.Password
+	if p.rootPassword == "" {
+		p.rootPassword = defaultPassword
+	}
+
+	WithServerVariables(map[string]string{
+		"server_id":                strconv.Itoa(serverID),
+		"log_bin":                  "mysql-bin",
+		"gtid_mode":                "ON",
+		"enforce_gtid_consistency": "ON",
+	})(p)
+
+	gnomockOpts = append(
+		gnomockOpts,
+		gnomock.WithExtraHosts([]string{primaryHost + ":host-gateway"}),
+	)
+
+	c, err := gnomock.Start(p, gnomockOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &node{p: p, container: c}, nil
+}
+
+func (n *node) connectRoot() (*sql.DB, error) {
+	connStr := fmt.Sprintf("root:%s@tcp(%s)/", n.p.rootPassword, n.container.DefaultAddress())
+
+	db, err := sql.Open("mysql", connStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// session settings must apply to all the queries
+	db.SetMaxOpenConns(1)
+
+	return db, db.Ping()
+}
+
+func (n *node) createReplicationUser() error {
+	db, err := n.connectRoot()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	queries := []string{
+		"set sql_log_bin = 0",
+		fmt.Sprintf(
+			"create user '%s'@'%%' identified with mysql_native_password by '%s'",
+			replicationUser, replicationPassword,
+		),
+		fmt.Sprintf("grant replication slave on *.* to '%s'@'%%'", replicationUser),
+		"set sql_log_bin = 1",
+	}
+
+	for _, q := range queries {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("can't create replication user: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// replicate makes this node a read-only replica of the provided primary.
+// Since both nodes are initialized with the same state, the replica only
+// needs to receive the transactions executed on the primary after it was
+// initialized.
+func (n *node) replicate(primary *node) error {
+	primaryDB, err := primary.connectRoot()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = primaryDB.Close() }()
+
+	var executed string
+
+	if err := primaryDB.QueryRow("select @@global.gtid_executed").Scan(&executed); err != nil {
+		return fmt.Errorf("can't get primary position: %w", err)
+	}
+
+	db, err := n.connectRoot()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	queries := []string{
+		"reset master",
+		fmt.Sprintf("set global gtid_purged = '%s'", executed),
+		fmt.Sprintf(
+			`change master to master_host = '%s', master_port = %d,
+			master_user = '%s', master_password = '%s', master_auto_position = 1`,
+			primaryHost, primary.container.DefaultPort(),
+			replicationUser, replicationPassword,
+		),
+		"start slave",
+		"set global read_only = 1",
+	}
+
+	for _, q := range queries {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("can't set up replication: %w", err)
+		}
+	}
+
+	return waitForReplication(db)
+}
+
+// waitForReplication returns an error if the replica doesn't connect to the
+// primary in time.
+func waitForReplication(db *sql.DB) error {
+	ctx, cancel := context.WithTimeout(context.Background(), replicationTimeout)
+	defer cancel()
+
+	var state string
+
+	for {
+		err := db.QueryRowContext(ctx, `
+			select service_state from performance_schema.replication_connection_status
+		`).Scan(&state)
+		if err == nil && state == "ON" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("replica is not connected (state '%s'): %w", state, ctx.Err())
+		case <-time.After(time.Millisecond * 250):
+		}
+	}
+}
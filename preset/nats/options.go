@@ -0,0 +1,53 @@
+package nats
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithJetStream enables JetStream persistence layer. It is enabled
+// automatically when streams or consumers are configured.
+func WithJetStream() Option {
+	return func(o *P) {
+		o.JetStream = true
+	}
+}
+
+// WithStreams creates JetStream streams during container initialization.
+// This option can be used multiple times.
+func WithStreams(streams ...Stream) Option {
+	return func(o *P) {
+		o.Streams = append(o.Streams, streams...)
+	}
+}
+
+// WithConsumers creates durable JetStream consumers once the streams are
+// created. This option can be used multiple times.
+func WithConsumers(consumers ...Consumer) Option {
+	return func(o *P) {
+		o.Consumers = append(o.Consumers, consumers...)
+	}
+}
+
+// WithToken requires clients to authenticate using the provided token. It
+// should not be used together with WithUser.
+func WithToken(token string) Option {
+	return func(o *P) {
+		o.Token = token
+	}
+}
+
+// WithUser requires clients to authenticate using the provided credentials.
+// It should not be used together with WithToken.
+func WithUser(user, password string) Option {
+	return func(o *P) {
+		o.User = user
+		o.Password = password
+	}
+}
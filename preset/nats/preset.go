@@ -0,0 +1,217 @@
+// Package nats includes NATS implementation of Gnomock Preset interface. This
+// Preset can be passed to gnomock.Start() function to create a configured NATS
+// server to use in tests.
+//
+// The default port accepts client connections, and MonitoringPort serves
+// monitoring endpoints, such as "/varz" or "/jsz". JetStream is disabled
+// unless it is enabled using WithJetStream, or streams or consumers are
+// configured.
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// MonitoringPort is a name of the port exposed by NATS containers in addition
+// to the default client port.
+const MonitoringPort = "monitoring"
+
+const (
+	defaultVersion = "2.9.15"
+	defaultPort    = 4222
+	monitoringPort = 8222
+	connectTimeout = time.Second * 5
+)
+
+func init() {
+	registry.Register("nats", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock NATS preset. This preset includes a NATS
+// specific healthcheck function and default NATS image and ports, and allows
+// to optionally enable JetStream, create streams and consumers, and require
+// authentication.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for NATS.
+type P struct {
+	Version   string     `json:"version"`
+	JetStream bool       `json:"jetstream"`
+	Streams   []Stream   `json:"streams"`
+	Consumers []Consumer `json:"consumers"`
+	Token     string     `json:"token"`
+	User      string     `json:"user"`
+	Password  string     `json:"password"`
+}
+
+// Stream is a JetStream stream created during container initialization.
+type Stream struct {
+	// Name is the name of the stream, for example "ORDERS".
+	Name string `json:"name"`
+
+	// Subjects are the subjects stored in the stream, for example
+	// "orders.*". If not set, the stream stores messages sent to a subject
+	// with the same name as the stream.
+	Subjects []string `json:"subjects"`
+}
+
+// Consumer is a durable JetStream pull consumer with explicit
+// acknowledgements, created during container initialization.
+type Consumer struct {
+	// Stream is the name of the stream to create the consumer for.
+	Stream string `json:"stream"`
+
+	// Name is the durable name of the consumer.
+	Name string `json:"name"`
+
+	// FilterSubject is an optional subject to filter the messages by, for
+	// example "orders.created".
+	FilterSubject string `json:"filter_subject"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/library/nats:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[MonitoringPort] = gnomock.Port{Protocol: "tcp", Port: monitoringPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	args := []string{"--name", "gnomock", "--http_port", fmt.Sprintf("%d", monitoringPort)}
+
+	if p.jetStream() {
+		args = append(args, "--jetstream")
+	}
+
+	if p.Token != "" {
+		args = append(args, "--auth", p.Token)
+	}
+
+	if p.User != "" {
+		args = append(args, "--user", p.User, "--pass", p.Password)
+	}
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithCommand(args[0], args[1:]...),
+	}
+
+	if len(p.Streams) > 0 || len(p.Consumers) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+func (p *P) jetStream() bool {
+	return p.JetStream || len(p.Streams) > 0 || len(p.Consumers) > 0
+}
+
+// healthcheck makes sure that the server accepts client connections, and
+// that JetStream, when enabled, is available.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	addr := fmt.Sprintf("http://%s/healthz", c.Address(MonitoringPort))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var health struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return fmt.Errorf("can't decode health status (status %d): %w", resp.StatusCode, err)
+	}
+
+	if health.Status != "ok" {
+		return fmt.Errorf("unexpected health status: %s %s", health.Status, health.Error)
+	}
+
+	return nil
+}
+
+// initf creates the configured streams, and then the consumers.
+func (p *P) initf(_ context.Context, c *gnomock.Container) error {
+	opts := []nats.Option{nats.Timeout(connectTimeout)}
+
+	if p.Token != "" {
+		opts = append(opts, nats.Token(p.Token))
+	}
+
+	if p.User != "" {
+		opts = append(opts, nats.UserInfo(p.User, p.Password))
+	}
+
+	conn, err := nats.Connect("nats://"+c.DefaultAddress(), opts...)
+	if err != nil {
+		return fmt.Errorf("can't connect to nats: %w", err)
+	}
+
+	defer conn.Close()
+
+	js, err := conn.JetStream()
+	if err != nil {
+		return fmt.Errorf("can't use jetstream: %w", err)
+	}
+
+	for _, s := range p.Streams {
+		_, err := js.AddStream(&nats.StreamConfig{Name: s.Name, Subjects: s.Subjects})
+		if err != nil {
+			return fmt.Errorf("can't create stream '%s': %w", s.Name, err)
+		}
+	}
+
+	for _, cons := range p.Consumers {
+		_, err := js.AddConsumer(cons.Stream, &nats.ConsumerConfig{
+			Durable:       cons.Name,
+			FilterSubject: cons.FilterSubject,
+			AckPolicy:     nats.AckExplicitPolicy,
+		})
+		if err != nil {
+			return fmt.Errorf("can't create consumer '%s' of stream '%s': %w", cons.Name, cons.Stream, err)
+		}
+	}
+
+	return nil
+}
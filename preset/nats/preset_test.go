@@ -0,0 +1,94 @@
+package nats_test
+
+import (
+	"testing"
+
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/nats"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"2.8.4", "2.9.15"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := nats.Preset(
+			nats.WithVersion(version),
+			nats.WithUser("gnomock", "gnomick"),
+			nats.WithStreams(
+				nats.Stream{Name: "ORDERS", Subjects: []string{"orders.*"}},
+				nats.Stream{Name: "events"},
+			),
+			nats.WithConsumers(nats.Consumer{Stream: "ORDERS", Name: "created", FilterSubject: "orders.created"}),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		_, err = natsgo.Connect("nats://" + container.DefaultAddress())
+		require.Error(t, err)
+
+		conn, err := natsgo.Connect("nats://"+container.DefaultAddress(), natsgo.UserInfo("gnomock", "gnomick"))
+		require.NoError(t, err)
+
+		defer conn.Close()
+
+		js, err := conn.JetStream()
+		require.NoError(t, err)
+
+		_, err = js.Publish("orders.created", []byte("42"))
+		require.NoError(t, err)
+
+		_, err = js.Publish("orders.deleted", []byte("43"))
+		require.NoError(t, err)
+
+		_, err = js.Publish("events", []byte("44"))
+		require.NoError(t, err)
+
+		sub, err := js.PullSubscribe("orders.created", "created", natsgo.Bind("ORDERS", "created"))
+		require.NoError(t, err)
+
+		msgs, err := sub.Fetch(1)
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+		require.Equal(t, "42", string(msgs[0].Data))
+
+		info, err := js.StreamInfo("ORDERS")
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), info.State.Msgs)
+	}
+}
+
+func TestPreset_token(t *testing.T) {
+	t.Parallel()
+
+	p := nats.Preset(nats.WithToken("secret"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	conn, err := natsgo.Connect("nats://"+container.DefaultAddress(), natsgo.Token("secret"))
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	require.NoError(t, conn.Publish("subject", []byte("message")))
+
+	// JetStream is not enabled without streams, consumers or WithJetStream
+	js, err := conn.JetStream()
+	require.NoError(t, err)
+
+	_, err = js.AccountInfo()
+	require.Error(t, err)
+}
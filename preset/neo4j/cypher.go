@@ -0,0 +1,7 @@
+package neo4j
+
+import "github.com/orlangure/gnomock/internal/script"
+
+// cypher splits scripts into statements as they would be executed by
+// cypher-shell, where only "//" starts a comment.
+var cypher = script.Splitter{Language: "cypher", Comments: []string{"//"}}
@@ -0,0 +1,38 @@
+package neo4j
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	script := `// comment
+CREATE (:Person {name: 'Alice'});
+  // indented comment
+MATCH (p:Person)
+RETURN p;
+
+RETURN 1`
+
+	require.Equal(t, []string{
+		"CREATE (:Person {name: 'Alice'})",
+		"MATCH (p:Person)\nRETURN p",
+		"RETURN 1",
+	}, cypher.Split(script))
+	require.Empty(t, cypher.Split("// only a comment\n;\n"))
+}
+
+func TestReadFiles(t *testing.T) {
+	t.Parallel()
+
+	statements, err := cypher.ReadFiles([]string{"./testdata/graph.cypher", "./testdata/graph.cypher"})
+	require.NoError(t, err)
+	require.Len(t, statements, 6)
+
+	_, err = cypher.ReadFiles([]string{"./testdata/missing.cypher"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read cypher file")
+}
@@ -0,0 +1,58 @@
+package neo4j
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithPassword sets the password of DefaultUser. Neo4j 5 requires passwords
+// to be at least 8 characters long. If not used, the default password is
+// "gnomock-password".
+func WithPassword(password string) Option {
+	return func(o *P) {
+		o.Password = password
+	}
+}
+
+// WithoutAuth disables authentication, so that any client can connect
+// without credentials.
+func WithoutAuth() Option {
+	return func(o *P) {
+		o.NoAuth = true
+	}
+}
+
+// WithAPOC installs APOC plugin and allows to use all of its procedures. The
+// plugin is downloaded when the container starts, so internet access is
+// required.
+func WithAPOC() Option {
+	return func(o *P) {
+		o.APOC = true
+	}
+}
+
+// WithCypher executes the provided Cypher statements during initial setup,
+// after the statements from files provided using WithCypherFile. Every
+// statement runs in a separate transaction. This option can be used multiple
+// times.
+func WithCypher(statements ...string) Option {
+	return func(o *P) {
+		o.Cypher = append(o.Cypher, statements...)
+	}
+}
+
+// WithCypherFile sets a file name to read initial Cypher statements from. The
+// file may include multiple statements separated by semicolons, and comments
+// starting with "//" on separate lines. This option can be used multiple
+// times, and the files are executed in the same order.
+func WithCypherFile(file string) Option {
+	return func(o *P) {
+		o.CypherFiles = append(o.CypherFiles, file)
+	}
+}
@@ -0,0 +1,198 @@
+// Package neo4j includes Neo4j implementation of Gnomock Preset interface.
+// This Preset can be passed to gnomock.Start() function to create a configured
+// Neo4j container to use in tests.
+//
+// The default port accepts Bolt protocol connections, and HTTPPort serves
+// Neo4j HTTP API and browser. Unless authentication is disabled, both require
+// DefaultUser with the password provided using WithPassword.
+package neo4j
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+)
+
+// HTTPPort is a name of the port exposed by Neo4j containers in addition to
+// the default Bolt port.
+const HTTPPort = "http"
+
+// DefaultUser is the only user that exists in the container. Its password can
+// be set using WithPassword.
+const DefaultUser = "neo4j"
+
+const (
+	defaultVersion  = "5.6"
+	defaultPort     = 7687
+	httpPort        = 7474
+	defaultPassword = "gnomock-password"
+	defaultDatabase = "neo4j"
+)
+
+func init() {
+	registry.Register("neo4j", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Neo4j preset. This preset includes a Neo4j
+// specific healthcheck function and default Neo4j image and ports, and allows
+// to optionally set up initial state.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Neo4j.
+type P struct {
+	Version     string   `json:"version"`
+	Password    string   `json:"password"`
+	NoAuth      bool     `json:"no_auth"`
+	APOC        bool     `json:"apoc"`
+	Cypher      []string `json:"cypher"`
+	CypherFiles []string `json:"cypher_files"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/library/neo4j:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[HTTPPort] = gnomock.Port{Protocol: "tcp", Port: httpPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+	}
+
+	if p.NoAuth {
+		opts = append(opts, gnomock.WithEnv("NEO4J_AUTH=none"))
+	} else {
+		opts = append(opts, gnomock.WithEnv("NEO4J_AUTH="+DefaultUser+"/"+p.Password))
+	}
+
+	if p.APOC {
+		// the plugin is downloaded when the container starts
+		pluginsEnv := "NEO4J_PLUGINS"
+		if strings.HasPrefix(p.Version, "4.") {
+			pluginsEnv = "NEO4JLABS_PLUGINS"
+		}
+
+		opts = append(
+			opts,
+			gnomock.WithEnv(pluginsEnv+`=["apoc"]`),
+			gnomock.WithEnv("NEO4J_dbms_security_procedures_unrestricted=apoc.*"),
+		)
+	}
+
+	if len(p.Cypher) > 0 || len(p.CypherFiles) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.Password == "" {
+		p.Password = defaultPassword
+	}
+}
+
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	return p.run(ctx, c, `return 1`)
+}
+
+// initf executes statements from files and the rest of the statements, in
+// this order, each in a separate transaction.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	statements, err := cypher.ReadFiles(p.CypherFiles)
+	if err != nil {
+		return err
+	}
+
+	statements = append(statements, p.Cypher...)
+
+	for i, s := range statements {
+		if err := p.run(ctx, c, s); err != nil {
+			return fmt.Errorf("statement %d of %d failed (%s): %w", i+1, len(statements), sqlsetup.Snippet(s), err)
+		}
+	}
+
+	return nil
+}
+
+// run executes the provided Cypher statement in the default database using
+// HTTP API.
+func (p *P) run(ctx context.Context, c *gnomock.Container, statement string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"statements": []map[string]string{{"statement": statement}},
+	})
+	if err != nil {
+		return err
+	}
+
+	addr := fmt.Sprintf("http://%s/db/%s/tx/commit", c.Address(HTTPPort), defaultDatabase)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if !p.NoAuth {
+		req.SetBasicAuth(DefaultUser, p.Password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("can't decode response: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		return errors.New(result.Errors[0].Code + ": " + result.Errors[0].Message)
+	}
+
+	return nil
+}
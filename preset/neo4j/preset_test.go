@@ -0,0 +1,112 @@
+package neo4j_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/neo4j"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"4.4", "5.6"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := neo4j.Preset(
+			neo4j.WithVersion(version),
+			neo4j.WithPassword("secret-password"),
+			neo4j.WithCypherFile("./testdata/graph.cypher"),
+			neo4j.WithCypher(`CREATE (:Person {name: 'Carol'})`),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		row := query(t, container, "secret-password", `MATCH (p:Person) RETURN count(p)`)
+		require.Equal(t, []interface{}{float64(3)}, row)
+
+		row = query(t, container, "secret-password", `MATCH (:Person)-[k:KNOWS]->(:Person) RETURN count(k)`)
+		require.Equal(t, []interface{}{float64(1)}, row)
+	}
+}
+
+func TestPreset_apoc(t *testing.T) {
+	t.Parallel()
+
+	p := neo4j.Preset(neo4j.WithoutAuth(), neo4j.WithAPOC())
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	row := query(t, container, "", `RETURN apoc.text.capitalize('gnomock')`)
+	require.Equal(t, []interface{}{"Gnomock"}, row)
+}
+
+func TestPreset_wrongCypherFile(t *testing.T) {
+	t.Parallel()
+
+	p := neo4j.Preset(neo4j.WithCypherFile("./testdata/missing.cypher"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read cypher file")
+}
+
+// query runs the provided statement using HTTP API, and returns the first
+// row of the result.
+func query(t *testing.T, c *gnomock.Container, password, statement string) []interface{} {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"statements": []map[string]string{{"statement": statement}},
+	})
+	require.NoError(t, err)
+
+	addr := fmt.Sprintf("http://%s/db/neo4j/tx/commit", c.Address(neo4j.HTTPPort))
+
+	req, err := http.NewRequest(http.MethodPost, addr, bytes.NewReader(body)) // nolint:noctx
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if password != "" {
+		req.SetBasicAuth(neo4j.DefaultUser, password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	var result struct {
+		Results []struct {
+			Data []struct {
+				Row []interface{} `json:"row"`
+			} `json:"data"`
+		} `json:"results"`
+		Errors []interface{} `json:"errors"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Empty(t, result.Errors)
+	require.Len(t, result.Results, 1)
+	require.NotEmpty(t, result.Results[0].Data)
+
+	return result.Results[0].Data[0].Row
+}
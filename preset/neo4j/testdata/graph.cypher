@@ -0,0 +1,6 @@
+// people and their friendships
+CREATE (:Person {name: 'Alice'});
+CREATE (:Person {name: 'Bob'});
+
+MATCH (a:Person {name: 'Alice'}), (b:Person {name: 'Bob'})
+CREATE (a)-[:KNOWS]->(b);
@@ -0,0 +1,53 @@
+package oracle
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version. Only gvenzl/oracle-free tags are supported,
+// for example "23-slim-faststart" or "23-full".
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithDatabase creates a new pluggable database with the provided name. If
+// not used, the default FREEPDB1 pluggable database is used. Creating a new
+// pluggable database takes a few minutes.
+func WithDatabase(db string) Option {
+	return func(o *P) {
+		o.DB = db
+	}
+}
+
+// WithUser creates a new user with the provided credentials in the pluggable
+// database. If not used, the default credentials are gnomock:gnomick. SYS and
+// SYSTEM users use the same password.
+func WithUser(user, password string) Option {
+	return func(o *P) {
+		o.User = user
+		o.Password = password
+	}
+}
+
+// WithQueries executes the provided queries as the created user. Every query
+// should be a single SQL statement without a trailing semicolon, or a single
+// PL/SQL block.
+func WithQueries(queries ...string) Option {
+	return func(o *P) {
+		o.Queries = append(o.Queries, queries...)
+	}
+}
+
+// WithQueriesFile sets a file name to read initial queries from. Queries from
+// this file are executed before any other queries provided in WithQueries.
+// The file uses SQL*Plus conventions: SQL statements end with a semicolon at
+// the end of a line, and PL/SQL blocks end with a line containing a single
+// slash. This option can be used multiple times, and the files are executed
+// in the same order.
+func WithQueriesFile(file string) Option {
+	return func(o *P) {
+		o.QueriesFiles = append(o.QueriesFiles, file)
+	}
+}
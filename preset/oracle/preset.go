@@ -0,0 +1,182 @@
+// Package oracle includes Oracle Database implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured Oracle Database Free container to use in tests.
+//
+// This preset uses gvenzl/oracle-free images, and pure Go go-ora driver, so
+// that Oracle client libraries are not required to run the tests. Use
+// ConnString to get a connection string for "oracle" driver registered by
+// go-ora.
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+	goora "github.com/sijms/go-ora/v2"
+)
+
+const (
+	defaultVersion  = "23-slim-faststart"
+	defaultPort     = 1521
+	defaultUser     = "gnomock"
+	defaultPassword = "gnomick"
+	defaultDatabase = "FREEPDB1"
+
+	// even faststart images take a while to open the database, and creating
+	// a new pluggable database takes minutes
+	defaultTimeout             = time.Minute * 10
+	defaultHealthcheckInterval = time.Second
+)
+
+func init() {
+	registry.Register("oracle", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Oracle preset. This preset includes an Oracle
+// specific healthcheck function and default Oracle image and port, and allows
+// to optionally set up initial state.
+//
+// When used without any configuration, it creates gnomock user with gnomick
+// password in FREEPDB1 pluggable database. SYS and SYSTEM users use the same
+// password as the created user.
+//
+// Oracle takes a long time to start, so this preset waits for up to 10
+// minutes by default. Use `gnomock.WithTimeout` together with this preset to
+// change it.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Oracle.
+type P struct {
+	Version      string   `json:"version"`
+	DB           string   `json:"db"`
+	User         string   `json:"user"`
+	Password     string   `json:"password"`
+	Queries      []string `json:"queries"`
+	QueriesFiles []string `json:"queries_files"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/gvenzl/oracle-free:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithHealthCheckInterval(defaultHealthcheckInterval),
+		gnomock.WithTimeout(defaultTimeout),
+		gnomock.WithEnv("ORACLE_PASSWORD=" + p.Password),
+		gnomock.WithEnv("APP_USER=" + p.User),
+		gnomock.WithEnv("APP_USER_PASSWORD=" + p.Password),
+	}
+
+	// the default pluggable database already exists in the image
+	if p.DB != defaultDatabase {
+		opts = append(opts, gnomock.WithEnv("ORACLE_DATABASE="+p.DB))
+	}
+
+	if len(p.Queries) > 0 || len(p.QueriesFiles) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.DB == "" {
+		p.DB = defaultDatabase
+	}
+
+	if p.User == "" && p.Password == "" {
+		p.User = defaultUser
+		p.Password = defaultPassword
+	}
+}
+
+// healthcheck connects as the created user, which only exists once the
+// database is open and the container setup is complete.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	db, err := p.connect(c)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	var one int
+
+	return db.QueryRowContext(ctx, `select 1 from dual`).Scan(&one)
+}
+
+// initf executes statements from queries files, followed by the rest of the
+// queries, as the created user.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	queries, err := readScripts(p.QueriesFiles)
+	if err != nil {
+		return err
+	}
+
+	db, err := p.connect(c)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	return sqlsetup.Execute(ctx, db, append(queries, p.Queries...))
+}
+
+// ConnString returns a connection string that can be used to connect to the
+// pluggable database created in the provided container using "oracle" driver
+// registered by go-ora. Use the same options that were used to create the
+// preset, so that the connection string includes the right database name and
+// credentials.
+func ConnString(c *gnomock.Container, opts ...Option) string {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.setDefaults()
+
+	return p.connString(c)
+}
+
+func (p *P) connString(c *gnomock.Container) string {
+	return goora.BuildUrl(c.Host, c.DefaultPort(), p.DB, p.User, p.Password, nil)
+}
+
+func (p *P) connect(c *gnomock.Container) (*sql.DB, error) {
+	db, err := sql.Open("oracle", p.connString(c))
+	if err != nil {
+		return nil, err
+	}
+
+	return db, db.Ping()
+}
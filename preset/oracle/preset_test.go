@@ -0,0 +1,85 @@
+package oracle_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/oracle"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"23-slim-faststart"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		opts := []oracle.Option{
+			oracle.WithVersion(version),
+			oracle.WithUser("test_user", "test_password"),
+			oracle.WithQueriesFile("./testdata/queries.sql"),
+			oracle.WithQueries("insert into things (id, name) values (3, 'baz')"),
+		}
+
+		container, err := gnomock.Start(oracle.Preset(opts...))
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		db, err := sql.Open("oracle", oracle.ConnString(container, opts...))
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, db.Close()) }()
+
+		var count int
+
+		require.NoError(t, db.QueryRow("select count(*) from things").Scan(&count))
+		require.Equal(t, 3, count)
+
+		var name string
+
+		require.NoError(t, db.QueryRow("select name from things where id = 2").Scan(&name))
+		require.Equal(t, "bar", name)
+	}
+}
+
+func TestPreset_withDatabase(t *testing.T) {
+	t.Parallel()
+
+	opts := []oracle.Option{oracle.WithDatabase("GNOMOCK")}
+
+	container, err := gnomock.Start(oracle.Preset(opts...), gnomock.WithTimeout(time.Minute*15))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err := sql.Open("oracle", oracle.ConnString(container, opts...))
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, db.Close()) }()
+
+	var name string
+
+	require.NoError(t, db.QueryRow("select sys_context('userenv', 'con_name') from dual").Scan(&name))
+	require.Equal(t, "GNOMOCK", name)
+}
+
+func TestPreset_wrongQueriesFile(t *testing.T) {
+	t.Parallel()
+
+	p := oracle.Preset(oracle.WithQueriesFile("./testdata/missing.sql"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read queries file")
+}
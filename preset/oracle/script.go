@@ -0,0 +1,91 @@
+package oracle
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// blockRegexp matches the beginning of PL/SQL blocks and stored program
+// units, which include semicolons, and end with a line containing a single
+// slash, as in SQL*Plus scripts.
+var blockRegexp = regexp.MustCompile(
+	`(?i)^(declare|begin|create\s+(or\s+replace\s+)?(editionable\s+|noneditionable\s+)?` +
+		`(procedure|function|package|trigger|type))\b`,
+)
+
+// readScripts returns statements found in the provided files, in the same
+// order.
+func readScripts(files []string) ([]string, error) {
+	statements := []string{}
+
+	for _, f := range files {
+		bs, err := os.ReadFile(f) // nolint:gosec
+		if err != nil {
+			return nil, fmt.Errorf("can't read queries file '%s': %w", f, err)
+		}
+
+		statements = append(statements, splitScript(string(bs))...)
+	}
+
+	return statements, nil
+}
+
+// splitScript returns separate statements found in the provided SQL*Plus
+// style script. SQL statements end with a semicolon at the end of a line,
+// which is not sent to the server, and PL/SQL blocks end with a line
+// containing a single slash. Lines starting with "--" outside of PL/SQL
+// blocks are ignored.
+func splitScript(script string) []string {
+	statements := []string{}
+	current := []string{}
+	block := false
+
+	flush := func() {
+		if s := strings.TrimSpace(strings.Join(current, "\n")); s != "" {
+			statements = append(statements, s)
+		}
+
+		current, block = current[:0], false
+	}
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "/" {
+			flush()
+			continue
+		}
+
+		if block {
+			current = append(current, line)
+			continue
+		}
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		if len(current) == 0 && blockRegexp.MatchString(trimmed) {
+			block = true
+
+			current = append(current, line)
+
+			continue
+		}
+
+		if strings.HasSuffix(trimmed, ";") {
+			current = append(current, strings.TrimSuffix(strings.TrimRight(line, " \t\r"), ";"))
+			flush()
+
+			continue
+		}
+
+		current = append(current, line)
+	}
+
+	flush()
+
+	return statements
+}
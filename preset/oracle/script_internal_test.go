@@ -0,0 +1,30 @@
+package oracle
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitScript(t *testing.T) {
+	t.Parallel()
+
+	bs, err := os.ReadFile("./testdata/queries.sql")
+	require.NoError(t, err)
+
+	statements := splitScript(string(bs))
+	require.Equal(t, []string{
+		"create table things (\n\tid number primary key,\n\tname varchar2(50)\n)",
+		"insert into things (id, name) values (1, 'foo')",
+		"create or replace procedure add_thing(p_id number, p_name varchar2) as\nbegin\n\tinsert into things (id, name) values (p_id, p_name);\nend;",
+		"begin\n\tadd_thing(2, 'bar');\nend;",
+	}, statements)
+}
+
+func TestSplitScript_noTerminator(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"select 1 from dual"}, splitScript("select 1 from dual\n"))
+	require.Equal(t, []string{"begin null; end;"}, splitScript("begin null; end;"))
+}
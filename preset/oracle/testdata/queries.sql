@@ -0,0 +1,18 @@
+-- things used in tests
+create table things (
+	id number primary key,
+	name varchar2(50)
+);
+
+insert into things (id, name) values (1, 'foo');
+
+create or replace procedure add_thing(p_id number, p_name varchar2) as
+begin
+	insert into things (id, name) values (p_id, p_name);
+end;
+/
+
+begin
+	add_thing(2, 'bar');
+end;
+/
@@ -0,0 +1,42 @@
+package otelcollector
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithConfig sets collector configuration in YAML format. Components included
+// in the contrib distribution of the collector can be used. The configuration
+// must enable "health_check" extension on "0.0.0.0:13133", which is used to
+// make sure that the collector is ready. Receivers and exporters should use
+// "0.0.0.0" to be reachable through the exposed ports.
+func WithConfig(config string) Option {
+	return func(o *P) {
+		o.Config = config
+	}
+}
+
+// WithConfigFile sets a file name to read collector configuration from. It
+// takes precedence over the configuration provided using WithConfig, and has
+// the same requirements.
+func WithConfigFile(file string) Option {
+	return func(o *P) {
+		o.ConfigFile = file
+	}
+}
+
+// WithPrometheusExporter adds "prometheus" exporter to the metrics pipeline
+// of the default configuration. Received metrics are exposed on
+// PrometheusPort, and can be retrieved using MetricsText. This option has no
+// effect when a custom configuration is provided.
+func WithPrometheusExporter() Option {
+	return func(o *P) {
+		o.PrometheusExporter = true
+	}
+}
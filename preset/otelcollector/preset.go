@@ -0,0 +1,226 @@
+// Package otelcollector includes OpenTelemetry Collector implementation of
+// Gnomock Preset interface. This Preset can be passed to gnomock.Start()
+// function to create a configured OpenTelemetry Collector container to use in
+// tests.
+//
+// The default port accepts OTLP over gRPC, and OTLPHTTPPort accepts OTLP over
+// HTTP. By default, the collector logs all received telemetry. Metrics can
+// also be exposed in Prometheus format on PrometheusPort using
+// WithPrometheusExporter, so that the tests can scrape them and assert
+// exported telemetry.
+package otelcollector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/failinit"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// Named ports exposed by OpenTelemetry Collector containers in addition to
+// the default OTLP gRPC port.
+//
+// HealthPort serves "health_check" extension, which is used to make sure that
+// the collector is ready. Custom configurations must enable this extension
+// on "0.0.0.0:13133".
+//
+// PrometheusPort serves "prometheus" exporter enabled by
+// WithPrometheusExporter. Custom configurations can use it by configuring
+// this exporter on "0.0.0.0:8889".
+const (
+	OTLPHTTPPort   = "otlp-http"
+	HealthPort     = "health"
+	PrometheusPort = "prometheus"
+)
+
+const (
+	defaultVersion = "0.75.0"
+	defaultPort    = 4317
+	otlpHTTPPort   = 4318
+	healthPort     = 13133
+	prometheusPort = 8889
+
+	// configEnv is an environment variable that the collector reads its
+	// configuration from, since the image doesn't include a shell to write it
+	// to a file.
+	configEnv = "GNOMOCK_CONFIG"
+)
+
+func init() {
+	registry.Register("otelcollector", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock OpenTelemetry Collector preset. This preset
+// includes an OpenTelemetry Collector specific healthcheck function and
+// default OpenTelemetry Collector image and ports, and allows to optionally
+// provide collector configuration.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for OpenTelemetry Collector.
+type P struct {
+	Version            string `json:"version"`
+	Config             string `json:"config"`
+	ConfigFile         string `json:"config_file"`
+	PrometheusExporter bool   `json:"prometheus_exporter"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/otel/opentelemetry-collector-contrib:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[OTLPHTTPPort] = gnomock.Port{Protocol: "tcp", Port: otlpHTTPPort}
+	namedPorts[HealthPort] = gnomock.Port{Protocol: "tcp", Port: healthPort}
+	namedPorts[PrometheusPort] = gnomock.Port{Protocol: "tcp", Port: prometheusPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	config, err := p.config()
+	if err != nil {
+		return []gnomock.Option{gnomock.WithInit(failinit.Func(err))}
+	}
+
+	return []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithEnv(configEnv + "=" + config),
+		gnomock.WithCommand("--config=env:" + configEnv),
+	}
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// config returns the configuration provided using options, or the default
+// configuration that receives OTLP and logs everything it receives.
+func (p *P) config() (string, error) {
+	if p.ConfigFile != "" {
+		bs, err := os.ReadFile(p.ConfigFile) // nolint:gosec
+		if err != nil {
+			return "", fmt.Errorf("can't read config file '%s': %w", p.ConfigFile, err)
+		}
+
+		return string(bs), nil
+	}
+
+	if p.Config != "" {
+		return p.Config, nil
+	}
+
+	metricsExporters := "logging"
+	exporters := ""
+
+	if p.PrometheusExporter {
+		metricsExporters += ", prometheus"
+		exporters = fmt.Sprintf("\n  prometheus:\n    endpoint: 0.0.0.0:%d", prometheusPort)
+	}
+
+	return fmt.Sprintf(defaultConfig, healthPort, defaultPort, otlpHTTPPort, exporters, metricsExporters), nil
+}
+
+const defaultConfig = `extensions:
+  health_check:
+    endpoint: 0.0.0.0:%d
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:%d
+      http:
+        endpoint: 0.0.0.0:%d
+exporters:
+  logging:
+    verbosity: detailed%s
+service:
+  extensions: [health_check]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [logging]
+    metrics:
+      receivers: [otlp]
+      exporters: [%s]
+    logs:
+      receivers: [otlp]
+      exporters: [logging]
+`
+
+// healthcheck uses "health_check" extension to make sure that all the
+// pipelines are started.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	addr := fmt.Sprintf("http://%s/", c.Address(HealthPort))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// MetricsText returns metrics exposed by "prometheus" exporter of the
+// provided container in Prometheus text format. It can be used with
+// WithPrometheusExporter to assert which metrics were exported to the
+// collector.
+func MetricsText(ctx context.Context, c *gnomock.Container) (string, error) {
+	addr := fmt.Sprintf("http://%s/metrics", c.Address(PrometheusPort))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var buf strings.Builder
+
+	if _, err := io.Copy(&buf, resp.Body); err != nil {
+		return "", fmt.Errorf("can't read metrics: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,32 @@
+package otelcollector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default", func(t *testing.T) {
+		config, err := (&P{}).config()
+		require.NoError(t, err)
+		require.Contains(t, config, "endpoint: 0.0.0.0:13133")
+		require.Contains(t, config, "exporters: [logging]\n    logs:")
+		require.NotContains(t, config, "prometheus")
+	})
+
+	t.Run("prometheus exporter", func(t *testing.T) {
+		config, err := (&P{PrometheusExporter: true}).config()
+		require.NoError(t, err)
+		require.Contains(t, config, "verbosity: detailed\n  prometheus:\n    endpoint: 0.0.0.0:8889\nservice:")
+		require.Contains(t, config, "exporters: [logging, prometheus]")
+	})
+
+	t.Run("config file takes precedence", func(t *testing.T) {
+		config, err := (&P{Config: "foo", ConfigFile: "./testdata/config.yaml"}).config()
+		require.NoError(t, err)
+		require.Contains(t, config, "namespace: gnomock")
+	})
+}
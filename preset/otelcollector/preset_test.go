@@ -0,0 +1,91 @@
+package otelcollector_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/otelcollector"
+	"github.com/stretchr/testify/require"
+)
+
+// metric is a minimal OTLP/JSON export request with a single gauge.
+const metric = `{
+  "resourceMetrics": [{
+    "scopeMetrics": [{
+      "metrics": [{
+        "name": "requests",
+        "gauge": {"dataPoints": [{"asInt": "42", "timeUnixNano": "%d"}]}
+      }]
+    }]
+  }]
+}`
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"0.74.0", "0.75.0"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := otelcollector.Preset(
+			otelcollector.WithVersion(version),
+			otelcollector.WithPrometheusExporter(),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+		requireMetric(t, container, "requests 42")
+	}
+}
+
+func TestPreset_configFile(t *testing.T) {
+	t.Parallel()
+
+	p := otelcollector.Preset(otelcollector.WithConfigFile("./testdata/config.yaml"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+	requireMetric(t, container, "gnomock_requests 42")
+}
+
+func TestPreset_wrongConfigFile(t *testing.T) {
+	t.Parallel()
+
+	p := otelcollector.Preset(otelcollector.WithConfigFile("./testdata/missing.yaml"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't init container: can't read config file")
+}
+
+func requireMetric(t *testing.T, c *gnomock.Container, expected string) {
+	t.Helper()
+
+	body := fmt.Sprintf(metric, time.Now().UnixNano())
+	addr := fmt.Sprintf("http://%s/v1/metrics", c.Address(otelcollector.OTLPHTTPPort))
+
+	resp, err := http.Post(addr, "application/json", strings.NewReader(body)) // nolint:gosec,noctx
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Eventually(t, func() bool {
+		metrics, err := otelcollector.MetricsText(context.Background(), c)
+
+		return err == nil && strings.Contains(metrics, expected)
+	}, time.Second*10, time.Millisecond*250)
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/hostpath"
+)
+
+const (
+	archiveDir    = "/gnomock/archive"
+	walArchiveDir = archiveDir + "/wal"
+	backupsDir    = archiveDir + "/base"
+
+	archiveTimeout = time.Second * 30
+)
+
+var backupNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// archiveSetup makes the archive directory mounted from the host writable by
+// postgres user.
+const archiveSetup = `mkdir -p ` + walArchiveDir + ` ` + backupsDir + `
+chmod 0777 ` + archiveDir + ` ` + walArchiveDir + ` ` + backupsDir + `
+`
+
+// archiveCommand copies completed WAL segments into the archive, and makes
+// them readable on the host.
+const archiveCommand = `test ! -f ` + walArchiveDir + `/%f && cp %p ` + walArchiveDir + `/%f && chmod 644 ` + walArchiveDir + `/%f`
+
+// recoverySetup restores the data directory from a base backup found in the
+// archive, and configures the server to replay archived WAL segments up to
+// the target time, if it is set, and then to accept writes. The data
+// directory is only restored once, so that an existing container can be
+// reused.
+const recoverySetup = `if [ ! -s "$PGDATA/PG_VERSION" ]; then
+	mkdir -p "$PGDATA"
+	tar -xzf "` + backupsDir + `/$GNOMOCK_PITR_BACKUP/base.tar.gz" -C "$PGDATA"
+	touch "$PGDATA/recovery.signal"
+	echo "restore_command = 'cp ` + walArchiveDir + `/%f %p'" >> "$PGDATA/postgresql.auto.conf"
+	echo "recovery_target_action = 'promote'" >> "$PGDATA/postgresql.auto.conf"
+	if [ -n "$GNOMOCK_PITR_TARGET" ]; then
+		echo "recovery_target_time = '$GNOMOCK_PITR_TARGET'" >> "$PGDATA/postgresql.auto.conf"
+	fi
+	chown -R postgres:postgres "$PGDATA"
+	chmod 0700 "$PGDATA"
+fi
+`
+
+// Recovery describes point-in-time recovery of a new container from a base
+// backup and WAL segments archived by another container.
+type Recovery struct {
+	// Archive is a host directory used with WithWALArchive by the original
+	// container.
+	Archive string `json:"archive"`
+
+	// Backup is the name of a base backup created using BaseBackup.
+	Backup string `json:"backup"`
+
+	// Target is the time to recover to, in a format postgres understands,
+	// such as "2021-01-02 15:04:05.999999+00". When empty, all the archived
+	// WAL segments are replayed.
+	Target string `json:"target"`
+}
+
+// setArchiveDefaults configures the server to archive WAL segments, unless
+// the same parameters are set explicitly.
+func (p *P) setArchiveDefaults() {
+	defaults := map[string]string{
+		"wal_level":       "replica",
+		"archive_mode":    "on",
+		"archive_command": archiveCommand,
+	}
+
+	if p.Config == nil {
+		p.Config = make(map[string]string, len(defaults))
+	}
+
+	for k, v := range defaults {
+		if _, ok := p.Config[k]; !ok {
+			p.Config[k] = v
+		}
+	}
+}
+
+// archiveOptions mount the archive directory, and return a script to run
+// before the container starts.
+func (p *P) archiveOptions() ([]gnomock.Option, string) {
+	dir, setup := p.WALArchive, ""
+
+	if p.Recovery != nil {
+		dir = p.Recovery.Archive
+	}
+
+	if dir == "" {
+		return nil, ""
+	}
+
+	opts := []gnomock.Option{gnomock.WithHostMounts(hostpath.Abs(dir), archiveDir)}
+
+	if p.WALArchive != "" {
+		setup += archiveSetup
+	}
+
+	if p.Recovery != nil {
+		setup += recoverySetup
+		opts = append(
+			opts,
+			gnomock.WithEnv("GNOMOCK_PITR_BACKUP="+p.Recovery.Backup),
+			gnomock.WithEnv("GNOMOCK_PITR_TARGET="+p.Recovery.Target),
+		)
+	}
+
+	return opts, setup
+}
+
+// BaseBackup creates a base backup of the server running in the provided
+// container, which must be created using WithWALArchive option. The backup
+// is saved as "base/<name>/base.tar.gz" in the archive directory, and can be
+// used to start a new container using WithPointInTimeRecovery. Backup name
+// may only include letters, digits, dots, dashes and underscores.
+func BaseBackup(c *gnomock.Container, name string) error {
+	if !backupNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid backup name '%s'", name)
+	}
+
+	db, err := connect(c, defaultDatabase)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	dir := backupsDir + "/" + name
+	cmd := fmt.Sprintf(
+		"pg_basebackup -U %s -D %s -Ft -z -X fetch -c fast && chmod -R a+rwX %s",
+		defaultUser, dir, dir,
+	)
+
+	// the program runs inside the container as postgres user, which is
+	// allowed to connect locally without password
+	_, err = db.Exec("copy (select 1) to program " + pq.QuoteLiteral(cmd))
+	if err != nil {
+		return fmt.Errorf("can't create base backup '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// SwitchWAL forces the server running in the provided container to switch to
+// a new WAL segment, and waits until the previous segment is archived. Use
+// it to make sure that all the changes made so far are available in the
+// archive directory of WithWALArchive.
+func SwitchWAL(c *gnomock.Container) error {
+	db, err := connect(c, defaultDatabase)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	var segment string
+
+	if err := db.QueryRow("select pg_walfile_name(pg_switch_wal())").Scan(&segment); err != nil {
+		return fmt.Errorf("can't switch wal: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), archiveTimeout)
+	defer cancel()
+
+	var archived string
+
+	for {
+		err := db.QueryRowContext(ctx, `
+			select coalesce(last_archived_wal, '') from pg_stat_archiver
+		`).Scan(&archived)
+		if err == nil && archived >= segment {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("segment '%s' is not archived (last '%s'): %w", segment, archived, ctx.Err())
+		case <-time.After(time.Millisecond * 250):
+		}
+	}
+}
@@ -21,6 +21,23 @@ func WithDatabase(db string) Option {
 	}
 }
 
+// WithDatabases creates additional databases in the container, and executes
+// the provided queries in each of them. Keys of the map are database names,
+// and values are queries to execute. Additional databases are set up after
+// the database created with WithDatabase, in alphabetical order. This option
+// can be used multiple times.
+func WithDatabases(databases map[string][]string) Option {
+	return func(p *P) {
+		if p.Databases == nil {
+			p.Databases = make(map[string][]string, len(databases))
+		}
+
+		for name, queries := range databases {
+			p.Databases[name] = append(p.Databases[name], queries...)
+		}
+	}
+}
+
 // WithQueries executes the provided queries against the database created with
 // WithDatabase, or against default postgres database.
 func WithQueries(queries ...string) Option {
@@ -37,6 +54,45 @@ func WithQueriesFile(file string) Option {
 	}
 }
 
+// WithMigrations applies golang-migrate migrations from the provided source
+// URL, for example "file://migrations", to the database during initial setup.
+// Migrations run after roles and extensions are created, and before any
+// queries are executed, so they can be used to create the schema.
+func WithMigrations(sourceURL string) Option {
+	return func(p *P) {
+		p.Migrations = sourceURL
+	}
+}
+
+// WithWALArchive enables continuous archiving of write-ahead log segments into
+// the provided host directory, which is mounted into the container. Archived
+// segments are saved in "wal" subdirectory, and base backups created using
+// BaseBackup are saved in "base" subdirectory. Use SwitchWAL to make sure the
+// latest changes are archived. The directory becomes writable by any user.
+//
+// Together with BaseBackup, this option allows to test backup tooling, and to
+// start a new container using WithPointInTimeRecovery.
+func WithWALArchive(dir string) Option {
+	return func(p *P) {
+		p.WALArchive = dir
+	}
+}
+
+// WithPointInTimeRecovery restores the data of a new container from a base
+// backup with the provided name, created using BaseBackup in the provided
+// archive directory, and replays WAL segments archived by WithWALArchive. If
+// target is not empty, changes are only replayed up to this time, for example
+// "2021-01-02 15:04:05.999999+00". The container becomes healthy once the
+// recovery is complete. Initial setup options, such as WithQueries, still
+// apply to the recovered database.
+//
+// Recovery requires Postgres 12 or later.
+func WithPointInTimeRecovery(dir, backup, target string) Option {
+	return func(p *P) {
+		p.Recovery = &Recovery{Archive: dir, Backup: backup, Target: target}
+	}
+}
+
 // WithVersion sets image version.
 func WithVersion(version string) Option {
 	return func(o *P) {
@@ -44,9 +100,119 @@ func WithVersion(version string) Option {
 	}
 }
 
-// WithTimezone sets the timezone in this container.
+// WithTimezone sets the timezone in this container. It is also used as the
+// default server timezone (`timezone` setting), which affects how timestamps
+// are converted and displayed.
 func WithTimezone(timezone string) Option {
 	return func(p *P) {
 		p.Timezone = timezone
 	}
 }
+
+// WithDump restores the provided pg_dump output into the database created
+// with WithDatabase, or into default postgres database. Both plain SQL and
+// custom format (pg_dump -Fc) dumps are supported. The dump is mounted into
+// the container and restored when the database is initialized, before any
+// queries are executed. The dump file must be readable by any user. Object
+// ownership is not restored for custom format dumps.
+//
+// If the dump can't be restored, the container fails to start; use
+// gnomock.WithDebugMode to see the error.
+func WithDump(path string) Option {
+	return func(p *P) {
+		p.Dump = path
+	}
+}
+
+// WithExtensions creates the provided extensions, such as "uuid-ossp" or
+// "pg_trgm", in the database created with WithDatabase, or in default
+// postgres database. Extensions are created before any queries are executed.
+// Initial setup fails if any of the extensions is not available in the image.
+func WithExtensions(extensions ...string) Option {
+	return func(p *P) {
+		p.Extensions = append(p.Extensions, extensions...)
+	}
+}
+
+// WithRole creates a new login role with the provided credentials. Unlike
+// WithUser, the new role is not a superuser, so it can be used to make sure
+// the application works with restricted permissions. Additional role
+// attributes, such as "CREATEDB" or "CONNECTION LIMIT 5", can be provided as
+// options.
+//
+// Roles are created before any queries are executed, so WithQueries can be
+// used to grant the required privileges, for example
+// "grant select, insert on all tables in schema public to app".
+func WithRole(name, password string, options ...string) Option {
+	return func(p *P) {
+		p.Roles = append(p.Roles, Role{Name: name, Password: password, Options: options})
+	}
+}
+
+// WithLocale sets the locale of the database cluster, such as "fr_FR.UTF-8",
+// which is used by all databases, including the one created with
+// WithDatabase. Official images based on Debian only include "en_US.UTF-8"
+// and "C" locales, while Alpine images don't support locales at all, so other
+// values require a custom image.
+func WithLocale(locale string) Option {
+	return func(p *P) {
+		p.Locale = locale
+	}
+}
+
+// WithEncoding sets the default encoding of the database cluster, such as
+// "UTF8" or "LATIN1". The encoding must be compatible with the locale.
+func WithEncoding(encoding string) Option {
+	return func(p *P) {
+		p.Encoding = encoding
+	}
+}
+
+// WithConfig overrides server configuration parameters, such as
+// "max_connections", "shared_buffers" or "log_statement". The parameters are
+// passed to the server using `-c` command line flags, so they take precedence
+// over postgresql.conf. This option can be used multiple times.
+func WithConfig(config map[string]string) Option {
+	return func(p *P) {
+		if p.Config == nil {
+			p.Config = make(map[string]string, len(config))
+		}
+
+		for k, v := range config {
+			p.Config[k] = v
+		}
+	}
+}
+
+// WithLogicalReplication configures the server for logical replication, which
+// allows to test change data capture consumers, such as Debezium, against
+// this container. It sets `wal_level` to `logical`, and increases the number
+// of replication slots and WAL senders, unless they are set using WithConfig.
+//
+// A role with REPLICATION attribute is created using ReplicationUser and
+// ReplicationPassword credentials. Use WithQueries to grant it access to the
+// replicated tables, or to create publications.
+func WithLogicalReplication() Option {
+	return func(p *P) {
+		p.LogicalReplication = true
+	}
+}
+
+// WithTimescaleDB uses TimescaleDB image of the provided version, such as
+// "2.9.1-pg14", instead of the official Postgres image, and creates
+// `timescaledb` extension in the database. WithVersion option is ignored when
+// this option is used.
+func WithTimescaleDB(version string) Option {
+	return func(p *P) {
+		p.TimescaleDB = version
+	}
+}
+
+// WithPostGIS uses PostGIS image of the provided version, such as "15-3.3",
+// instead of the official Postgres image, and creates `postgis` extension in
+// the database. WithVersion option is ignored when this option is used.
+func WithPostGIS(version string) Option {
+	return func(p *P) {
+		p.PostGIS = version
+	}
+}
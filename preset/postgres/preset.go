@@ -4,13 +4,24 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
-	_ "github.com/lib/pq" // postgres driver
+	"github.com/golang-migrate/migrate/v4/database"
+	migratepostgres "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/lib/pq"
 	"github.com/orlangure/gnomock"
 	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+)
+
+// Credentials of the role created by WithLogicalReplication option.
+const (
+	ReplicationUser     = "replicator"
+	ReplicationPassword = "replicator"
 )
 
 const (
@@ -20,8 +31,29 @@ const (
 	defaultSSLMode  = "disable"
 	defaultPort     = 5432
 	defaultVersion  = "12.5"
+
+	dumpFile = "/gnomock/dump"
+
+	duplicateObjectCode = "42710"
 )
 
+// restoreSetup adds a script to restore the dump mounted into the container to
+// the list of scripts the official image runs when the database is
+// initialized. Custom format dumps are restored using pg_restore, and any
+// other dumps are executed by psql.
+const restoreSetup = `cat > /docker-entrypoint-initdb.d/gnomock-restore.sh <<'EOF'
+if [ "$(head -c 5 ` + dumpFile + `)" = "PGDMP" ]; then
+	pg_restore --no-owner --exit-on-error -U "$POSTGRES_USER" -d "$POSTGRES_DB" ` + dumpFile + `
+else
+	psql -v ON_ERROR_STOP=1 -U "$POSTGRES_USER" -d "$POSTGRES_DB" -f ` + dumpFile + `
+fi
+EOF
+`
+
+// entrypoint starts the container as usual, with the command passed as
+// arguments, once any custom setup is done.
+const entrypoint = `exec docker-entrypoint.sh "$0" "$@"`
+
 func init() {
 	registry.Register("postgres", func() gnomock.Preset { return &P{} })
 }
@@ -44,17 +76,46 @@ func Preset(opts ...Option) gnomock.Preset {
 
 // P is a Gnomock Preset implementation of PostgreSQL database.
 type P struct {
-	DB           string   `json:"db"`
-	Queries      []string `json:"queries"`
-	QueriesFiles []string `json:"queries_files"`
-	User         string   `json:"user"`
-	Password     string   `json:"password"`
-	Timezone     string   `json:"timezone"`
-	Version      string   `json:"version"`
+	DB           string              `json:"db"`
+	Queries      []string            `json:"queries"`
+	QueriesFiles []string            `json:"queries_files"`
+	User         string              `json:"user"`
+	Password     string              `json:"password"`
+	Timezone     string              `json:"timezone"`
+	Version      string              `json:"version"`
+	Dump         string              `json:"dump"`
+	Extensions   []string            `json:"extensions"`
+	Roles        []Role              `json:"roles"`
+	Locale       string              `json:"locale"`
+	Encoding     string              `json:"encoding"`
+	Config       map[string]string   `json:"config"`
+	Databases    map[string][]string `json:"databases"`
+	Migrations   string              `json:"migrations"`
+	WALArchive   string              `json:"wal_archive"`
+	Recovery     *Recovery           `json:"recovery"`
+
+	LogicalReplication bool   `json:"logical_replication"`
+	TimescaleDB        string `json:"timescaledb"`
+	PostGIS            string `json:"postgis"`
+}
+
+// Role is a login role created in the container during initial setup.
+type Role struct {
+	Name     string   `json:"name"`
+	Password string   `json:"password"`
+	Options  []string `json:"options"`
 }
 
 // Image returns an image that should be pulled to create this container.
 func (p *P) Image() string {
+	if p.TimescaleDB != "" {
+		return fmt.Sprintf("docker.io/timescale/timescaledb:%s", p.TimescaleDB)
+	}
+
+	if p.PostGIS != "" {
+		return fmt.Sprintf("docker.io/postgis/postgis:%s", p.PostGIS)
+	}
+
 	return fmt.Sprintf("docker.io/library/postgres:%s", p.Version)
 }
 
@@ -76,20 +137,132 @@ func (p *P) Options() []gnomock.Option {
 		p.Queries = append(p.Queries, q)
 	}
 
+	if p.LogicalReplication {
+		p.setReplicationDefaults()
+	}
+
+	if p.WALArchive != "" {
+		p.setArchiveDefaults()
+	}
+
+	if p.TimescaleDB != "" {
+		p.Extensions = append([]string{"timescaledb"}, p.Extensions...)
+	}
+
+	if p.PostGIS != "" {
+		p.Extensions = append([]string{"postgis"}, p.Extensions...)
+	}
+
 	opts := []gnomock.Option{
 		gnomock.WithHealthCheck(p.healthcheck),
 		gnomock.WithEnv("POSTGRES_PASSWORD=" + defaultPassword),
-		gnomock.WithEnv("TZ=" + p.Timezone),
 		gnomock.WithInit(p.initf()),
 	}
 
+	// server timezone setting is initialized from TZ variable when the
+	// database cluster is created
 	if p.Timezone != "" {
 		opts = append(opts, gnomock.WithEnv("TZ="+p.Timezone))
 	}
 
+	archiveOpts, setup := p.archiveOptions()
+	opts = append(opts, archiveOpts...)
+
+	if p.Dump != "" {
+		setup += restoreSetup
+	}
+
+	// docker resets the command when entrypoint is replaced, so it is always
+	// set explicitly when any setup is done
+	if len(p.Config) > 0 || setup != "" {
+		opts = append(opts, gnomock.WithCommand("postgres", p.configArgs()...))
+	}
+
+	if setup != "" {
+		opts = append(opts, gnomock.WithEntrypoint("/bin/sh", "-c", setup+entrypoint))
+	}
+
+	if initdbArgs := p.initdbArgs(); initdbArgs != "" {
+		opts = append(opts, gnomock.WithEnv("POSTGRES_INITDB_ARGS="+initdbArgs))
+	}
+
+	if p.Dump != "" {
+		dump, err := filepath.Abs(p.Dump)
+		if err != nil {
+			dump = p.Dump
+		}
+
+		// the dump is restored during database initialization, so the
+		// database must be created by the image itself
+		opts = append(
+			opts,
+			gnomock.WithEnv("POSTGRES_DB="+p.DB),
+			gnomock.WithHostMounts(dump, dumpFile),
+		)
+	}
+
 	return opts
 }
 
+// setReplicationDefaults configures the server to allow logical replication,
+// unless the same parameters are set explicitly, and adds a role that can be
+// used by replication clients.
+func (p *P) setReplicationDefaults() {
+	defaults := map[string]string{
+		"wal_level":             "logical",
+		"max_replication_slots": "10",
+		"max_wal_senders":       "10",
+	}
+
+	if p.Config == nil {
+		p.Config = make(map[string]string, len(defaults))
+	}
+
+	for k, v := range defaults {
+		if _, ok := p.Config[k]; !ok {
+			p.Config[k] = v
+		}
+	}
+
+	p.Roles = append(p.Roles, Role{
+		Name:     ReplicationUser,
+		Password: ReplicationPassword,
+		Options:  []string{"replication"},
+	})
+}
+
+// configArgs returns server command line arguments that override
+// configuration parameters, sorted by parameter name.
+func (p *P) configArgs() []string {
+	keys := make([]string, 0, len(p.Config))
+	for k := range p.Config {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	args := make([]string, 0, len(keys)*2)
+	for _, k := range keys {
+		args = append(args, "-c", k+"="+p.Config[k])
+	}
+
+	return args
+}
+
+func (p *P) initdbArgs() string {
+	args := []string{}
+
+	if p.Locale != "" {
+		args = append(args, "--locale="+p.Locale)
+	}
+
+	if p.Encoding != "" {
+		args = append(args, "--encoding="+p.Encoding)
+	}
+
+	return strings.Join(args, " ")
+}
+
 func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
 	db, err := connect(c, defaultDatabase)
 	if err != nil {
@@ -102,26 +275,32 @@ func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
 
 	var one int
 
-	return db.QueryRow(`select 1`).Scan(&one)
+	if err := db.QueryRow(`select 1`).Scan(&one); err != nil {
+		return err
+	}
+
+	// recovered server only accepts writes once recovery is complete
+	if p.Recovery != nil {
+		var inRecovery bool
+
+		if err := db.QueryRow(`select pg_is_in_recovery()`).Scan(&inRecovery); err != nil {
+			return err
+		}
+
+		if inRecovery {
+			return errors.New("recovery is in progress")
+		}
+	}
+
+	return nil
 }
 
 func (p *P) initf() gnomock.InitFunc {
 	return func(ctx context.Context, c *gnomock.Container) error {
 		if p.DB != defaultDatabase {
-			db, err := connect(c, defaultDatabase)
-			if err != nil {
+			if err := createDatabase(c, p.DB); err != nil {
 				return err
 			}
-
-			_, err = db.Exec("create database " + p.DB)
-			if err != nil {
-				isDuplicateDB := strings.Contains(err.Error(), fmt.Sprintf(`pq: database "%s" already exists`, p.DB))
-				if !isDuplicateDB {
-					return err
-				}
-			}
-
-			_ = db.Close()
 		}
 
 		db, err := connect(c, p.DB)
@@ -131,14 +310,80 @@ func (p *P) initf() gnomock.InitFunc {
 
 		defer func() { _ = db.Close() }()
 
-		if err := p.executeQueries(db); err != nil {
+		if err := p.createRoles(db); err != nil {
+			return err
+		}
+
+		if err := p.createExtensions(db); err != nil {
+			return err
+		}
+
+		setup := sqlsetup.Setup{
+			Queries:      p.Queries,
+			QueriesFiles: p.QueriesFiles,
+			Migrations:   p.Migrations,
+		}
+
+		if err := setup.Apply(ctx, db, p.DB, migrationsDriver); err != nil {
 			return fmt.Errorf("can't execute setup queries: %w", err)
 		}
 
-		return nil
+		return p.setupDatabases(c)
 	}
 }
 
+// setupDatabases creates additional databases, and executes their queries.
+func (p *P) setupDatabases(c *gnomock.Container) error {
+	names := make([]string, 0, len(p.Databases))
+	for name := range p.Databases {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := createDatabase(c, name); err != nil {
+			return err
+		}
+
+		db, err := connect(c, name)
+		if err != nil {
+			return err
+		}
+
+		for _, q := range p.Databases[name] {
+			if _, err := db.Exec(q); err != nil {
+				_ = db.Close()
+
+				return fmt.Errorf("can't execute setup queries in database '%s': %w", name, err)
+			}
+		}
+
+		_ = db.Close()
+	}
+
+	return nil
+}
+
+func createDatabase(c *gnomock.Container, name string) error {
+	db, err := connect(c, defaultDatabase)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	_, err = db.Exec("create database " + name)
+	if err != nil {
+		isDuplicateDB := strings.Contains(err.Error(), fmt.Sprintf(`pq: database "%s" already exists`, name))
+		if !isDuplicateDB {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (p *P) setDefaults() {
 	if p.DB == "" {
 		p.DB = defaultDatabase
@@ -149,36 +394,88 @@ func (p *P) setDefaults() {
 	}
 }
 
-func (p *P) executeQueries(db *sql.DB) error {
-	if len(p.QueriesFiles) > 0 {
-		for _, f := range p.QueriesFiles {
-			bs, err := os.ReadFile(f) // nolint:gosec
-			if err != nil {
-				return fmt.Errorf("can't read queries file '%s': %w", f, err)
+func (p *P) createRoles(db *sql.DB) error {
+	for _, r := range p.Roles {
+		q := fmt.Sprintf(`create role "%s" with login password '%s'`, r.Name, r.Password)
+		if len(r.Options) > 0 {
+			q += " " + strings.Join(r.Options, " ")
+		}
+
+		_, err := db.Exec(q)
+		if err != nil {
+			// roles already exist when an existing container is reused
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == duplicateObjectCode {
+				continue
 			}
 
-			p.Queries = append([]string{string(bs)}, p.Queries...)
+			return fmt.Errorf("can't create role '%s': %w", r.Name, err)
 		}
 	}
 
-	for _, q := range p.Queries {
-		_, err := db.Exec(q)
+	return nil
+}
+
+func (p *P) createExtensions(db *sql.DB) error {
+	for _, ext := range p.Extensions {
+		var available bool
+
+		err := db.QueryRow(
+			`select exists(select 1 from pg_available_extensions where name = $1)`, ext,
+		).Scan(&available)
 		if err != nil {
-			return err
+			return fmt.Errorf("can't check extension '%s': %w", ext, err)
+		}
+
+		if !available {
+			return fmt.Errorf("extension '%s' is not available in image '%s'", ext, p.Image())
+		}
+
+		_, err = db.Exec(fmt.Sprintf(`create extension if not exists "%s"`, ext))
+		if err != nil {
+			return fmt.Errorf("can't create extension '%s': %w", ext, err)
 		}
 	}
 
 	return nil
 }
 
-func connect(c *gnomock.Container, db string) (*sql.DB, error) {
-	connStr := fmt.Sprintf(
+func migrationsDriver(db *sql.DB) (database.Driver, error) {
+	return migratepostgres.WithInstance(db, &migratepostgres.Config{})
+}
+
+// ConnString returns a connection string that can be used to connect to the
+// database created in the provided container. Use the same options that were
+// used to create the preset, so that the connection string includes the right
+// database name and credentials. Default values are used for options that are
+// not provided.
+func ConnString(c *gnomock.Container, opts ...Option) string {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.setDefaults()
+
+	user, password := defaultUser, defaultPassword
+	if p.User != "" && p.Password != "" {
+		user, password = p.User, p.Password
+	}
+
+	return connString(c, user, password, p.DB)
+}
+
+func connString(c *gnomock.Container, user, password, db string) string {
+	return fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port(gnomock.DefaultPort),
-		defaultUser, defaultPassword, db, defaultSSLMode,
+		user, password, db, defaultSSLMode,
 	)
+}
 
-	conn, err := sql.Open("postgres", connStr)
+func connect(c *gnomock.Container, db string) (*sql.DB, error) {
+	conn, err := sql.Open("postgres", connString(c, defaultUser, defaultPassword, db))
 	if err != nil {
 		return nil, err
 	}
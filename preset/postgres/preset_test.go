@@ -3,6 +3,7 @@ package postgres_test
 import (
 	"database/sql"
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/orlangure/gnomock"
@@ -66,6 +67,329 @@ func testPreset(version string) func(t *testing.T) {
 	}
 }
 
+func TestPreset_withDump(t *testing.T) {
+	t.Parallel()
+
+	p := postgres.Preset(
+		postgres.WithDatabase("mydb"),
+		postgres.WithDump("./testdata/dump.sql"),
+		postgres.WithQueries("insert into accounts (id, name) values (3, 'baz')"),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s  dbname=%s sslmode=disable",
+		container.Host, container.DefaultPort(),
+		"postgres", "password", "mydb",
+	)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+
+	var count int
+
+	require.NoError(t, db.QueryRow("select count(*) from accounts").Scan(&count))
+	require.Equal(t, 3, count)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withExtensions(t *testing.T) {
+	t.Parallel()
+
+	p := postgres.Preset(
+		postgres.WithExtensions("uuid-ossp", "pg_trgm"),
+		postgres.WithQueries("create table t (id uuid default uuid_generate_v4(), name text)"),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s  dbname=%s sslmode=disable",
+		container.Host, container.DefaultPort(),
+		"postgres", "password", "postgres",
+	)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+
+	var similarity float64
+
+	require.NoError(t, db.QueryRow("select similarity('gnomock', 'gnomick')").Scan(&similarity))
+	require.Greater(t, similarity, 0.0)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withMissingExtension(t *testing.T) {
+	t.Parallel()
+
+	p := postgres.Preset(
+		postgres.WithExtensions("postgis"),
+	)
+	c, err := gnomock.Start(p)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "extension 'postgis' is not available")
+	require.NoError(t, gnomock.Stop(c))
+}
+
+func TestPreset_withRole(t *testing.T) {
+	t.Parallel()
+
+	p := postgres.Preset(
+		postgres.WithDatabase("mydb"),
+		postgres.WithRole("app", "secret", "connection limit 5"),
+		postgres.WithQueries(
+			"create table t (a int)",
+			"create table secrets (a int)",
+			"grant select, insert on t to app",
+		),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s  dbname=%s sslmode=disable",
+		container.Host, container.DefaultPort(),
+		"app", "secret", "mydb",
+	)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+
+	_, err = db.Exec("insert into t (a) values (1)")
+	require.NoError(t, err)
+
+	_, err = db.Exec("insert into secrets (a) values (1)")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "permission denied")
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withLocale(t *testing.T) {
+	t.Parallel()
+
+	p := postgres.Preset(
+		postgres.WithDatabase("mydb"),
+		postgres.WithTimezone("America/New_York"),
+		postgres.WithLocale("C"),
+		postgres.WithEncoding("LATIN1"),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s  dbname=%s sslmode=disable",
+		container.Host, container.DefaultPort(),
+		"postgres", "password", "mydb",
+	)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+
+	var timezone, collation, encoding string
+
+	require.NoError(t, db.QueryRow("select current_setting('timezone')").Scan(&timezone))
+	require.Equal(t, "America/New_York", timezone)
+
+	row := db.QueryRow(`
+		select datcollate, pg_encoding_to_char(encoding)
+		from pg_database where datname = current_database()
+	`)
+	require.NoError(t, row.Scan(&collation, &encoding))
+	require.Equal(t, "C", collation)
+	require.Equal(t, "LATIN1", encoding)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withConfig(t *testing.T) {
+	t.Parallel()
+
+	p := postgres.Preset(
+		postgres.WithConfig(map[string]string{
+			"max_connections": "42",
+			"log_statement":   "all",
+		}),
+		postgres.WithDump("./testdata/dump.sql"),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s  dbname=%s sslmode=disable",
+		container.Host, container.DefaultPort(),
+		"postgres", "password", "postgres",
+	)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+
+	var maxConnections, logStatement string
+
+	require.NoError(t, db.QueryRow("show max_connections").Scan(&maxConnections))
+	require.Equal(t, "42", maxConnections)
+	require.NoError(t, db.QueryRow("show log_statement").Scan(&logStatement))
+	require.Equal(t, "all", logStatement)
+
+	var count int
+
+	require.NoError(t, db.QueryRow("select count(*) from accounts").Scan(&count))
+	require.Equal(t, 2, count)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withLogicalReplication(t *testing.T) {
+	t.Parallel()
+
+	p := postgres.Preset(
+		postgres.WithLogicalReplication(),
+		postgres.WithQueries(
+			"create table t (a int primary key)",
+			"create publication gnomock for table t",
+			"grant select on t to "+postgres.ReplicationUser,
+		),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s  dbname=%s sslmode=disable",
+		container.Host, container.DefaultPort(),
+		postgres.ReplicationUser, postgres.ReplicationPassword, "postgres",
+	)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+
+	var walLevel string
+
+	require.NoError(t, db.QueryRow("show wal_level").Scan(&walLevel))
+	require.Equal(t, "logical", walLevel)
+
+	var slot string
+
+	row := db.QueryRow("select slot_name from pg_create_logical_replication_slot('gnomock', 'pgoutput')")
+	require.NoError(t, row.Scan(&slot))
+	require.Equal(t, "gnomock", slot)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withDatabases(t *testing.T) {
+	t.Parallel()
+
+	p := postgres.Preset(
+		postgres.WithDatabase("main"),
+		postgres.WithQueries("create table t (a int)"),
+		postgres.WithDatabases(map[string][]string{
+			"tenant1": {"create table t (a int)", "insert into t (a) values (1)"},
+			"tenant2": {"create table t (a int)", "insert into t (a) values (2), (3)"},
+		}),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	for db, expected := range map[string]int{"main": 0, "tenant1": 1, "tenant2": 2} {
+		connStr := fmt.Sprintf(
+			"host=%s port=%d user=%s password=%s  dbname=%s sslmode=disable",
+			container.Host, container.DefaultPort(),
+			"postgres", "password", db,
+		)
+
+		conn, err := sql.Open("postgres", connStr)
+		require.NoError(t, err)
+
+		var count int
+
+		require.NoError(t, conn.QueryRow("select count(*) from t").Scan(&count))
+		require.Equal(t, expected, count)
+		require.NoError(t, conn.Close())
+	}
+}
+
+func TestPreset_withTimescaleDB(t *testing.T) {
+	t.Parallel()
+
+	p := postgres.Preset(
+		postgres.WithDatabase("metrics"),
+		postgres.WithTimescaleDB("2.9.1-pg14"),
+		postgres.WithQueries(
+			"create table conditions (time timestamptz not null, temperature double precision)",
+			"select create_hypertable('conditions', 'time')",
+		),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Equal(t, "docker.io/timescale/timescaledb:2.9.1-pg14", p.Image())
+
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s  dbname=%s sslmode=disable",
+		container.Host, container.DefaultPort(),
+		"postgres", "password", "metrics",
+	)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+
+	var hypertables int
+
+	require.NoError(t, db.QueryRow("select count(*) from timescaledb_information.hypertables").Scan(&hypertables))
+	require.Equal(t, 1, hypertables)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withPostGIS(t *testing.T) {
+	t.Parallel()
+
+	p := postgres.Preset(
+		postgres.WithDatabase("geo"),
+		postgres.WithPostGIS("15-3.3"),
+		postgres.WithQueries(
+			"create table places (name text, location geography(point))",
+			"insert into places values ('a', 'POINT(2.2945 48.8584)'), ('b', 'POINT(2.3522 48.8566)')",
+		),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s  dbname=%s sslmode=disable",
+		container.Host, container.DefaultPort(),
+		"postgres", "password", "geo",
+	)
+
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+
+	var distance float64
+
+	row := db.QueryRow(`
+		select st_distance(a.location, b.location)
+		from places a, places b
+		where a.name = 'a' and b.name = 'b'
+	`)
+	require.NoError(t, row.Scan(&distance))
+	require.InDelta(t, 4200, distance, 100)
+	require.NoError(t, db.Close())
+}
+
 func TestPreset_withDefaults(t *testing.T) {
 	t.Parallel()
 
@@ -130,3 +454,89 @@ func TestPreset_withContainerReuseAndDatabase(t *testing.T) {
 
 	t.Cleanup(func() { require.NoError(t, gnomock.Stop(c1, c2)) })
 }
+
+func TestPreset_withMigrations(t *testing.T) {
+	t.Parallel()
+
+	opts := []postgres.Option{
+		postgres.WithDatabase("accounts_db"),
+		postgres.WithMigrations("file://./testdata/migrations"),
+		postgres.WithQueries("insert into accounts (id, name, email) values (1, 'foo', 'foo@example.com')"),
+	}
+	container, err := gnomock.Start(postgres.Preset(opts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", postgres.ConnString(container, opts...))
+	require.NoError(t, err)
+
+	var version int
+
+	require.NoError(t, db.QueryRow("select version from schema_migrations").Scan(&version))
+	require.Equal(t, 2, version)
+
+	var email string
+
+	require.NoError(t, db.QueryRow("select email from accounts where id = 1").Scan(&email))
+	require.Equal(t, "foo@example.com", email)
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_withPointInTimeRecovery(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	p := postgres.Preset(
+		postgres.WithVersion("13.1"),
+		postgres.WithWALArchive(dir),
+		postgres.WithQueries("create table t (a int)", "insert into t values (1)"),
+	)
+	c, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	connStr := postgres.ConnString(c)
+	db, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+
+	require.NoError(t, postgres.BaseBackup(c, "first"))
+	require.FileExists(t, filepath.Join(dir, "base", "first", "base.tar.gz"))
+	require.Error(t, postgres.BaseBackup(c, "../invalid"))
+
+	_, err = db.Exec("insert into t values (2)")
+	require.NoError(t, err)
+
+	var target string
+
+	require.NoError(t, db.QueryRow("select now()::text").Scan(&target))
+
+	_, err = db.Exec("insert into t values (3)")
+	require.NoError(t, err)
+	require.NoError(t, postgres.SwitchWAL(c))
+	require.NoError(t, db.Close())
+	require.NoError(t, gnomock.Stop(c))
+
+	p = postgres.Preset(
+		postgres.WithVersion("13.1"),
+		postgres.WithPointInTimeRecovery(dir, "first", target),
+	)
+	c, err = gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(c)) }()
+
+	require.NoError(t, err)
+
+	db, err = sql.Open("postgres", postgres.ConnString(c))
+	require.NoError(t, err)
+
+	var count, max int
+
+	require.NoError(t, db.QueryRow("select count(*), max(a) from t").Scan(&count, &max))
+	require.Equal(t, 2, count)
+	require.Equal(t, 2, max)
+
+	_, err = db.Exec("insert into t values (4)")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+}
@@ -0,0 +1,24 @@
+--
+-- PostgreSQL database dump
+--
+
+SET statement_timeout = 0;
+SET client_encoding = 'UTF8';
+SET standard_conforming_strings = on;
+
+CREATE TABLE public.accounts (
+    id integer NOT NULL,
+    name text NOT NULL
+);
+
+COPY public.accounts (id, name) FROM stdin;
+1	foo
+2	bar
+\.
+
+ALTER TABLE ONLY public.accounts
+    ADD CONSTRAINT accounts_pkey PRIMARY KEY (id);
+
+--
+-- PostgreSQL database dump complete
+--
@@ -0,0 +1 @@
+create table accounts (id int not null primary key, name varchar(64));
@@ -0,0 +1 @@
+alter table accounts add email varchar(128);
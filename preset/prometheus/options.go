@@ -0,0 +1,49 @@
+package prometheus
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithConfig sets the contents of "prometheus.yml" configuration file, for
+// example to scrape an exporter running on the host or in another container.
+// By default, the server only scrapes itself.
+func WithConfig(config string) Option {
+	return func(o *P) {
+		o.Config = config
+	}
+}
+
+// WithConfigFile sets a file name to read "prometheus.yml" configuration
+// from. It takes precedence over the configuration provided using WithConfig.
+func WithConfigFile(file string) Option {
+	return func(o *P) {
+		o.ConfigFile = file
+	}
+}
+
+// WithRules loads the provided recording or alerting rule files contents
+// into the server. Every value should be a complete rule file with "groups"
+// at the top level. The configuration is extended with "rule_files" section
+// to load them, so it should not include one. Invalid rules prevent the
+// server from starting. This option can be used multiple times.
+func WithRules(rules ...string) Option {
+	return func(o *P) {
+		o.Rules = append(o.Rules, rules...)
+	}
+}
+
+// WithRulesFile sets a file name to read recording or alerting rules from.
+// Rules from files are loaded together with the rules provided using
+// WithRules. This option can be used multiple times.
+func WithRulesFile(file string) Option {
+	return func(o *P) {
+		o.RulesFiles = append(o.RulesFiles, file)
+	}
+}
@@ -0,0 +1,180 @@
+// Package prometheus includes Prometheus implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured Prometheus container to use in tests.
+//
+// The default port serves Prometheus HTTP API, for example "/api/v1/query",
+// and can be used to test exporters scraped by the server, or recording and
+// alerting rules loaded into it.
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/failinit"
+	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/shell"
+)
+
+const (
+	defaultVersion = "v2.43.0"
+	defaultPort    = 9090
+
+	configFile = "/etc/prometheus/prometheus.yml"
+	rulesDir   = "/etc/prometheus/rules"
+)
+
+// defaultConfig is used when rules are provided without a configuration. It
+// makes the server scrape itself, similar to the configuration of the image.
+const defaultConfig = `global:
+  scrape_interval: 15s
+  evaluation_interval: 15s
+scrape_configs:
+  - job_name: prometheus
+    static_configs:
+      - targets: ["localhost:9090"]`
+
+// entrypoint starts the server as usual once the configuration is written.
+const entrypoint = `exec /bin/prometheus --config.file=` + configFile + ` \
+	--storage.tsdb.path=/prometheus \
+	--web.console.libraries=/usr/share/prometheus/console_libraries \
+	--web.console.templates=/usr/share/prometheus/consoles`
+
+func init() {
+	registry.Register("prometheus", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Prometheus preset. This preset includes a
+// Prometheus specific healthcheck function and default Prometheus image and
+// port, and allows to optionally configure scraping and rules.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Prometheus.
+type P struct {
+	Version    string   `json:"version"`
+	Config     string   `json:"config"`
+	ConfigFile string   `json:"config_file"`
+	Rules      []string `json:"rules"`
+	RulesFiles []string `json:"rules_files"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/prom/prometheus:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+	}
+
+	if p.Config == "" && p.ConfigFile == "" && len(p.Rules) == 0 && len(p.RulesFiles) == 0 {
+		return opts
+	}
+
+	setup, err := p.configSetup()
+	if err != nil {
+		return []gnomock.Option{gnomock.WithInit(failinit.Func(err))}
+	}
+
+	return append(opts, gnomock.WithEntrypoint("/bin/sh", "-c", setup+entrypoint))
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// configSetup returns a script that writes the configuration and the rules
+// into the container. When rules are provided, the configuration is extended
+// to load them.
+func (p *P) configSetup() (string, error) {
+	config := p.Config
+
+	if p.ConfigFile != "" {
+		bs, err := os.ReadFile(p.ConfigFile) // nolint:gosec
+		if err != nil {
+			return "", fmt.Errorf("can't read config file '%s': %w", p.ConfigFile, err)
+		}
+
+		config = string(bs)
+	}
+
+	if config == "" {
+		config = defaultConfig
+	}
+
+	rules := make([]string, 0, len(p.RulesFiles)+len(p.Rules))
+
+	for _, f := range p.RulesFiles {
+		bs, err := os.ReadFile(f) // nolint:gosec
+		if err != nil {
+			return "", fmt.Errorf("can't read rules file '%s': %w", f, err)
+		}
+
+		rules = append(rules, string(bs))
+	}
+
+	rules = append(rules, p.Rules...)
+
+	var setup strings.Builder
+
+	if len(rules) > 0 {
+		config = strings.TrimRight(config, "\n") + "\nrule_files:\n  - " + rulesDir + "/*.yml"
+
+		setup.WriteString("mkdir -p " + rulesDir + "\n")
+
+		for i, r := range rules {
+			fmt.Fprintf(&setup, "printf '%%s\\n' %s > %s/rules_%d.yml\n", shell.Quote(r), rulesDir, i+1)
+		}
+	}
+
+	fmt.Fprintf(&setup, "printf '%%s\\n' %s > %s\n", shell.Quote(config), configFile)
+
+	return setup.String(), nil
+}
+
+// healthcheck makes sure that the server is ready to serve traffic, which
+// happens after the configuration and the rules are loaded.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	addr := fmt.Sprintf("http://%s/-/ready", c.DefaultAddress())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSetup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("config only", func(t *testing.T) {
+		p := &P{Config: "global:\n  scrape_interval: 1s\n"}
+
+		setup, err := p.configSetup()
+		require.NoError(t, err)
+		require.Equal(t, "printf '%s\\n' 'global:\n  scrape_interval: 1s\n' > /etc/prometheus/prometheus.yml\n", setup)
+	})
+
+	t.Run("rules with default config", func(t *testing.T) {
+		p := &P{Rules: []string{"groups: []"}}
+
+		setup, err := p.configSetup()
+		require.NoError(t, err)
+		require.Contains(t, setup, "mkdir -p /etc/prometheus/rules\n")
+		require.Contains(t, setup, "printf '%s\\n' 'groups: []' > /etc/prometheus/rules/rules_1.yml\n")
+		require.Contains(t, setup, "job_name: prometheus")
+		require.Contains(t, setup, "rule_files:\n  - /etc/prometheus/rules/*.yml' > /etc/prometheus/prometheus.yml\n")
+	})
+
+	t.Run("quotes", func(t *testing.T) {
+		p := &P{Config: "a: 'b'"}
+
+		setup, err := p.configSetup()
+		require.NoError(t, err)
+		require.Contains(t, setup, `'a: '\''b'\'''`)
+	})
+
+	t.Run("missing config file", func(t *testing.T) {
+		p := &P{ConfigFile: "./testdata/missing.yml"}
+
+		_, err := p.configSetup()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "can't read config file")
+	})
+}
@@ -0,0 +1,107 @@
+package prometheus_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+const alertingRules = `groups:
+  - name: alerting
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+        for: 1m`
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"v2.37.6", "v2.43.0"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := prometheus.Preset(
+			prometheus.WithVersion(version),
+			prometheus.WithConfigFile("./testdata/prometheus.yml"),
+			prometheus.WithRulesFile("./testdata/rules.yml"),
+			prometheus.WithRules(alertingRules),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		var result struct {
+			Data struct {
+				Groups []struct {
+					Name string `json:"name"`
+				} `json:"groups"`
+			} `json:"data"`
+		}
+
+		get(t, container, "/api/v1/rules", &result)
+
+		names := make([]string, 0, len(result.Data.Groups))
+		for _, g := range result.Data.Groups {
+			names = append(names, g.Name)
+		}
+
+		require.ElementsMatch(t, []string{"recording", "alerting"}, names)
+	}
+}
+
+func TestPreset_defaultConfig(t *testing.T) {
+	t.Parallel()
+
+	p := prometheus.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	var result struct {
+		Data struct {
+			ActiveTargets []struct {
+				ScrapePool string `json:"scrapePool"`
+			} `json:"activeTargets"`
+		} `json:"data"`
+	}
+
+	get(t, container, "/api/v1/targets", &result)
+	require.Len(t, result.Data.ActiveTargets, 1)
+	require.Equal(t, "prometheus", result.Data.ActiveTargets[0].ScrapePool)
+}
+
+func TestPreset_wrongRulesFile(t *testing.T) {
+	t.Parallel()
+
+	p := prometheus.Preset(prometheus.WithRulesFile("./testdata/missing.yml"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't init container: can't read rules file")
+}
+
+func get(t *testing.T, c *gnomock.Container, path string, v interface{}) {
+	t.Helper()
+
+	resp, err := http.Get(fmt.Sprintf("http://%s%s", c.DefaultAddress(), path)) // nolint:gosec,noctx
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
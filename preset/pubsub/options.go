@@ -0,0 +1,42 @@
+package pubsub
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets the version of Google Cloud SDK image, for example
+// "424.0.0". The emulators variant of the image is used.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithProjectID sets the project used by the emulator. Clients must use the
+// same project. If not used, DefaultProjectID is used.
+func WithProjectID(projectID string) Option {
+	return func(o *P) {
+		o.ProjectID = projectID
+	}
+}
+
+// WithTopics creates topics with the provided names. This option can be used
+// multiple times.
+func WithTopics(topics ...string) Option {
+	return func(o *P) {
+		o.Topics = append(o.Topics, topics...)
+	}
+}
+
+// WithSubscription creates a pull subscription with the provided name to the
+// provided topic. The topic is created if it doesn't exist. This option can
+// be used multiple times.
+func WithSubscription(name, topic string) Option {
+	return func(o *P) {
+		if o.Subscriptions == nil {
+			o.Subscriptions = make(map[string]string)
+		}
+
+		o.Subscriptions[name] = topic
+	}
+}
@@ -0,0 +1,197 @@
+// Package pubsub includes Google Cloud Pub/Sub emulator implementation of
+// Gnomock Preset interface. This Preset can be passed to gnomock.Start()
+// function to create a configured Pub/Sub emulator container to use in tests.
+//
+// Tests that create their own clients can point them at the emulator using
+// EmulatorHostEnv, or by passing the default address of the container as an
+// endpoint explicitly.
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// EmulatorHostEnv is checked by pubsub.NewClient before it dials Google
+// Cloud: when the variable holds a host:port address, such as
+// container.DefaultAddress(), the client uses it over an insecure
+// connection and skips authentication.
+const EmulatorHostEnv = "PUBSUB_EMULATOR_HOST"
+
+// DefaultProjectID is the project used by the emulator unless a different
+// project is configured using WithProjectID.
+const DefaultProjectID = "gnomock"
+
+const (
+	defaultVersion   = "424.0.0"
+	defaultPort      = 8085
+	healthcheckTopic = "gnomock-healthcheck"
+)
+
+func init() {
+	registry.Register("pubsub", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Pub/Sub preset. This preset includes a Pub/Sub
+// specific healthcheck function and default Pub/Sub emulator image and port,
+// and allows to optionally create topics and subscriptions.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Pub/Sub emulator.
+type P struct {
+	Version       string            `json:"version"`
+	ProjectID     string            `json:"project_id"`
+	Topics        []string          `json:"topics"`
+	Subscriptions map[string]string `json:"subscriptions"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("gcr.io/google.com/cloudsdktool/cloud-sdk:%s-emulators", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithCommand(
+			"gcloud", "beta", "emulators", "pubsub", "start",
+			"--project="+p.ProjectID,
+			fmt.Sprintf("--host-port=0.0.0.0:%d", defaultPort),
+		),
+	}
+
+	if len(p.Topics) > 0 || len(p.Subscriptions) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.ProjectID == "" {
+		p.ProjectID = DefaultProjectID
+	}
+}
+
+// healthcheck makes sure that the emulator serves gRPC requests.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) (err error) {
+	client, err := p.client(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		closeErr := client.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	if _, err := client.Topic(healthcheckTopic).Exists(ctx); err != nil {
+		return fmt.Errorf("can't get topic: %w", err)
+	}
+
+	return nil
+}
+
+// initf creates the configured topics, and then the configured
+// subscriptions in sorted order. Topics of the subscriptions are created if
+// they don't exist.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) (err error) {
+	client, err := p.client(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		closeErr := client.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	for _, topic := range p.Topics {
+		if err := createTopic(ctx, client, topic); err != nil {
+			return err
+		}
+	}
+
+	subscriptions := make([]string, 0, len(p.Subscriptions))
+	for subscription := range p.Subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	sort.Strings(subscriptions)
+
+	for _, subscription := range subscriptions {
+		topic := p.Subscriptions[subscription]
+
+		if err := createTopic(ctx, client, topic); err != nil {
+			return err
+		}
+
+		cfg := pubsub.SubscriptionConfig{Topic: client.Topic(topic)}
+
+		_, err := client.CreateSubscription(ctx, subscription, cfg)
+		if err != nil && status.Code(err) != codes.AlreadyExists {
+			return fmt.Errorf("can't create subscription '%s': %w", subscription, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *P) client(ctx context.Context, c *gnomock.Container) (*pubsub.Client, error) {
+	client, err := pubsub.NewClient(
+		ctx, p.ProjectID,
+		option.WithEndpoint(c.DefaultAddress()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't create pubsub client: %w", err)
+	}
+
+	return client, nil
+}
+
+// createTopic creates a topic with the provided name, unless it already
+// exists.
+func createTopic(ctx context.Context, client *pubsub.Client, topic string) error {
+	_, err := client.CreateTopic(ctx, topic)
+	if err != nil && status.Code(err) != codes.AlreadyExists {
+		return fmt.Errorf("can't create topic '%s': %w", topic, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package pubsub_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gpubsub "cloud.google.com/go/pubsub"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/pubsub"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"423.0.0", "424.0.0"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := pubsub.Preset(
+			pubsub.WithVersion(version),
+			pubsub.WithProjectID("project"),
+			pubsub.WithTopics("events", "empty"),
+			pubsub.WithSubscription("events-sub", "events"),
+			pubsub.WithSubscription("orders-sub", "orders"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+		defer cancel()
+
+		client := newClient(ctx, t, container, "project")
+
+		defer func() { require.NoError(t, client.Close()) }()
+
+		for _, topic := range []string{"events", "empty", "orders"} {
+			exists, err := client.Topic(topic).Exists(ctx)
+			require.NoError(t, err)
+			require.True(t, exists, topic)
+		}
+
+		exists, err := client.Subscription("orders-sub").Exists(ctx)
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		_, err = client.Topic("events").Publish(ctx, &gpubsub.Message{Data: []byte("foo")}).Get(ctx)
+		require.NoError(t, err)
+
+		receiveCtx, stop := context.WithCancel(ctx)
+		defer stop()
+
+		var data []byte
+
+		err = client.Subscription("events-sub").Receive(receiveCtx, func(_ context.Context, m *gpubsub.Message) {
+			data = m.Data
+
+			m.Ack()
+			stop()
+		})
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(data))
+	}
+}
+
+func TestPreset_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	p := pubsub.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	client := newClient(ctx, t, container, pubsub.DefaultProjectID)
+
+	defer func() { require.NoError(t, client.Close()) }()
+
+	_, err = client.CreateTopic(ctx, "created")
+	require.NoError(t, err)
+}
+
+func newClient(ctx context.Context, t *testing.T, c *gnomock.Container, projectID string) *gpubsub.Client {
+	t.Helper()
+
+	client, err := gpubsub.NewClient(
+		ctx, projectID,
+		option.WithEndpoint(c.DefaultAddress()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	require.NoError(t, err)
+
+	return client
+}
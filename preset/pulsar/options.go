@@ -0,0 +1,41 @@
+package pulsar
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithTenants creates tenants with the provided names, allowed to use the
+// standalone cluster. Tenants of the namespaces and the topics created using
+// other options are created automatically. This option can be used multiple
+// times.
+func WithTenants(tenants ...string) Option {
+	return func(o *P) {
+		o.Tenants = append(o.Tenants, tenants...)
+	}
+}
+
+// WithNamespaces creates namespaces in "tenant/namespace" format, together
+// with their tenants. This option can be used multiple times.
+func WithNamespaces(namespaces ...string) Option {
+	return func(o *P) {
+		o.Namespaces = append(o.Namespaces, namespaces...)
+	}
+}
+
+// WithTopics creates non-partitioned persistent topics, such as
+// "persistent://tenant/namespace/topic" or "tenant/namespace/topic", together
+// with their tenants and namespaces. Topics without tenant and namespace, such
+// as "topic", are created in "public/default" namespace. This option can be
+// used multiple times.
+func WithTopics(topics ...string) Option {
+	return func(o *P) {
+		o.Topics = append(o.Topics, topics...)
+	}
+}
@@ -0,0 +1,275 @@
+// Package pulsar includes Apache Pulsar implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured Pulsar container to use in tests.
+//
+// The container runs Pulsar in standalone mode, without functions worker and
+// stream storage. The default port accepts binary protocol client
+// connections, and AdminPort serves admin REST API, for example
+// "/admin/v2/persistent/public/default".
+package pulsar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// AdminPort is a name of the port exposed by Pulsar containers in addition
+// to the default broker port.
+const AdminPort = "admin"
+
+const (
+	defaultVersion   = "2.11.0"
+	defaultPort      = 6650
+	adminPort        = 8080
+	clusterName      = "standalone"
+	defaultNamespace = "public/default"
+	persistentPrefix = "persistent://"
+)
+
+func init() {
+	registry.Register("pulsar", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Pulsar preset. This preset includes a Pulsar
+// specific healthcheck function and default Pulsar image and ports, and
+// allows to optionally create tenants, namespaces and topics.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Pulsar.
+type P struct {
+	Version    string   `json:"version"`
+	Tenants    []string `json:"tenants"`
+	Namespaces []string `json:"namespaces"`
+	Topics     []string `json:"topics"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/apachepulsar/pulsar:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[AdminPort] = gnomock.Port{Protocol: "tcp", Port: adminPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithCommand("bin/pulsar", "standalone", "--no-functions-worker", "--no-stream-storage"),
+		gnomock.WithEnv("PULSAR_MEM=-Xms256m -Xmx256m -XX:MaxDirectMemorySize=256m"),
+	}
+
+	if len(p.Tenants) > 0 || len(p.Namespaces) > 0 || len(p.Topics) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// healthcheck makes sure that the broker can produce and consume messages,
+// and that the default namespace is created.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	if _, err := request(ctx, c, http.MethodGet, "/admin/v2/brokers/health", nil); err != nil {
+		return err
+	}
+
+	body, err := request(ctx, c, http.MethodGet, "/admin/v2/namespaces/public", nil)
+	if err != nil {
+		return err
+	}
+
+	var namespaces []string
+
+	if err := json.Unmarshal(body, &namespaces); err != nil {
+		return fmt.Errorf("can't decode namespaces: %w", err)
+	}
+
+	for _, ns := range namespaces {
+		if ns == defaultNamespace {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("namespace '%s' not found", defaultNamespace)
+}
+
+// initf creates the configured tenants, namespaces and topics, together with
+// the tenants and the namespaces they belong to.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	tenants := append([]string{}, p.Tenants...)
+	namespaces := []string{}
+
+	for _, ns := range p.Namespaces {
+		tenant, _, err := splitNamespace(ns)
+		if err != nil {
+			return err
+		}
+
+		tenants = append(tenants, tenant)
+		namespaces = append(namespaces, ns)
+	}
+
+	topics := make([]string, 0, len(p.Topics))
+
+	for _, topic := range p.Topics {
+		ns, name, err := splitTopic(topic)
+		if err != nil {
+			return err
+		}
+
+		tenant, _, _ := splitNamespace(ns)
+
+		tenants = append(tenants, tenant)
+		namespaces = append(namespaces, ns)
+		topics = append(topics, ns+"/"+name)
+	}
+
+	body, err := json.Marshal(map[string][]string{"allowedClusters": {clusterName}})
+	if err != nil {
+		return err
+	}
+
+	for _, tenant := range tenants {
+		if err := create(ctx, c, "/admin/v2/tenants/"+tenant, body); err != nil {
+			return fmt.Errorf("can't create tenant '%s': %w", tenant, err)
+		}
+	}
+
+	for _, ns := range namespaces {
+		if err := create(ctx, c, "/admin/v2/namespaces/"+ns, nil); err != nil {
+			return fmt.Errorf("can't create namespace '%s': %w", ns, err)
+		}
+	}
+
+	for _, topic := range topics {
+		if err := create(ctx, c, "/admin/v2/persistent/"+topic, nil); err != nil {
+			return fmt.Errorf("can't create topic '%s': %w", topic, err)
+		}
+	}
+
+	return nil
+}
+
+// splitNamespace returns tenant and namespace names of the provided
+// "tenant/namespace" string.
+func splitNamespace(ns string) (string, string, error) {
+	parts := strings.Split(ns, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid namespace '%s': expected 'tenant/namespace'", ns)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// splitTopic returns "tenant/namespace" and topic name of the provided
+// persistent topic, such as "persistent://tenant/namespace/topic" or
+// "tenant/namespace/topic". Topics without a namespace, such as "topic", are
+// placed in "public/default" namespace.
+func splitTopic(topic string) (string, string, error) {
+	if strings.Contains(topic, "://") && !strings.HasPrefix(topic, persistentPrefix) {
+		return "", "", fmt.Errorf("invalid topic '%s': only persistent topics are supported", topic)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(topic, persistentPrefix), "/")
+
+	for _, part := range parts {
+		if part == "" {
+			return "", "", fmt.Errorf("invalid topic '%s'", topic)
+		}
+	}
+
+	if len(parts) == 1 && !strings.HasPrefix(topic, persistentPrefix) {
+		return defaultNamespace, parts[0], nil
+	}
+
+	if len(parts) != 3 {
+		return "", "", fmt.Errorf("invalid topic '%s': expected 'tenant/namespace/topic'", topic)
+	}
+
+	return parts[0] + "/" + parts[1], parts[2], nil
+}
+
+// create sends a request to create a resource at the provided path, unless it
+// already exists.
+func create(ctx context.Context, c *gnomock.Container, path string, body []byte) error {
+	_, err := request(ctx, c, http.MethodPut, path, body)
+	if err != nil && !errors.Is(err, errConflict) {
+		return err
+	}
+
+	return nil
+}
+
+var errConflict = errors.New("already exists")
+
+// request sends a request to Pulsar admin API, and returns the response body.
+func request(ctx context.Context, c *gnomock.Container, method, path string, body []byte) ([]byte, error) {
+	addr := fmt.Sprintf("http://%s%s", c.Address(AdminPort), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		return nil, errConflict
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr struct {
+			Reason string `json:"reason"`
+		}
+
+		if err := json.Unmarshal(bs, &apiErr); err == nil && apiErr.Reason != "" {
+			return nil, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, apiErr.Reason)
+		}
+
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return bs, nil
+}
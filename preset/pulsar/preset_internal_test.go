@@ -0,0 +1,50 @@
+package pulsar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitTopic(t *testing.T) {
+	t.Parallel()
+
+	valid := map[string][2]string{
+		"persistent://shop/orders/created": {"shop/orders", "created"},
+		"shop/orders/created":              {"shop/orders", "created"},
+		"created":                          {"public/default", "created"},
+	}
+
+	for topic, expected := range valid {
+		ns, name, err := splitTopic(topic)
+		require.NoError(t, err)
+		require.Equal(t, expected, [2]string{ns, name})
+	}
+
+	invalid := []string{
+		"non-persistent://shop/orders/created",
+		"shop/created",
+		"shop//created",
+		"persistent://created",
+		"",
+	}
+
+	for _, topic := range invalid {
+		_, _, err := splitTopic(topic)
+		require.Error(t, err, topic)
+	}
+}
+
+func TestSplitNamespace(t *testing.T) {
+	t.Parallel()
+
+	tenant, ns, err := splitNamespace("shop/orders")
+	require.NoError(t, err)
+	require.Equal(t, "shop", tenant)
+	require.Equal(t, "orders", ns)
+
+	for _, invalid := range []string{"shop", "shop/", "/orders", "shop/orders/created"} {
+		_, _, err := splitNamespace(invalid)
+		require.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,73 @@
+package pulsar_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/pulsar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"2.10.3", "2.11.0"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := pulsar.Preset(
+			pulsar.WithVersion(version),
+			pulsar.WithTenants("empty"),
+			pulsar.WithNamespaces("shop/customers"),
+			pulsar.WithTopics("persistent://shop/orders/created", "shop/orders/deleted", "events"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		require.Subset(t, list(t, container, "/admin/v2/tenants"), []string{"empty", "shop", "public"})
+		require.ElementsMatch(t, []string{"shop/customers", "shop/orders"}, list(t, container, "/admin/v2/namespaces/shop"))
+		require.ElementsMatch(t, []string{
+			"persistent://shop/orders/created",
+			"persistent://shop/orders/deleted",
+		}, list(t, container, "/admin/v2/persistent/shop/orders"))
+		require.Contains(t, list(t, container, "/admin/v2/persistent/public/default"), "persistent://public/default/events")
+	}
+}
+
+func TestPreset_invalidTopic(t *testing.T) {
+	t.Parallel()
+
+	p := pulsar.Preset(pulsar.WithTopics("non-persistent://public/default/events"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "only persistent topics are supported")
+}
+
+func list(t *testing.T, c *gnomock.Container, path string) []string {
+	t.Helper()
+
+	resp, err := http.Get(fmt.Sprintf("http://%s%s", c.Address(pulsar.AdminPort), path)) // nolint:gosec,noctx
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var items []string
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&items))
+
+	return items
+}
@@ -0,0 +1,42 @@
+package questdb
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithQueries executes the provided SQL queries during initial setup, for
+// example to create tables with a designated timestamp and partitioning.
+// Every query should be a single statement.
+func WithQueries(queries ...string) Option {
+	return func(o *P) {
+		o.Queries = append(o.Queries, queries...)
+	}
+}
+
+// WithQueriesFile sets a file name to read an initial query from. Queries
+// from files are executed before any other queries provided in WithQueries.
+// Every file should include a single statement. This option can be used
+// multiple times, and the files are executed in the same order.
+func WithQueriesFile(file string) Option {
+	return func(o *P) {
+		o.QueriesFiles = append(o.QueriesFiles, file)
+	}
+}
+
+// WithLines sends the provided InfluxDB line protocol lines to the ingestion
+// port after the queries are executed, for example
+// "trades,symbol=BTC price=100.5 1672531200000000000". Tables are created
+// automatically when they don't exist. Initial setup waits until the rows
+// are committed and can be queried.
+func WithLines(lines ...string) Option {
+	return func(o *P) {
+		o.Lines = append(o.Lines, lines...)
+	}
+}
@@ -0,0 +1,300 @@
+// Package questdb includes QuestDB implementation of Gnomock Preset interface.
+// This Preset can be passed to gnomock.Start() function to create a configured
+// QuestDB container to use in tests.
+//
+// QuestDB containers expose three ports: Postgres wire protocol port, which is
+// the default port, InfluxDB line protocol ingestion port, available as
+// ILPPort, and HTTP port serving REST API and web console, available as
+// HTTPPort.
+package questdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+)
+
+// Named ports exposed by QuestDB containers in addition to the default
+// Postgres wire protocol port.
+const (
+	ILPPort  = "ilp"
+	HTTPPort = "http"
+)
+
+// By default, Postgres wire protocol connections should use these
+// credentials.
+const (
+	DefaultUser     = "admin"
+	DefaultPassword = "quest"
+	DefaultDatabase = "qdb"
+)
+
+const (
+	defaultVersion = "7.0.1"
+	defaultPort    = 8812
+	ilpPort        = 9009
+	httpPort       = 9000
+
+	linesPollInterval = time.Millisecond * 250
+)
+
+func init() {
+	registry.Register("questdb", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock QuestDB preset. This preset includes a QuestDB
+// specific healthcheck function and default QuestDB image and ports, and
+// allows to optionally set up initial state.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for QuestDB.
+type P struct {
+	Version      string   `json:"version"`
+	Queries      []string `json:"queries"`
+	QueriesFiles []string `json:"queries_files"`
+	Lines        []string `json:"lines"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/questdb/questdb:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[ILPPort] = gnomock.Port{Protocol: "tcp", Port: ilpPort}
+	namedPorts[HTTPPort] = gnomock.Port{Protocol: "tcp", Port: httpPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithEnv("QDB_TELEMETRY_ENABLED=false"),
+	}
+
+	if len(p.Queries) > 0 || len(p.QueriesFiles) > 0 || len(p.Lines) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// healthcheck makes sure that the server accepts queries over HTTP, and that
+// the ingestion port accepts connections.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	if _, err := query(ctx, c, `select 1`); err != nil {
+		return err
+	}
+
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "tcp", c.Address(ILPPort))
+	if err != nil {
+		return fmt.Errorf("can't connect to ingestion port: %w", err)
+	}
+
+	return conn.Close()
+}
+
+// initf executes queries from files and the rest of the queries, in this
+// order, and then sends the lines to the ingestion port, so that the queries
+// can create tables with a designated timestamp and partitioning. It returns
+// once the rows sent as lines can be queried.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	setup := sqlsetup.Setup{Queries: p.Queries, QueriesFiles: p.QueriesFiles}
+
+	queries, err := setup.AllQueries()
+	if err != nil {
+		return err
+	}
+
+	for i, q := range queries {
+		if _, err := query(ctx, c, q); err != nil {
+			return fmt.Errorf("query %d of %d failed (%s): %w", i+1, len(queries), sqlsetup.Snippet(q), err)
+		}
+	}
+
+	if len(p.Lines) == 0 {
+		return nil
+	}
+
+	expected := lineTables(p.Lines)
+
+	for table, lines := range expected {
+		// tables created by the queries may already include some rows
+		count, err := countRows(ctx, c, table)
+		if err == nil {
+			expected[table] = count + lines
+		}
+	}
+
+	if err := writeLines(ctx, c, p.Lines); err != nil {
+		return err
+	}
+
+	return waitForRows(ctx, c, expected)
+}
+
+// lineTables returns the number of lines sent to every table. The name of
+// the table is the measurement that starts every line.
+func lineTables(lines []string) map[string]int {
+	tables := make(map[string]int)
+
+	for _, line := range lines {
+		table := strings.TrimSpace(line)
+		if i := strings.IndexAny(table, ", "); i >= 0 {
+			table = table[:i]
+		}
+
+		if table != "" {
+			tables[table]++
+		}
+	}
+
+	return tables
+}
+
+// waitForRows returns when every table includes at least the expected
+// number of rows, since the lines are committed by the server asynchronously.
+func waitForRows(ctx context.Context, c *gnomock.Container, expected map[string]int) error {
+	ticker := time.NewTicker(linesPollInterval)
+	defer ticker.Stop()
+
+	for table, rows := range expected {
+		for {
+			count, err := countRows(ctx, c, table)
+			if err == nil && count >= rows {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("rows of table '%s' are not visible (%d of %d): %w", table, count, rows, ctx.Err())
+			case <-ticker.C:
+			}
+		}
+	}
+
+	return nil
+}
+
+func countRows(ctx context.Context, c *gnomock.Container, table string) (int, error) {
+	rows, err := query(ctx, c, `select count() from "`+strings.ReplaceAll(table, `"`, `""`)+`"`)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(rows) != 1 || len(rows[0]) != 1 {
+		return 0, fmt.Errorf("unexpected count result: %v", rows)
+	}
+
+	count, ok := rows[0][0].(float64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected count value: %v", rows[0][0])
+	}
+
+	return int(count), nil
+}
+
+// writeLines sends the provided InfluxDB line protocol lines to the ingestion
+// port. The data is committed by the server asynchronously.
+func writeLines(ctx context.Context, c *gnomock.Container, lines []string) error {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "tcp", c.Address(ILPPort))
+	if err != nil {
+		return fmt.Errorf("can't connect to ingestion port: %w", err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	var buf strings.Builder
+
+	for _, line := range lines {
+		buf.WriteString(strings.TrimRight(line, "\n"))
+		buf.WriteString("\n")
+	}
+
+	if _, err := io.WriteString(conn, buf.String()); err != nil {
+		return fmt.Errorf("can't write lines: %w", err)
+	}
+
+	return nil
+}
+
+// query executes the provided query using REST API, and returns the result
+// rows.
+func query(ctx context.Context, c *gnomock.Container, q string) ([][]interface{}, error) {
+	addr := fmt.Sprintf("http://%s/exec?%s", c.Address(HTTPPort), url.Values{"query": []string{q}}.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var result struct {
+		Dataset [][]interface{} `json:"dataset"`
+		Error   string          `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("can't decode query result (status %d): %w", resp.StatusCode, err)
+	}
+
+	if result.Error != "" {
+		return nil, errors.New(result.Error)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return result.Dataset, nil
+}
+
+// ConnString returns a Postgres wire protocol connection string that can be
+// used to connect to the provided container using default credentials.
+func ConnString(c *gnomock.Container) string {
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		c.Host, c.Port(gnomock.DefaultPort), DefaultUser, DefaultPassword, DefaultDatabase,
+	)
+}
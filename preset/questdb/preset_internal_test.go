@@ -0,0 +1,19 @@
+package questdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineTables(t *testing.T) {
+	t.Parallel()
+
+	tables := lineTables([]string{
+		"trades,symbol=BTC price=100.5 1672531200000000000",
+		"trades,symbol=ETH price=10.5 1672531200000000000\n",
+		"quotes bid=1.5,ask=2.5",
+		"",
+	})
+	require.Equal(t, map[string]int{"trades": 2, "quotes": 1}, tables)
+}
@@ -0,0 +1,65 @@
+package questdb_test
+
+import (
+	"database/sql"
+	"net"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/questdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"6.7", "7.0.1"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := questdb.Preset(
+			questdb.WithVersion(version),
+			questdb.WithQueriesFile("./testdata/trades.sql"),
+			questdb.WithQueries("insert into trades values ('ETH', 10.5, '2023-01-01T00:00:00.000000Z')"),
+			questdb.WithLines(
+				"trades,symbol=BTC price=100.5 1672531200000000000",
+				"trades,symbol=BTC price=101.5 1672531201000000000",
+			),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		db, err := sql.Open("postgres", questdb.ConnString(container))
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, db.Close()) }()
+
+		var count int
+
+		require.NoError(t, db.QueryRow("select count() from trades").Scan(&count))
+		require.Equal(t, 3, count)
+
+		conn, err := net.Dial("tcp", container.Address(questdb.ILPPort))
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	}
+}
+
+func TestPreset_wrongQuery(t *testing.T) {
+	t.Parallel()
+
+	p := questdb.Preset(questdb.WithQueries("select * from missing"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "query 1 of 1 failed")
+}
@@ -0,0 +1 @@
+create table trades (symbol symbol, price double, ts timestamp) timestamp(ts) partition by day
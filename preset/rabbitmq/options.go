@@ -38,3 +38,48 @@ func WithMessagesFile(file string) Option {
 		p.MessagesFiles = append(p.MessagesFiles, file)
 	}
 }
+
+// WithTopology declares exchanges, queues and bindings between them during
+// initial setup, in this order. Messages provided using WithMessages can be
+// sent into the declared queues. This option can be used multiple times.
+func WithTopology(t Topology) Option {
+	return func(p *P) {
+		p.Topology.Exchanges = append(p.Topology.Exchanges, t.Exchanges...)
+		p.Topology.Queues = append(p.Topology.Queues, t.Queues...)
+		p.Topology.Bindings = append(p.Topology.Bindings, t.Bindings...)
+	}
+}
+
+// WithManagement enables RabbitMQ management plugin by using the management
+// variant of the selected version, for example "3.8.9-management". Management
+// API is available at `container.Address(rabbitmq.ManagementPort)`, and
+// accepts the same credentials as the broker. The container is ready only
+// when the API serves requests.
+func WithManagement() Option {
+	return func(p *P) {
+		p.Management = true
+	}
+}
+
+// WithVhost creates virtual hosts with the provided names. Management plugin
+// is enabled automatically, since it is used to create them.
+func WithVhost(names ...string) Option {
+	return func(p *P) {
+		p.Vhosts = append(p.Vhosts, names...)
+	}
+}
+
+// WithAccount creates a user with the provided tags and permissions, which
+// can be used by the application instead of the superuser. Permissions may
+// refer to the virtual hosts created using WithVhost. Management plugin is
+// enabled automatically, since it is used to create the users.
+func WithAccount(user, password string, tags []string, permissions ...Permission) Option {
+	return func(p *P) {
+		p.Accounts = append(p.Accounts, Account{
+			Name:        user,
+			Password:    password,
+			Tags:        tags,
+			Permissions: permissions,
+		})
+	}
+}
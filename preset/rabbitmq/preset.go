@@ -68,6 +68,10 @@ type P struct {
 	Version       string    `json:"version"`
 	Messages      []Message `json:"messages"`
 	MessagesFiles []string  `json:"messages_files"`
+	Topology      Topology  `json:"topology"`
+	Management    bool      `json:"management"`
+	Vhosts        []string  `json:"vhosts"`
+	Accounts      []Account `json:"accounts"`
 }
 
 // Image returns an image that should be pulled to create this container.
@@ -102,7 +106,7 @@ func (p *P) Options() []gnomock.Option {
 		)
 	}
 
-	if len(p.Messages)+len(p.MessagesFiles) > 0 {
+	if len(p.Messages)+len(p.MessagesFiles)+len(p.Vhosts)+len(p.Accounts) > 0 || !p.Topology.empty() {
 		opts = append(opts, gnomock.WithInit(p.initf))
 	}
 
@@ -127,13 +131,18 @@ func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
 			return err
 		}
 
-		// any non-err response is valid, it is most likely 401 Unauthorized
+		req.SetBasicAuth(p.User, p.Password)
+
 		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
 			return err
 		}
 
 		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("management api is not ready: status %d", resp.StatusCode)
+		}
 	}
 
 	return nil
@@ -144,6 +153,15 @@ func (p *P) setDefaults() {
 		p.Version = defaultVersion
 	}
 
+	// virtual hosts and users are created using management API
+	if len(p.Vhosts)+len(p.Accounts) > 0 {
+		p.Management = true
+	}
+
+	if p.Management && !strings.Contains(p.Version, "management") {
+		p.Version += "-management"
+	}
+
 	if p.User == "" && p.Password == "" {
 		p.User = defaultUser
 		p.Password = defaultPassword
@@ -151,6 +169,10 @@ func (p *P) setDefaults() {
 }
 
 func (p *P) initf(ctx context.Context, c *gnomock.Container) (err error) {
+	if err := p.provision(ctx, c); err != nil {
+		return err
+	}
+
 	conn, err := p.connect(c)
 	if err != nil {
 		return fmt.Errorf("can't connect to rabbitmq: %w", err)
@@ -163,6 +185,10 @@ func (p *P) initf(ctx context.Context, c *gnomock.Container) (err error) {
 		}
 	}()
 
+	if err := p.declareTopology(conn); err != nil {
+		return err
+	}
+
 	return p.ingestMessages(conn)
 }
 
@@ -176,9 +202,17 @@ func (p *P) ingestMessages(conn *amqp.Connection) error {
 		messagesByQueue[m.Queue] = append(messagesByQueue[m.Queue], m)
 	}
 
+	declared := make(map[string]bool, len(p.Topology.Queues))
+	for _, q := range p.Topology.Queues {
+		declared[q.Name] = true
+	}
+
+	// queues declared as a part of topology may have different settings
 	queues := make([]string, 0, len(messagesByQueue))
 	for q := range messagesByQueue {
-		queues = append(queues, q)
+		if !declared[q] {
+			queues = append(queues, q)
+		}
 	}
 
 	ch, err := conn.Channel()
@@ -232,7 +266,7 @@ func declareQueues(ch *amqp.Channel, qs []string) error {
 }
 
 func (p *P) isManagement() bool {
-	return strings.Contains(p.Version, "management")
+	return p.Management || strings.Contains(p.Version, "management")
 }
 
 // nolint:gosec
@@ -1,11 +1,13 @@
 package rabbitmq_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/orlangure/gnomock"
 	"github.com/orlangure/gnomock/preset/rabbitmq"
@@ -160,6 +162,73 @@ func TestPreset_withManagement(t *testing.T) {
 	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
+func TestPreset_withManagementOption(t *testing.T) {
+	t.Parallel()
+
+	p := rabbitmq.Preset(
+		rabbitmq.WithManagement(),
+		rabbitmq.WithUser("gnomock", "strong-password"),
+		rabbitmq.WithMessages(rabbitmq.Message{Queue: "events", StringBody: "foo"}),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	url := fmt.Sprintf("http://%s/api/queues/%%2F/events", container.Address(rabbitmq.ManagementPort))
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.SetBasicAuth("gnomock", "strong-password")
+
+	// management api statistics are updated periodically
+	var queue struct {
+		Messages int `json:"messages"`
+	}
+
+	require.Eventually(t, func() bool {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return false
+		}
+
+		defer func() { _ = resp.Body.Close() }()
+
+		return json.NewDecoder(resp.Body).Decode(&queue) == nil && queue.Messages == 1
+	}, time.Second*10, time.Millisecond*500)
+}
+
+func TestPreset_withVhostAndAccount(t *testing.T) {
+	t.Parallel()
+
+	p := rabbitmq.Preset(
+		rabbitmq.WithVhost("orders"),
+		rabbitmq.WithAccount("app", "app-password", nil, rabbitmq.Permission{
+			Vhost:     "orders",
+			Configure: ".*",
+			Write:     ".*",
+			Read:      ".*",
+		}),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	conn, err := amqp.Dial(fmt.Sprintf("amqp://app:app-password@%s/orders", container.DefaultAddress()))
+	require.NoError(t, err)
+
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+
+	_, err = ch.QueueDeclare("events", false, false, false, false, nil)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	// the user has no access to the default vhost
+	_, err = amqp.Dial(fmt.Sprintf("amqp://app:app-password@%s/", container.DefaultAddress()))
+	require.Error(t, err)
+}
+
 func TestPreset_withDefaults(t *testing.T) {
 	t.Parallel()
 
@@ -184,6 +253,45 @@ func TestPreset_withDefaults(t *testing.T) {
 	require.NoError(t, ch.Close())
 }
 
+func TestPreset_withTopology(t *testing.T) {
+	t.Parallel()
+
+	p := rabbitmq.Preset(
+		rabbitmq.WithTopology(rabbitmq.Topology{
+			Exchanges: []rabbitmq.Exchange{{Name: "events", Kind: "topic", Durable: true}},
+			Queues: []rabbitmq.Queue{{
+				Name:    "orders",
+				Durable: true,
+				Args:    map[string]interface{}{"x-message-ttl": 60000},
+			}},
+			Bindings: []rabbitmq.Binding{{Exchange: "events", Queue: "orders", RoutingKey: "order.*"}},
+		}),
+		rabbitmq.WithMessages(rabbitmq.Message{Queue: "orders", StringBody: "seed"}),
+	)
+	container, err := gnomock.Start(p)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	conn, err := amqp.Dial(fmt.Sprintf("amqp://guest:guest@%s", container.DefaultAddress()))
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, conn.Close()) }()
+
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+
+	err = ch.Publish("events", "order.created", false, false, amqp.Publishing{Body: []byte("published")})
+	require.NoError(t, err)
+
+	msgs, err := ch.Consume("orders", "", true, false, false, false, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, "seed", string((<-msgs).Body))
+	require.Equal(t, "published", string((<-msgs).Body))
+	require.NoError(t, ch.Close())
+}
+
 func TestPreset_missingFiles(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,105 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/streadway/amqp"
+)
+
+// Topology is a set of exchanges, queues and bindings between them declared
+// during initial setup.
+type Topology struct {
+	Exchanges []Exchange `json:"exchanges"`
+	Queues    []Queue    `json:"queues"`
+	Bindings  []Binding  `json:"bindings"`
+}
+
+// Exchange is a RabbitMQ exchange. Kind is one of "direct", "fanout", "topic"
+// or "headers".
+type Exchange struct {
+	Name       string                 `json:"name"`
+	Kind       string                 `json:"kind"`
+	Durable    bool                   `json:"durable"`
+	AutoDelete bool                   `json:"auto_delete"`
+	Args       map[string]interface{} `json:"args"`
+}
+
+// Queue is a RabbitMQ queue. Args can be used to configure queue features
+// such as "x-message-ttl" or "x-dead-letter-exchange".
+type Queue struct {
+	Name       string                 `json:"name"`
+	Durable    bool                   `json:"durable"`
+	AutoDelete bool                   `json:"auto_delete"`
+	Args       map[string]interface{} `json:"args"`
+}
+
+// Binding routes messages published to an exchange into a queue.
+type Binding struct {
+	Exchange   string                 `json:"exchange"`
+	Queue      string                 `json:"queue"`
+	RoutingKey string                 `json:"routing_key"`
+	Args       map[string]interface{} `json:"args"`
+}
+
+func (t Topology) empty() bool {
+	return len(t.Exchanges)+len(t.Queues)+len(t.Bindings) == 0
+}
+
+func (p *P) declareTopology(conn *amqp.Connection) (err error) {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("can't open channel: %w", err)
+	}
+
+	defer func() {
+		closeErr := ch.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	for _, e := range p.Topology.Exchanges {
+		err := ch.ExchangeDeclare(e.Name, e.Kind, e.Durable, e.AutoDelete, false, false, table(e.Args))
+		if err != nil {
+			return fmt.Errorf("can't declare exchange '%s': %w", e.Name, err)
+		}
+	}
+
+	for _, q := range p.Topology.Queues {
+		_, err := ch.QueueDeclare(q.Name, q.Durable, q.AutoDelete, false, false, table(q.Args))
+		if err != nil {
+			return fmt.Errorf("can't declare queue '%s': %w", q.Name, err)
+		}
+	}
+
+	for _, b := range p.Topology.Bindings {
+		err := ch.QueueBind(b.Queue, b.RoutingKey, b.Exchange, false, table(b.Args))
+		if err != nil {
+			return fmt.Errorf("can't bind queue '%s' to exchange '%s': %w", b.Queue, b.Exchange, err)
+		}
+	}
+
+	return nil
+}
+
+// table converts arguments into amqp table. Whole numbers, which are decoded
+// from JSON as floats, are sent as integers, since RabbitMQ expects integer
+// values for arguments like "x-message-ttl".
+func table(args map[string]interface{}) amqp.Table {
+	if len(args) == 0 {
+		return nil
+	}
+
+	t := make(amqp.Table, len(args))
+
+	for k, v := range args {
+		if f, ok := v.(float64); ok && f == math.Trunc(f) {
+			v = int64(f)
+		}
+
+		t[k] = v
+	}
+
+	return t
+}
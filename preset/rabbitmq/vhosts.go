@@ -0,0 +1,97 @@
+package rabbitmq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+)
+
+// Account is a RabbitMQ user created during initial setup. Tags, such as
+// "administrator" or "monitoring", control access to the management API.
+type Account struct {
+	Name        string       `json:"name"`
+	Password    string       `json:"password"`
+	Tags        []string     `json:"tags"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Permission grants a user access to a virtual host. Configure, Write and Read
+// are regular expressions matching resource names; ".*" allows access to all
+// the resources of the virtual host.
+type Permission struct {
+	Vhost     string `json:"vhost"`
+	Configure string `json:"configure"`
+	Write     string `json:"write"`
+	Read      string `json:"read"`
+}
+
+// provision creates virtual hosts and accounts using management API.
+func (p *P) provision(ctx context.Context, c *gnomock.Container) error {
+	api := "http://" + c.Address(ManagementPort) + "/api"
+
+	for _, vhost := range p.Vhosts {
+		if err := p.apiPut(ctx, api+"/vhosts/"+url.PathEscape(vhost), nil); err != nil {
+			return fmt.Errorf("can't create vhost '%s': %w", vhost, err)
+		}
+	}
+
+	for _, a := range p.Accounts {
+		user := map[string]string{
+			"password": a.Password,
+			"tags":     strings.Join(a.Tags, ","),
+		}
+
+		if err := p.apiPut(ctx, api+"/users/"+url.PathEscape(a.Name), user); err != nil {
+			return fmt.Errorf("can't create user '%s': %w", a.Name, err)
+		}
+
+		for _, perm := range a.Permissions {
+			path := api + "/permissions/" + url.PathEscape(perm.Vhost) + "/" + url.PathEscape(a.Name)
+			body := map[string]string{
+				"configure": perm.Configure,
+				"write":     perm.Write,
+				"read":      perm.Read,
+			}
+
+			if err := p.apiPut(ctx, path, body); err != nil {
+				return fmt.Errorf("can't set user '%s' permissions in vhost '%s': %w", a.Name, perm.Vhost, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *P) apiPut(ctx context.Context, endpoint string, body interface{}) error {
+	bs, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(p.User, p.Password)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return nil
+}
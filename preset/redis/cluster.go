@@ -0,0 +1,123 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	redisclient "github.com/go-redis/redis/v7"
+	"github.com/orlangure/gnomock"
+)
+
+const (
+	// ClusterBasePort is the port of the first node of redis cluster. Other
+	// nodes use the following port numbers.
+	ClusterBasePort = 47000
+
+	minClusterShards = 3
+)
+
+// clusterEntrypoint starts every cluster node in the background, waits for
+// all of them to accept connections, creates the cluster and keeps running as
+// long as the nodes do. Arguments are the number of replicas of every shard,
+// and the ports of the nodes. REDIS_PASSWORD variable is used to protect the
+// nodes, if set.
+const clusterEntrypoint = `replicas=$1
+shift
+auth=""
+if [ -n "$REDIS_PASSWORD" ]; then
+	auth="--requirepass $REDIS_PASSWORD --masterauth $REDIS_PASSWORD"
+fi
+nodes=""
+for port in "$@"; do
+	mkdir -p /data/$port
+	redis-server --port $port --dir /data/$port --cluster-enabled yes \
+		--cluster-config-file nodes.conf --cluster-announce-ip 127.0.0.1 \
+		--appendonly no $auth &
+	nodes="$nodes 127.0.0.1:$port"
+done
+for port in "$@"; do
+	until REDISCLI_AUTH="$REDIS_PASSWORD" redis-cli -p $port ping; do sleep 0.1; done
+done
+echo yes | REDISCLI_AUTH="$REDIS_PASSWORD" redis-cli --cluster create $nodes --cluster-replicas $replicas
+wait`
+
+// ClusterNodePort returns the name of a port of i-th cluster node. Node 0 uses
+// gnomock.DefaultPort.
+func ClusterNodePort(i int) string {
+	if i == 0 {
+		return gnomock.DefaultPort
+	}
+
+	return "node-" + strconv.Itoa(i)
+}
+
+// ClusterAddrs returns the addresses of all the nodes of redis cluster
+// running in the provided container. Use these addresses to configure cluster
+// clients.
+func ClusterAddrs(c *gnomock.Container) []string {
+	var addrs []string
+
+	for i := 0; ; i++ {
+		// cluster nodes are published on the same host ports
+		if _, err := c.Ports.Find("tcp", ClusterBasePort+i); err != nil {
+			break
+		}
+
+		addrs = append(addrs, c.Address(ClusterNodePort(i)))
+	}
+
+	return addrs
+}
+
+func (p *P) clusterNodes() int {
+	return p.ClusterShards * (p.ClusterReplicas + 1)
+}
+
+func (p *P) clusterPorts() gnomock.NamedPorts {
+	ports := make(gnomock.NamedPorts, p.clusterNodes())
+
+	for i := 0; i < p.clusterNodes(); i++ {
+		port := gnomock.TCP(ClusterBasePort + i)
+		port.HostPort = ClusterBasePort + i
+		ports[ClusterNodePort(i)] = port
+	}
+
+	return ports
+}
+
+func (p *P) clusterOptions() []gnomock.Option {
+	args := []string{"-c", clusterEntrypoint, "cluster", strconv.Itoa(p.ClusterReplicas)}
+	for i := 0; i < p.clusterNodes(); i++ {
+		args = append(args, strconv.Itoa(ClusterBasePort+i))
+	}
+
+	return []gnomock.Option{
+		gnomock.WithEnv("REDIS_PASSWORD=" + p.Password),
+		gnomock.WithEntrypoint("/bin/sh", args...),
+	}
+}
+
+func (p *P) clusterHealthcheck(ctx context.Context, c *gnomock.Container) error {
+	client := redisclient.NewClient(&redisclient.Options{
+		Addr:     c.Address(gnomock.DefaultPort),
+		Password: p.Password,
+	})
+	defer func() { _ = client.Close() }()
+
+	info, err := client.ClusterInfo().Result()
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(info, "cluster_state:ok") {
+		return fmt.Errorf("cluster is not ready")
+	}
+
+	if !strings.Contains(info, fmt.Sprintf("cluster_known_nodes:%d", p.clusterNodes())) {
+		return fmt.Errorf("not all cluster nodes are known")
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+
+	redisclient "github.com/go-redis/redis/v7"
+)
+
+// Module is a redis module available in Redis Stack.
+type Module string
+
+// Supported redis modules.
+const (
+	ModuleJSON       Module = "json"
+	ModuleSearch     Module = "search"
+	ModuleBloom      Module = "bloom"
+	ModuleTimeSeries Module = "timeseries"
+)
+
+const defaultStackVersion = "6.2.6-v7"
+
+// moduleProbes are commands of every module that are expected to succeed
+// once the module is loaded.
+var moduleProbes = map[Module][]interface{}{
+	ModuleJSON:       {"JSON.TYPE", "gnomock:probe"},
+	ModuleSearch:     {"FT._LIST"},
+	ModuleBloom:      {"BF.EXISTS", "gnomock:probe", "gnomock"},
+	ModuleTimeSeries: {"TS.QUERYINDEX", "gnomock=probe"},
+}
+
+// validateModules reports requested modules that Redis Stack doesn't
+// include, before the container starts.
+func (p *P) validateModules() error {
+	for _, m := range p.Modules {
+		if _, ok := moduleProbes[m]; !ok {
+			return fmt.Errorf("unknown module '%s'", m)
+		}
+	}
+
+	return nil
+}
+
+// modulesHealthcheck returns an error unless every requested module responds
+// to its commands.
+func (p *P) modulesHealthcheck(client redisclient.UniversalClient) error {
+	for _, m := range p.Modules {
+		err := client.Do(moduleProbes[m]...).Err()
+		if err != nil && !errors.Is(err, redisclient.Nil) {
+			return fmt.Errorf("module '%s' is not ready: %w", m, err)
+		}
+	}
+
+	return nil
+}
@@ -13,9 +13,118 @@ func WithValues(vs map[string]interface{}) Option {
 	}
 }
 
+// WithHashes initializes Redis with the provided hashes. Keys of the map are
+// hash keys, and values are hash fields with their values.
+func WithHashes(hashes map[string]map[string]interface{}) Option {
+	return func(p *P) {
+		p.Hashes = hashes
+	}
+}
+
+// WithLists initializes Redis with the provided lists. Items are pushed to
+// every list in the same order.
+func WithLists(lists map[string][]interface{}) Option {
+	return func(p *P) {
+		p.Lists = lists
+	}
+}
+
+// WithSets initializes Redis with the provided sets.
+func WithSets(sets map[string][]interface{}) Option {
+	return func(p *P) {
+		p.Sets = sets
+	}
+}
+
+// WithSortedSets initializes Redis with the provided sorted sets. Keys of the
+// map are sorted set keys, and values are members with their scores.
+func WithSortedSets(sets map[string]map[string]float64) Option {
+	return func(p *P) {
+		p.SortedSets = sets
+	}
+}
+
+// WithStreams initializes Redis with the provided streams. Every stream entry
+// is a set of field/value pairs; entries are added in the same order, and
+// receive automatically generated IDs.
+func WithStreams(streams map[string][]map[string]interface{}) Option {
+	return func(p *P) {
+		p.Streams = streams
+	}
+}
+
+// WithCluster runs redis cluster with the provided number of shards (master
+// nodes), each with the provided number of replicas, instead of a single redis
+// server. Redis requires at least 3 shards, so smaller values are increased.
+// All nodes run in the same container, and initial values are distributed
+// between them according to their keys.
+//
+// Cluster nodes use constant port numbers starting with ClusterBasePort
+// (47000), which are published on the same host ports, since every node must
+// announce the address the clients should use. Please make sure these ports
+// are available. Use ClusterAddrs to get the addresses of all nodes.
+func WithCluster(shards, replicas int) Option {
+	return func(o *P) {
+		o.ClusterShards = shards
+		o.ClusterReplicas = replicas
+	}
+}
+
+// WithSentinel runs redis master with the provided number of replicas, and
+// the provided number of sentinels monitoring them, instead of a single redis
+// server. If master name is empty, "mymaster" is used. Initial values are set
+// on the master, and are replicated to all the replicas.
+//
+// All servers run in the same container, and use constant port numbers since
+// they must announce the addresses the clients should use: master uses
+// SentinelMasterPort (48000) and replicas use the following ports, while
+// sentinels use ports starting with SentinelBasePort (48100). These ports are
+// published on the same host ports, so please make sure they are available.
+// Use SentinelAddrs to get the addresses of all sentinels. This option can't
+// be combined with WithCluster.
+func WithSentinel(masterName string, replicas, sentinels int) Option {
+	return func(o *P) {
+		o.SentinelMaster = masterName
+		o.SentinelReplicas = replicas
+		o.Sentinels = sentinels
+	}
+}
+
+// WithTLS configures redis server to only accept TLS connections, using the
+// provided PEM encoded certificate and private key. Clients are not required
+// to present certificates. Use TLSConfig to configure clients to trust this
+// certificate. TLS is not supported together with cluster or sentinel
+// topologies, and such containers fail to start.
+func WithTLS(certPEM, keyPEM []byte) Option {
+	return func(o *P) {
+		o.TLSCert = string(certPEM)
+		o.TLSKey = string(keyPEM)
+	}
+}
+
+// WithModules uses Redis Stack image, which includes the provided modules,
+// instead of the official Redis image. The container is considered ready only
+// when all the modules respond to their commands. When this option is used,
+// WithVersion sets the version of redis-stack-server image; by default,
+// version 6.2.6-v7 is used. Modules are not supported together with TLS,
+// cluster or sentinel topologies, and such containers fail to start.
+func WithModules(modules ...Module) Option {
+	return func(o *P) {
+		o.Modules = append(o.Modules, modules...)
+	}
+}
+
 // WithVersion sets image version.
 func WithVersion(version string) Option {
 	return func(o *P) {
 		o.Version = version
 	}
 }
+
+// WithPassword requires clients to authenticate using the provided password
+// (`requirepass` configuration directive).
+func WithPassword(password string) Option {
+	return func(o *P) {
+		o.Password = password
+	}
+}
@@ -5,10 +5,12 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	redisclient "github.com/go-redis/redis/v7"
 	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/failinit"
 	"github.com/orlangure/gnomock/internal/registry"
 )
 
@@ -33,17 +35,47 @@ func Preset(opts ...Option) gnomock.Preset {
 
 // P is a Gnomock Preset implementation for Redis storage.
 type P struct {
-	Values  map[string]interface{} `json:"values"`
-	Version string                 `json:"version"`
+	Values     map[string]interface{}              `json:"values"`
+	Hashes     map[string]map[string]interface{}   `json:"hashes"`
+	Lists      map[string][]interface{}            `json:"lists"`
+	Sets       map[string][]interface{}            `json:"sets"`
+	SortedSets map[string]map[string]float64       `json:"sorted_sets"`
+	Streams    map[string][]map[string]interface{} `json:"streams"`
+	Version    string                              `json:"version"`
+	Password   string                              `json:"password"`
+
+	ClusterShards   int `json:"cluster_shards"`
+	ClusterReplicas int `json:"cluster_replicas"`
+
+	SentinelMaster   string `json:"sentinel_master"`
+	SentinelReplicas int    `json:"sentinel_replicas"`
+	Sentinels        int    `json:"sentinels"`
+
+	TLSCert string `json:"tls_cert"`
+	TLSKey  string `json:"tls_key"`
+
+	Modules []Module `json:"modules"`
 }
 
 // Image returns an image that should be pulled to create this container.
 func (p *P) Image() string {
+	if len(p.Modules) > 0 {
+		return fmt.Sprintf("docker.io/redis/redis-stack-server:%s", p.Version)
+	}
+
 	return fmt.Sprintf("docker.io/library/redis:%s", p.Version)
 }
 
 // Ports returns ports that should be used to access this container.
 func (p *P) Ports() gnomock.NamedPorts {
+	if p.ClusterShards > 0 {
+		return p.clusterPorts()
+	}
+
+	if p.Sentinels > 0 {
+		return p.sentinelPorts()
+	}
+
 	return gnomock.DefaultTCP(6379)
 }
 
@@ -51,41 +83,181 @@ func (p *P) Ports() gnomock.NamedPorts {
 func (p *P) Options() []gnomock.Option {
 	p.setDefaults()
 
+	if err := p.validate(); err != nil {
+		return []gnomock.Option{gnomock.WithInit(failinit.Func(err))}
+	}
+
 	opts := []gnomock.Option{
-		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithHealthCheck(p.healthcheck),
 	}
 
-	if p.Values != nil {
-		initf := func(ctx context.Context, c *gnomock.Container) error {
-			addr := c.Address(gnomock.DefaultPort)
-			client := redisclient.NewClient(&redisclient.Options{Addr: addr})
+	switch {
+	case p.ClusterShards > 0:
+		opts = append(opts, p.clusterOptions()...)
+	case p.Sentinels > 0:
+		opts = append(opts, p.sentinelOptions()...)
+	case p.TLSCert != "":
+		opts = append(opts, p.tlsOptions()...)
+	case len(p.Modules) > 0 && p.Password != "":
+		// redis stack image loads the modules in its own entrypoint, and
+		// accepts additional server arguments in a variable
+		opts = append(opts, gnomock.WithEnv("REDIS_ARGS=--requirepass "+p.Password))
+	case p.Password != "":
+		opts = append(opts, gnomock.WithCommand("redis-server", "--requirepass", p.Password))
+	}
 
-			for k, v := range p.Values {
-				err := client.Set(k, v, 0).Err()
-				if err != nil {
-					return fmt.Errorf("can't set '%s'='%v': %w", k, v, err)
-				}
-			}
+	if p.hasData() {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
 
-			return nil
+// validate reports options that can't be used together, and unknown modules.
+// Cluster and sentinel topologies run their own servers, which support
+// neither TLS nor modules, and modules are only available in Redis Stack
+// image, which is started without TLS.
+func (p *P) validate() error {
+	topology := p.ClusterShards > 0 || p.Sentinels > 0
+
+	switch {
+	case p.ClusterShards > 0 && p.Sentinels > 0:
+		return errors.New("cluster is not supported together with sentinel")
+	case topology && p.TLSCert != "":
+		return errors.New("tls is not supported together with cluster or sentinel")
+	case topology && len(p.Modules) > 0:
+		return errors.New("modules are not supported together with cluster or sentinel")
+	case p.TLSCert != "" && len(p.Modules) > 0:
+		return errors.New("modules are not supported together with tls")
+	default:
+		return p.validateModules()
+	}
+}
+
+func (p *P) hasData() bool {
+	return len(p.Values) > 0 || len(p.Hashes) > 0 || len(p.Lists) > 0 ||
+		len(p.Sets) > 0 || len(p.SortedSets) > 0 || len(p.Streams) > 0
+}
+
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	client, err := p.client(c)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	for k, v := range p.Values {
+		err := client.Set(k, v, 0).Err()
+		if err != nil {
+			return fmt.Errorf("can't set '%s'='%v': %w", k, v, err)
 		}
+	}
 
-		opts = append(opts, gnomock.WithInit(initf))
+	for k, fields := range p.Hashes {
+		if err := client.HSet(k, fields).Err(); err != nil {
+			return fmt.Errorf("can't set hash '%s': %w", k, err)
+		}
 	}
 
-	return opts
+	for k, items := range p.Lists {
+		if err := client.RPush(k, items...).Err(); err != nil {
+			return fmt.Errorf("can't set list '%s': %w", k, err)
+		}
+	}
+
+	for k, members := range p.Sets {
+		if err := client.SAdd(k, members...).Err(); err != nil {
+			return fmt.Errorf("can't set set '%s': %w", k, err)
+		}
+	}
+
+	for k, scores := range p.SortedSets {
+		members := make([]*redisclient.Z, 0, len(scores))
+		for member, score := range scores {
+			members = append(members, &redisclient.Z{Score: score, Member: member})
+		}
+
+		if err := client.ZAdd(k, members...).Err(); err != nil {
+			return fmt.Errorf("can't set sorted set '%s': %w", k, err)
+		}
+	}
+
+	for k, entries := range p.Streams {
+		for _, values := range entries {
+			err := client.XAdd(&redisclient.XAddArgs{Stream: k, Values: values}).Err()
+			if err != nil {
+				return fmt.Errorf("can't add stream '%s' entry: %w", k, err)
+			}
+		}
+	}
+
+	return nil
 }
 
 func (p *P) setDefaults() {
 	if p.Version == "" {
 		p.Version = defaultVersion
+
+		if len(p.Modules) > 0 {
+			p.Version = defaultStackVersion
+		}
+	}
+
+	if p.ClusterShards > 0 && p.ClusterShards < minClusterShards {
+		p.ClusterShards = minClusterShards
+	}
+
+	if p.Sentinels > 0 && p.SentinelMaster == "" {
+		p.SentinelMaster = defaultSentinelMaster
+	}
+}
+
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	if p.ClusterShards > 0 {
+		return p.clusterHealthcheck(ctx, c)
+	}
+
+	if p.Sentinels > 0 {
+		return p.sentinelHealthcheck(ctx, c)
+	}
+
+	client, err := p.client(c)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	_, err = client.Ping().Result()
+	if err != nil {
+		return err
 	}
+
+	return p.modulesHealthcheck(client)
 }
 
-func healthcheck(ctx context.Context, c *gnomock.Container) error {
-	addr := c.Address(gnomock.DefaultPort)
-	client := redisclient.NewClient(&redisclient.Options{Addr: addr})
-	_, err := client.Ping().Result()
+func (p *P) client(c *gnomock.Container) (redisclient.UniversalClient, error) {
+	if p.ClusterShards > 0 {
+		return redisclient.NewClusterClient(&redisclient.ClusterOptions{
+			Addrs:    ClusterAddrs(c),
+			Password: p.Password,
+		}), nil
+	}
+
+	opts := &redisclient.Options{
+		Addr:     c.Address(gnomock.DefaultPort),
+		Password: p.Password,
+	}
+
+	if p.TLSCert != "" {
+		tlsConfig, err := p.tlsConfig(c.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.TLSConfig = tlsConfig
+	}
 
-	return err
+	return redisclient.NewClient(opts), nil
 }
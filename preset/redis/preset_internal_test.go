@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []Option
+		err  string
+	}{
+		{name: "default"},
+		{name: "password", opts: []Option{WithPassword("secret"), WithModules(ModuleJSON)}},
+		{name: "tls", opts: []Option{WithTLS([]byte("cert"), []byte("key")), WithPassword("secret")}},
+		{name: "cluster", opts: []Option{WithCluster(3, 1), WithPassword("secret")}},
+		{
+			name: "cluster with sentinel",
+			opts: []Option{WithCluster(3, 1), WithSentinel("", 1, 3)},
+			err:  "cluster is not supported together with sentinel",
+		},
+		{
+			name: "sentinel with tls",
+			opts: []Option{WithSentinel("", 1, 3), WithTLS([]byte("cert"), []byte("key"))},
+			err:  "tls is not supported together with cluster or sentinel",
+		},
+		{
+			name: "cluster with modules",
+			opts: []Option{WithModules(ModuleSearch), WithCluster(3, 0)},
+			err:  "modules are not supported together with cluster or sentinel",
+		},
+		{
+			name: "tls with modules",
+			opts: []Option{WithTLS([]byte("cert"), []byte("key")), WithModules(ModuleJSON)},
+			err:  "modules are not supported together with tls",
+		},
+		{
+			name: "unknown module",
+			opts: []Option{WithModules(ModuleJSON, "graph")},
+			err:  "unknown module 'graph'",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := Preset(test.opts...).(*P)
+			p.setDefaults()
+
+			err := p.validate()
+			if test.err == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package redis_test
 
 import (
+	"sync/atomic"
 	"testing"
+	"time"
 
 	redisclient "github.com/go-redis/redis/v7"
 	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/tlsutil"
 	"github.com/orlangure/gnomock/preset/redis"
 	"github.com/stretchr/testify/require"
 )
@@ -55,6 +58,234 @@ func testPreset(version string) func(t *testing.T) {
 	}
 }
 
+func TestPreset_withPassword(t *testing.T) {
+	t.Parallel()
+
+	p := redis.Preset(
+		redis.WithPassword("s3cr3t"),
+		redis.WithValues(map[string]interface{}{"a": "foo"}),
+	)
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	addr := container.DefaultAddress()
+
+	client := redisclient.NewClient(&redisclient.Options{Addr: addr})
+	require.Error(t, client.Get("a").Err())
+	require.NoError(t, client.Close())
+
+	client = redisclient.NewClient(&redisclient.Options{Addr: addr, Password: "s3cr3t"})
+
+	var str string
+
+	require.NoError(t, client.Get("a").Scan(&str))
+	require.Equal(t, "foo", str)
+	require.NoError(t, client.Close())
+}
+
+func TestPreset_withComplexTypes(t *testing.T) {
+	t.Parallel()
+
+	p := redis.Preset(
+		redis.WithHashes(map[string]map[string]interface{}{
+			"user:1": {"name": "foo", "age": 42},
+		}),
+		redis.WithLists(map[string][]interface{}{
+			"queue": {"a", "b", "c"},
+		}),
+		redis.WithSets(map[string][]interface{}{
+			"tags": {"x", "y", "x"},
+		}),
+		redis.WithSortedSets(map[string]map[string]float64{
+			"scores": {"alice": 3, "bob": 1, "carol": 2},
+		}),
+		redis.WithStreams(map[string][]map[string]interface{}{
+			"events": {{"type": "created"}, {"type": "updated"}},
+		}),
+	)
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	client := redisclient.NewClient(&redisclient.Options{Addr: container.DefaultAddress()})
+
+	user, err := client.HGetAll("user:1").Result()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"name": "foo", "age": "42"}, user)
+
+	queue, err := client.LRange("queue", 0, -1).Result()
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, queue)
+
+	tags, err := client.SMembers("tags").Result()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"x", "y"}, tags)
+
+	scores, err := client.ZRange("scores", 0, -1).Result()
+	require.NoError(t, err)
+	require.Equal(t, []string{"bob", "carol", "alice"}, scores)
+
+	events, err := client.XRange("events", "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, "updated", events[1].Values["type"])
+
+	require.NoError(t, client.Close())
+}
+
+func TestPreset_withCluster(t *testing.T) {
+	t.Parallel()
+
+	vs := map[string]interface{}{"a": "foo", "b": "bar", "c": "baz"}
+	p := redis.Preset(
+		redis.WithCluster(3, 1),
+		redis.WithPassword("s3cr3t"),
+		redis.WithValues(vs),
+	)
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	addrs := redis.ClusterAddrs(container)
+	require.Len(t, addrs, 6)
+
+	client := redisclient.NewClusterClient(&redisclient.ClusterOptions{
+		Addrs:    addrs,
+		Password: "s3cr3t",
+	})
+
+	for k, v := range vs {
+		actual, err := client.Get(k).Result()
+		require.NoError(t, err)
+		require.Equal(t, v, actual)
+	}
+
+	var masters int32
+
+	require.NoError(t, client.ForEachMaster(func(c *redisclient.Client) error {
+		atomic.AddInt32(&masters, 1)
+
+		return nil
+	}))
+	require.Equal(t, int32(3), masters)
+	require.NoError(t, client.Close())
+}
+
+func TestPreset_withSentinel(t *testing.T) {
+	t.Parallel()
+
+	p := redis.Preset(
+		redis.WithSentinel("gnomock", 2, 3),
+		redis.WithValues(map[string]interface{}{"a": "foo"}),
+	)
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	addrs := redis.SentinelAddrs(container)
+	require.Len(t, addrs, 3)
+
+	client := redisclient.NewFailoverClient(&redisclient.FailoverOptions{
+		MasterName:    "gnomock",
+		SentinelAddrs: addrs,
+	})
+
+	var str string
+
+	require.NoError(t, client.Get("a").Scan(&str))
+	require.Equal(t, "foo", str)
+	require.NoError(t, client.Set("b", "bar", 0).Err())
+	require.NoError(t, client.Close())
+
+	replica := redisclient.NewClient(&redisclient.Options{Addr: container.Address(redis.ReplicaPort(1))})
+
+	require.Eventually(t, func() bool {
+		return replica.Get("b").Val() == "bar"
+	}, time.Second*5, time.Millisecond*100)
+	require.NoError(t, replica.Close())
+}
+
+func TestPreset_withTLS(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM, err := tlsutil.SelfSignedCert()
+	require.NoError(t, err)
+	opts := []redis.Option{
+		redis.WithTLS(certPEM, keyPEM),
+		redis.WithPassword("s3cr3t"),
+		redis.WithValues(map[string]interface{}{"a": "foo"}),
+	}
+	container, err := gnomock.Start(redis.Preset(opts...))
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	tlsConfig, err := redis.TLSConfig(container, opts...)
+	require.NoError(t, err)
+
+	client := redisclient.NewClient(&redisclient.Options{
+		Addr:      container.DefaultAddress(),
+		Password:  "s3cr3t",
+		TLSConfig: tlsConfig,
+	})
+
+	var str string
+
+	require.NoError(t, client.Get("a").Scan(&str))
+	require.Equal(t, "foo", str)
+	require.NoError(t, client.Close())
+
+	// plain text connections are rejected
+	client = redisclient.NewClient(&redisclient.Options{
+		Addr:     container.DefaultAddress(),
+		Password: "s3cr3t",
+	})
+	require.Error(t, client.Ping().Err())
+	require.NoError(t, client.Close())
+}
+
+func TestPreset_withModules(t *testing.T) {
+	t.Parallel()
+
+	p := redis.Preset(
+		redis.WithModules(redis.ModuleJSON, redis.ModuleSearch, redis.ModuleBloom, redis.ModuleTimeSeries),
+		redis.WithPassword("s3cr3t"),
+	)
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	client := redisclient.NewClient(&redisclient.Options{
+		Addr:     container.DefaultAddress(),
+		Password: "s3cr3t",
+	})
+
+	require.NoError(t, client.Do("JSON.SET", "doc", "$", `{"a":{"b":42}}`).Err())
+
+	b, err := client.Do("JSON.GET", "doc", "$.a.b").Text()
+	require.NoError(t, err)
+	require.Equal(t, "[42]", b)
+
+	require.NoError(t, client.Do("BF.ADD", "filter", "foo").Err())
+
+	exists, err := client.Do("BF.EXISTS", "filter", "foo").Int()
+	require.NoError(t, err)
+	require.Equal(t, 1, exists)
+	require.NoError(t, client.Close())
+}
+
 func TestRedis_wrongValue(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,155 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	redisclient "github.com/go-redis/redis/v7"
+	"github.com/orlangure/gnomock"
+)
+
+const (
+	// SentinelMasterPort is the port of redis master server when sentinel
+	// topology is used. Replicas use the following port numbers.
+	SentinelMasterPort = 48000
+
+	// SentinelBasePort is the port of the first sentinel. Other sentinels use
+	// the following port numbers.
+	SentinelBasePort = 48100
+
+	defaultSentinelMaster = "mymaster"
+)
+
+// sentinelEntrypoint starts redis master, its replicas and sentinels in the
+// background, and keeps running as long as they do. Arguments are the number
+// of replicas and master port, followed by sentinel ports. SENTINEL_MASTER and SENTINEL_QUORUM
+// variables configure sentinels, and REDIS_PASSWORD is used to protect all
+// servers, if set.
+const sentinelEntrypoint = `replicas=$1
+master=$2
+shift 2
+auth=""
+if [ -n "$REDIS_PASSWORD" ]; then
+	auth="--requirepass $REDIS_PASSWORD --masterauth $REDIS_PASSWORD"
+fi
+redis-server --port $master --replica-announce-ip 127.0.0.1 $auth &
+i=1
+while [ $i -le $replicas ]; do
+	redis-server --port $((master + i)) --replicaof 127.0.0.1 $master \
+		--replica-announce-ip 127.0.0.1 $auth &
+	i=$((i + 1))
+done
+for port in "$@"; do
+	conf=/data/sentinel-$port.conf
+	echo "port $port" > $conf
+	echo "sentinel announce-ip 127.0.0.1" >> $conf
+	echo "sentinel monitor $SENTINEL_MASTER 127.0.0.1 $master $SENTINEL_QUORUM" >> $conf
+	echo "sentinel down-after-milliseconds $SENTINEL_MASTER 1000" >> $conf
+	echo "sentinel failover-timeout $SENTINEL_MASTER 5000" >> $conf
+	if [ -n "$REDIS_PASSWORD" ]; then
+		echo "sentinel auth-pass $SENTINEL_MASTER $REDIS_PASSWORD" >> $conf
+	fi
+	redis-server $conf --sentinel &
+done
+wait`
+
+// SentinelPort returns the name of a port of i-th sentinel.
+func SentinelPort(i int) string {
+	return "sentinel-" + strconv.Itoa(i)
+}
+
+// ReplicaPort returns the name of a port of i-th replica in sentinel
+// topology. Redis master uses gnomock.DefaultPort.
+func ReplicaPort(i int) string {
+	return "replica-" + strconv.Itoa(i)
+}
+
+// SentinelAddrs returns the addresses of all the sentinels running in the
+// provided container. Use these addresses together with the master name to
+// configure failover clients.
+func SentinelAddrs(c *gnomock.Container) []string {
+	var addrs []string
+
+	for i := 0; ; i++ {
+		// sentinels are published on the same host ports
+		if _, err := c.Ports.Find("tcp", SentinelBasePort+i); err != nil {
+			break
+		}
+
+		addrs = append(addrs, c.Address(SentinelPort(i)))
+	}
+
+	return addrs
+}
+
+func (p *P) sentinelPorts() gnomock.NamedPorts {
+	ports := make(gnomock.NamedPorts, 1+p.SentinelReplicas+p.Sentinels)
+
+	master := gnomock.TCP(SentinelMasterPort)
+	master.HostPort = SentinelMasterPort
+	ports[gnomock.DefaultPort] = master
+
+	for i := 0; i < p.SentinelReplicas; i++ {
+		port := gnomock.TCP(SentinelMasterPort + i + 1)
+		port.HostPort = port.Port
+		ports[ReplicaPort(i)] = port
+	}
+
+	for i := 0; i < p.Sentinels; i++ {
+		port := gnomock.TCP(SentinelBasePort + i)
+		port.HostPort = port.Port
+		ports[SentinelPort(i)] = port
+	}
+
+	return ports
+}
+
+func (p *P) sentinelOptions() []gnomock.Option {
+	args := []string{
+		"-c", sentinelEntrypoint, "sentinel",
+		strconv.Itoa(p.SentinelReplicas), strconv.Itoa(SentinelMasterPort),
+	}
+	for i := 0; i < p.Sentinels; i++ {
+		args = append(args, strconv.Itoa(SentinelBasePort+i))
+	}
+
+	return []gnomock.Option{
+		gnomock.WithEnv("REDIS_PASSWORD=" + p.Password),
+		gnomock.WithEnv("SENTINEL_MASTER=" + p.SentinelMaster),
+		gnomock.WithEnv("SENTINEL_QUORUM=" + strconv.Itoa(p.Sentinels/2+1)),
+		gnomock.WithEntrypoint("/bin/sh", args...),
+	}
+}
+
+func (p *P) sentinelHealthcheck(ctx context.Context, c *gnomock.Container) error {
+	master := redisclient.NewClient(&redisclient.Options{
+		Addr:     c.Address(gnomock.DefaultPort),
+		Password: p.Password,
+	})
+	defer func() { _ = master.Close() }()
+
+	info, err := master.Info("replication").Result()
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(info, fmt.Sprintf("connected_slaves:%d", p.SentinelReplicas)) {
+		return fmt.Errorf("not all replicas are connected")
+	}
+
+	for _, addr := range SentinelAddrs(c) {
+		sentinel := redisclient.NewSentinelClient(&redisclient.Options{Addr: addr})
+
+		_, err := sentinel.GetMasterAddrByName(p.SentinelMaster).Result()
+
+		_ = sentinel.Close()
+
+		if err != nil {
+			return fmt.Errorf("sentinel '%s' is not ready: %w", addr, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"crypto/tls"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/tlsutil"
+)
+
+// tlsEntrypoint writes server certificate and key from REDIS_TLS_CERT and
+// REDIS_TLS_KEY variables, and starts redis server that only accepts TLS
+// connections. The provided arguments are passed to the server.
+const tlsEntrypoint = `mkdir -p /tls
+printf '%s' "$REDIS_TLS_CERT" > /tls/redis.crt
+(umask 077 && printf '%s' "$REDIS_TLS_KEY" > /tls/redis.key)
+exec redis-server --port 0 --tls-port 6379 \
+	--tls-cert-file /tls/redis.crt --tls-key-file /tls/redis.key \
+	--tls-ca-cert-file /tls/redis.crt --tls-auth-clients no "$@"`
+
+// TLSConfig returns TLS configuration that trusts the certificate provided
+// using WithTLS option, and can be used to verify the server in the provided
+// container, for example as redis client TLSConfig option. Use the same
+// options that were used to create the preset. The certificate must be valid
+// for the host that runs the container, for example for "127.0.0.1" or
+// "localhost".
+func TLSConfig(c *gnomock.Container, opts ...Option) (*tls.Config, error) {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p.tlsConfig(c.Host)
+}
+
+func (p *P) tlsConfig(host string) (*tls.Config, error) {
+	return tlsutil.Config(p.TLSCert, host)
+}
+
+func (p *P) tlsOptions() []gnomock.Option {
+	args := []string{"-c", tlsEntrypoint, "redis"}
+	if p.Password != "" {
+		args = append(args, "--requirepass", p.Password)
+	}
+
+	return []gnomock.Option{
+		gnomock.WithEnv("REDIS_TLS_CERT=" + p.TLSCert),
+		gnomock.WithEnv("REDIS_TLS_KEY=" + p.TLSKey),
+		gnomock.WithEntrypoint("/bin/sh", args...),
+	}
+}
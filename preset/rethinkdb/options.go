@@ -0,0 +1,54 @@
+package rethinkdb
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithDatabase creates a database with the provided name in the container.
+// Tables provided using WithTables are created in this database. If not used,
+// the default "test" database is used.
+func WithDatabase(db string) Option {
+	return func(o *P) {
+		o.DB = db
+	}
+}
+
+// WithTables creates tables with the provided names in the database created
+// using WithDatabase, or in the default "test" database. This option can be
+// used multiple times.
+func WithTables(names ...string) Option {
+	return func(o *P) {
+		o.Tables = append(o.Tables, names...)
+	}
+}
+
+// WithData sets up initial container state according to the directory
+// structure at the given path:
+//
+//   - path/first/one.json
+//   - path/first/two.json
+//   - path/second/three.json
+//
+// For such directory structure, two databases are created: "first" and
+// "second". Under "first" database there are two tables, "one" and "two", and
+// under "second" database - one table "three".
+//
+// Files "one.json", "two.json" and "three.json" contain JSON documents to be
+// inserted into the tables, either one document per line, or a single array
+// of documents. Table name is the file name without its extension.
+//
+// Top level files under "path" are ignored, only directories are used.
+// Similarly, directories located anywhere besides top-level "path", are also
+// ignored.
+func WithData(path string) Option {
+	return func(o *P) {
+		o.DataPath = path
+	}
+}
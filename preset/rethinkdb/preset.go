@@ -0,0 +1,261 @@
+// Package rethinkdb includes RethinkDB implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured RethinkDB container to use in tests.
+//
+// The default port accepts client driver connections, and HTTPPort serves
+// the web administration console.
+package rethinkdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/jsondocs"
+	"github.com/orlangure/gnomock/internal/registry"
+	r "gopkg.in/rethinkdb/rethinkdb-go.v6"
+)
+
+// HTTPPort is a name of the port exposed by RethinkDB containers in addition
+// to the default driver port.
+const HTTPPort = "http"
+
+const (
+	defaultVersion  = "2.4.2"
+	defaultPort     = 28015
+	httpPort        = 8080
+	defaultDatabase = "test"
+)
+
+func init() {
+	registry.Register("rethinkdb", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock RethinkDB preset. This preset includes a
+// RethinkDB specific healthcheck function and default RethinkDB image and
+// ports, and allows to optionally set up initial state.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for RethinkDB.
+type P struct {
+	Version  string   `json:"version"`
+	DB       string   `json:"db"`
+	Tables   []string `json:"tables"`
+	DataPath string   `json:"data_path"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/library/rethinkdb:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[HTTPPort] = gnomock.Port{Protocol: "tcp", Port: httpPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+	}
+
+	if p.DB != defaultDatabase || len(p.Tables) > 0 || p.DataPath != "" {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.DB == "" {
+		p.DB = defaultDatabase
+	}
+}
+
+// healthcheck makes sure that the server accepts driver connections and can
+// run queries.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	session, err := connect(c)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = session.Close() }()
+
+	return r.Expr(1).Exec(session, r.ExecOpts{Context: ctx})
+}
+
+// initf creates the database and the tables configured using options, and
+// then loads the data from the data path, if provided.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	session, err := connect(c)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = session.Close() }()
+
+	if err := createDatabase(ctx, session, p.DB); err != nil {
+		return err
+	}
+
+	for _, table := range p.Tables {
+		if err := createTable(ctx, session, p.DB, table); err != nil {
+			return err
+		}
+	}
+
+	if p.DataPath == "" {
+		return nil
+	}
+
+	topLevelDirs, err := os.ReadDir(p.DataPath)
+	if err != nil {
+		return fmt.Errorf("can't read test data path: %w", err)
+	}
+
+	for _, topLevelDir := range topLevelDirs {
+		if !topLevelDir.IsDir() {
+			continue
+		}
+
+		if err := p.setupDB(ctx, session, topLevelDir.Name()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *P) setupDB(ctx context.Context, session *r.Session, db string) error {
+	dataFiles, err := os.ReadDir(path.Join(p.DataPath, db))
+	if err != nil {
+		return fmt.Errorf("can't read test data sub path '%s': %w", db, err)
+	}
+
+	if err := createDatabase(ctx, session, db); err != nil {
+		return err
+	}
+
+	for _, dataFile := range dataFiles {
+		if dataFile.IsDir() {
+			continue
+		}
+
+		fName := dataFile.Name()
+
+		if err := p.setupTable(ctx, session, db, fName); err != nil {
+			return fmt.Errorf("can't setup table from file '%s': %w", fName, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *P) setupTable(ctx context.Context, session *r.Session, db, dataFileName string) error {
+	table := strings.TrimSuffix(dataFileName, path.Ext(dataFileName))
+
+	bs, err := os.ReadFile(path.Join(p.DataPath, db, dataFileName)) // nolint:gosec
+	if err != nil {
+		return fmt.Errorf("can't read file '%s': %w", dataFileName, err)
+	}
+
+	docs, err := readJSON(bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+
+	if err := createTable(ctx, session, db, table); err != nil {
+		return err
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	res, err := r.DB(db).Table(table).Insert(docs).RunWrite(session, r.RunOpts{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("can't insert documents: %w", err)
+	}
+
+	if res.Errors > 0 {
+		return fmt.Errorf("can't insert %d documents: %s", res.Errors, res.FirstError)
+	}
+
+	return nil
+}
+
+// readJSON returns documents found in the provided reader, decoded so that
+// the driver inserts them as objects rather than binary values.
+func readJSON(rd io.Reader) ([]interface{}, error) {
+	raw, err := jsondocs.Read(rd)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]interface{}, len(raw))
+
+	for i, doc := range raw {
+		if err := json.Unmarshal(doc, &docs[i]); err != nil {
+			return nil, fmt.Errorf("can't read documents: %w", err)
+		}
+	}
+
+	return docs, nil
+}
+
+// createDatabase creates a database with the provided name, unless it already
+// exists.
+func createDatabase(ctx context.Context, session *r.Session, name string) error {
+	err := r.Branch(r.DBList().Contains(name), nil, r.DBCreate(name)).Exec(session, r.ExecOpts{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("can't create database '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// createTable creates a table with the provided name in the provided
+// database, unless it already exists.
+func createTable(ctx context.Context, session *r.Session, db, name string) error {
+	err := r.Branch(
+		r.DB(db).TableList().Contains(name), nil, r.DB(db).TableCreate(name),
+	).Exec(session, r.ExecOpts{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("can't create table '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+func connect(c *gnomock.Container) (*r.Session, error) {
+	return r.Connect(r.ConnectOpts{
+		Address: c.DefaultAddress(),
+		Timeout: time.Second,
+	})
+}
@@ -0,0 +1,32 @@
+package rethinkdb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("array", func(t *testing.T) {
+		docs, err := readJSON(strings.NewReader(`[{"a": 1}, {"b": "c"}]`))
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{
+			map[string]interface{}{"a": float64(1)},
+			map[string]interface{}{"b": "c"},
+		}, docs)
+	})
+
+	t.Run("one per line", func(t *testing.T) {
+		docs, err := readJSON(strings.NewReader("{\"a\": 1}\n{\"b\": \"c\"}\n"))
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := readJSON(strings.NewReader(`{"a": `))
+		require.Error(t, err)
+	})
+}
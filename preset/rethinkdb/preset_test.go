@@ -0,0 +1,71 @@
+package rethinkdb_test
+
+import (
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/rethinkdb"
+	"github.com/stretchr/testify/require"
+	r "gopkg.in/rethinkdb/rethinkdb-go.v6"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"2.4.1", "2.4.2"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := rethinkdb.Preset(
+			rethinkdb.WithVersion(version),
+			rethinkdb.WithDatabase("inventory"),
+			rethinkdb.WithTables("items", "locations"),
+			rethinkdb.WithData("./testdata"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		session, err := r.Connect(r.ConnectOpts{Address: container.DefaultAddress()})
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, session.Close()) }()
+
+		require.Equal(t, 0, count(t, session, "inventory", "items"))
+		require.Equal(t, 0, count(t, session, "inventory", "locations"))
+		require.Equal(t, 2, count(t, session, "shop", "products"))
+		require.Equal(t, 3, count(t, session, "shop", "customers"))
+	}
+}
+
+func TestPreset_wrongDataPath(t *testing.T) {
+	t.Parallel()
+
+	p := rethinkdb.Preset(rethinkdb.WithData("./testdata/missing"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read test data path")
+}
+
+func count(t *testing.T, session *r.Session, db, table string) int {
+	t.Helper()
+
+	cursor, err := r.DB(db).Table(table).Count().Run(session)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, cursor.Close()) }()
+
+	var n int
+
+	require.NoError(t, cursor.One(&n))
+
+	return n
+}
@@ -0,0 +1,48 @@
+package scylla
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithMemory sets the amount of memory the node is allowed to use, for
+// example "1G". Default is 512M, which is enough for most tests.
+func WithMemory(memory string) Option {
+	return func(o *P) {
+		o.Memory = memory
+	}
+}
+
+// WithKeyspace makes sure a keyspace with the provided name exists once the
+// node is ready. It is replicated once, since the container runs a single
+// node, and statements from WithCQLFile and WithCQLQueries run in it.
+func WithKeyspace(name string) Option {
+	return func(o *P) {
+		o.Keyspace = name
+	}
+}
+
+// WithCQLQueries runs CQL statements, one statement per query, once the
+// keyspace is created. They run after all the files from WithCQLFile, and
+// repeated use of this option adds more statements.
+func WithCQLQueries(queries ...string) Option {
+	return func(o *P) {
+		o.Queries = append(o.Queries, queries...)
+	}
+}
+
+// WithCQLFile runs a CQL script from the provided file once the keyspace is
+// created. Statements in the script end with semicolons, and whole line
+// comments start with "--" or "//". Repeated use of this option adds more
+// files, which run in the order they were added.
+func WithCQLFile(file string) Option {
+	return func(o *P) {
+		o.QueriesFiles = append(o.QueriesFiles, file)
+	}
+}
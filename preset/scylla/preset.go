@@ -0,0 +1,108 @@
+// Package scylla includes ScyllaDB implementation of Gnomock Preset interface.
+// This Preset can be passed to gnomock.Start() function to create a configured
+// ScyllaDB container to use in tests.
+//
+// ScyllaDB is compatible with Cassandra Query Language and drivers, and starts
+// considerably faster than Cassandra, so it can be used as a drop-in
+// replacement in tests. Containers run in developer mode with a single CPU
+// and limited memory, and do not require authentication.
+package scylla
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/cql"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+const (
+	defaultVersion = "5.1"
+	defaultPort    = 9042
+	defaultMemory  = "512M"
+)
+
+func init() {
+	registry.Register("scylla", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gnomock ScyllaDB preset. This preset includes a
+// ScyllaDB specific healthcheck function and default ScyllaDB image and port,
+// and allows to optionally set up initial state.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for ScyllaDB.
+type P struct {
+	Version      string   `json:"version"`
+	Memory       string   `json:"memory"`
+	Keyspace     string   `json:"keyspace"`
+	Queries      []string `json:"queries"`
+	QueriesFiles []string `json:"queries_files"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/scylladb/scylla:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	args := p.args()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithCommand(args[0], args[1:]...),
+	}
+
+	if p.Keyspace != "" || len(p.Queries) > 0 || len(p.QueriesFiles) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.Memory == "" {
+		p.Memory = defaultMemory
+	}
+}
+
+// args returns flags that make a single node start quickly on shared CI
+// machines: developer mode skips the checks of production settings, and
+// overprovisioned mode stops the node from busy-polling its only CPU.
+func (p *P) args() []string {
+	return []string{
+		"--developer-mode", "1",
+		"--overprovisioned", "1",
+		"--smp", "1",
+		"--memory", p.Memory,
+	}
+}
+
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	return cql.Node{Addr: c.DefaultAddress()}.Healthcheck(ctx)
+}
+
+func (p *P) initf(_ context.Context, c *gnomock.Container) error {
+	return cql.Node{Addr: c.DefaultAddress()}.Init(p.Keyspace, p.QueriesFiles, p.Queries)
+}
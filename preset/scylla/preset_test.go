@@ -0,0 +1,68 @@
+package scylla_test
+
+import (
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/scylla"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"5.1", "5.2"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := scylla.Preset(
+			scylla.WithVersion(version),
+			scylla.WithKeyspace("gnomock"),
+			scylla.WithCQLFile("./testdata/schema.cql"),
+			scylla.WithCQLQueries("insert into users (id, name) values (3, 'baz')"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		addr := container.DefaultAddress()
+		require.NotEmpty(t, addr)
+
+		cluster := gocql.NewCluster(addr)
+		cluster.Keyspace = "gnomock"
+		cluster.DisableInitialHostLookup = true
+
+		session, err := cluster.CreateSession()
+		require.NoError(t, err)
+
+		defer session.Close()
+
+		var count int
+
+		require.NoError(t, session.Query("select count(*) from users").Scan(&count))
+		require.Equal(t, 3, count)
+
+		var name string
+
+		require.NoError(t, session.Query("select name from users where id = 2").Scan(&name))
+		require.Equal(t, "bar", name)
+	}
+}
+
+func TestPreset_wrongCQLFile(t *testing.T) {
+	t.Parallel()
+
+	p := scylla.Preset(scylla.WithCQLFile("./testdata/missing.cql"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read cql file")
+}
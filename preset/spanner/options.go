@@ -0,0 +1,42 @@
+package spanner
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version, for example "1.5.2".
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithProjectID sets the project of the created instance. Clients must use
+// the same project. If not used, DefaultProjectID is used.
+func WithProjectID(projectID string) Option {
+	return func(o *P) {
+		o.ProjectID = projectID
+	}
+}
+
+// WithInstanceID sets the ID of the instance created during init. If not
+// used, DefaultInstanceID is used.
+func WithInstanceID(instanceID string) Option {
+	return func(o *P) {
+		o.InstanceID = instanceID
+	}
+}
+
+// WithDatabase creates a database with the provided name in the instance,
+// and applies the provided DDL statements, such as "CREATE TABLE", to it.
+// Every statement must be a separate string. This option can be used
+// multiple times, with the same or different databases.
+func WithDatabase(name string, ddl ...string) Option {
+	return func(o *P) {
+		if o.Databases == nil {
+			o.Databases = make(map[string][]string)
+		}
+
+		o.Databases[name] = append(o.Databases[name], ddl...)
+	}
+}
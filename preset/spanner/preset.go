@@ -0,0 +1,301 @@
+// Package spanner includes Google Cloud Spanner emulator implementation of
+// Gnomock Preset interface. This Preset can be passed to gnomock.Start()
+// function to create a configured Spanner emulator container to use in tests.
+//
+// The default port serves gRPC API, and RESTPort serves REST API. Most client
+// libraries, as well as gcloud, only need EmulatorHostEnv to use the gRPC
+// port instead of the real service.
+package spanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// RESTPort is a name of the port exposed by Spanner emulator containers in
+// addition to the default gRPC port.
+const RESTPort = "rest"
+
+// EmulatorHostEnv should hold the gRPC address of the emulator, which is the
+// default address of the container; RESTPort address won't work there.
+// spanner.NewClient then dials it without credentials.
+const EmulatorHostEnv = "SPANNER_EMULATOR_HOST"
+
+// Default project and instance used unless different values are configured
+// using WithProjectID and WithInstanceID.
+const (
+	DefaultProjectID  = "gnomock"
+	DefaultInstanceID = "gnomock"
+)
+
+const (
+	defaultVersion = "1.5.2"
+	defaultPort    = 9010
+	restPort       = 9020
+	instanceConfig = "emulator-config"
+)
+
+var errConflict = errors.New("already exists")
+
+func init() {
+	registry.Register("spanner", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Spanner preset. This preset includes a Spanner
+// specific healthcheck function and default Spanner emulator image and
+// ports. It creates an instance, and optionally databases with the provided
+// schema.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Spanner emulator.
+type P struct {
+	Version    string              `json:"version"`
+	ProjectID  string              `json:"project_id"`
+	InstanceID string              `json:"instance_id"`
+	Databases  map[string][]string `json:"databases"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("gcr.io/cloud-spanner-emulator/emulator:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[RESTPort] = gnomock.Port{Protocol: "tcp", Port: restPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithInit(p.initf),
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.ProjectID == "" {
+		p.ProjectID = DefaultProjectID
+	}
+
+	if p.InstanceID == "" {
+		p.InstanceID = DefaultInstanceID
+	}
+}
+
+// healthcheck makes sure that REST API serves requests, and that gRPC API
+// accepts connections.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	path := fmt.Sprintf("/v1/projects/%s/instanceConfigs", p.ProjectID)
+
+	if _, err := request(ctx, c, http.MethodGet, path, nil); err != nil {
+		return fmt.Errorf("rest api is not ready: %w", err)
+	}
+
+	d := net.Dialer{}
+
+	conn, err := d.DialContext(ctx, "tcp", c.DefaultAddress())
+	if err != nil {
+		return fmt.Errorf("grpc api is not ready: %w", err)
+	}
+
+	return conn.Close()
+}
+
+// initf creates the configured instance, and then the configured databases
+// in sorted order, applying their schema.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	if err := p.createInstance(ctx, c); err != nil {
+		return fmt.Errorf("can't create instance '%s': %w", p.InstanceID, err)
+	}
+
+	databases := make([]string, 0, len(p.Databases))
+	for database := range p.Databases {
+		databases = append(databases, database)
+	}
+
+	sort.Strings(databases)
+
+	for _, database := range databases {
+		if err := p.createDatabase(ctx, c, database, p.Databases[database]); err != nil {
+			return fmt.Errorf("can't create database '%s': %w", database, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *P) createInstance(ctx context.Context, c *gnomock.Container) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"instanceId": p.InstanceID,
+		"instance": map[string]interface{}{
+			"config":      fmt.Sprintf("projects/%s/instanceConfigs/%s", p.ProjectID, instanceConfig),
+			"displayName": p.InstanceID,
+			"nodeCount":   1,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/v1/projects/%s/instances", p.ProjectID)
+
+	bs, err := request(ctx, c, http.MethodPost, path, body)
+	if errors.Is(err, errConflict) {
+		// instances already exist when an existing container is reused
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return waitOperation(ctx, c, bs)
+}
+
+func (p *P) createDatabase(ctx context.Context, c *gnomock.Container, database string, ddl []string) error {
+	statements := make([]string, 0, len(ddl))
+
+	for _, statement := range ddl {
+		statement = strings.TrimSuffix(strings.TrimSpace(statement), ";")
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"createStatement": fmt.Sprintf("CREATE DATABASE `%s`", database),
+		"extraStatements": statements,
+	})
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/v1/projects/%s/instances/%s/databases", p.ProjectID, p.InstanceID)
+
+	bs, err := request(ctx, c, http.MethodPost, path, body)
+	if errors.Is(err, errConflict) {
+		// databases already exist when an existing container is reused
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return waitOperation(ctx, c, bs)
+}
+
+type operation struct {
+	Name  string `json:"name"`
+	Done  bool   `json:"done"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// waitOperation waits until the long-running operation described in the
+// provided response body is done, and returns its error if it failed.
+func waitOperation(ctx context.Context, c *gnomock.Container, bs []byte) error {
+	for {
+		var op operation
+		if err := json.Unmarshal(bs, &op); err != nil {
+			return fmt.Errorf("can't parse operation: %w", err)
+		}
+
+		if op.Error != nil {
+			return fmt.Errorf("operation failed: %s", op.Error.Message)
+		}
+
+		if op.Done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("operation '%s' is not done: %w", op.Name, ctx.Err())
+		case <-time.After(time.Millisecond * 250):
+		}
+
+		var err error
+
+		bs, err = request(ctx, c, http.MethodGet, "/v1/"+op.Name, nil)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func request(ctx context.Context, c *gnomock.Container, method, path string, body []byte) ([]byte, error) {
+	addr := fmt.Sprintf("http://%s%s", c.Address(RESTPort), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		return nil, errConflict
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+
+		if err := json.Unmarshal(bs, &apiErr); err == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, apiErr.Error.Message)
+		}
+
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return bs, nil
+}
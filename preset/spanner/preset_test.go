@@ -0,0 +1,131 @@
+package spanner_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	gspanner "cloud.google.com/go/spanner"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/spanner"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"1.5.1", "1.5.2"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := spanner.Preset(
+			spanner.WithVersion(version),
+			spanner.WithProjectID("project"),
+			spanner.WithInstanceID("instance"),
+			spanner.WithDatabase(
+				"app",
+				"CREATE TABLE users (id INT64 NOT NULL, name STRING(64)) PRIMARY KEY (id);",
+			),
+			spanner.WithDatabase(
+				"app",
+				"CREATE INDEX users_by_name ON users(name)",
+			),
+			spanner.WithDatabase("empty"),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		client := newClient(ctx, t, container, "projects/project/instances/instance/databases/app")
+
+		defer client.Close()
+
+		_, err = client.Apply(ctx, []*gspanner.Mutation{
+			gspanner.Insert("users", []string{"id", "name"}, []interface{}{1, "Alice"}),
+		})
+		require.NoError(t, err)
+
+		stmt := gspanner.Statement{SQL: "SELECT name FROM users@{FORCE_INDEX=users_by_name} WHERE id = 1"}
+		row, err := client.Single().Query(ctx, stmt).Next()
+		require.NoError(t, err)
+
+		var name string
+		require.NoError(t, row.Columns(&name))
+		require.Equal(t, "Alice", name)
+
+		addr := fmt.Sprintf(
+			"http://%s/v1/projects/project/instances/instance/databases",
+			container.Address(spanner.RESTPort),
+		)
+		resp, err := http.Get(addr) // nolint:gosec,noctx
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, resp.Body.Close()) }()
+
+		var out struct {
+			Databases []struct {
+				Name string `json:"name"`
+			} `json:"databases"`
+		}
+
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		require.Len(t, out.Databases, 2)
+	}
+}
+
+func TestPreset_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	p := spanner.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	addr := fmt.Sprintf(
+		"http://%s/v1/projects/%s/instances/%s",
+		container.Address(spanner.RESTPort), spanner.DefaultProjectID, spanner.DefaultInstanceID,
+	)
+	resp, err := http.Get(addr) // nolint:gosec,noctx
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestPreset_invalidSchema(t *testing.T) {
+	t.Parallel()
+
+	p := spanner.Preset(spanner.WithDatabase("app", "CREATE TABLE invalid"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't create database 'app'")
+}
+
+func newClient(ctx context.Context, t *testing.T, c *gnomock.Container, database string) *gspanner.Client {
+	t.Helper()
+
+	client, err := gspanner.NewClient(
+		ctx, database,
+		option.WithEndpoint(c.DefaultAddress()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	require.NoError(t, err)
+
+	return client
+}
@@ -42,6 +42,10 @@ type Event struct {
 
 func (p *P) initf() gnomock.InitFunc {
 	return func(ctx context.Context, c *gnomock.Container) (err error) {
+		if err := CreateIndexes(ctx, c, p.AdminPassword, p.Indexes...); err != nil {
+			return fmt.Errorf("can't create indexes: %w", err)
+		}
+
 		if p.ValuesFile != "" {
 			f, err := os.Open(p.ValuesFile)
 			if err != nil {
@@ -123,6 +127,31 @@ func Ingest(ctx context.Context, c *gnomock.Container, adminPassword string, eve
 	}
 }
 
+// CreateIndexes creates event indexes with the provided names in splunk
+// container. Use the same password you provided in WithPassword. Indexes that
+// already exist are not modified.
+func CreateIndexes(ctx context.Context, c *gnomock.Container, adminPassword string, indexes ...string) error {
+	postFormWithPassword := requestWithAuth(ctx, http.MethodPost, adminPassword, false)
+	ensureIndex := indexRegistry(postFormWithPassword, c.Address(APIPort))
+
+	for _, index := range indexes {
+		if err := ensureIndex(index); err != nil {
+			return fmt.Errorf("can't create index '%s': %w", index, err)
+		}
+	}
+
+	return nil
+}
+
+// NewHECToken issues a new HTTP Event Collector token in splunk container,
+// and returns it. Use the same password you provided in WithPassword. The
+// token can be used to send events to HECPort of the container.
+func NewHECToken(ctx context.Context, c *gnomock.Container, adminPassword string) (string, error) {
+	postFormWithPassword := requestWithAuth(ctx, http.MethodPost, adminPassword, false)
+
+	return issueToken(postFormWithPassword, c.Address(APIPort))
+}
+
 func ingestEvents(ctx context.Context, c *gnomock.Container, adminPassword string, events []Event) error {
 	postFormWithPassword := requestWithAuth(ctx, http.MethodPost, adminPassword, false)
 	ensureIndex := indexRegistry(postFormWithPassword, c.Address(APIPort))
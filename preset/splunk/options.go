@@ -47,3 +47,23 @@ func WithPassword(pass string) Option {
 		o.AdminPassword = pass
 	}
 }
+
+// WithIndexes creates event indexes with the provided names during container
+// setup. Indexes used by events sent using WithValues or WithValuesFile are
+// created automatically.
+func WithIndexes(indexes ...string) Option {
+	return func(o *P) {
+		o.Indexes = append(o.Indexes, indexes...)
+	}
+}
+
+// WithHECToken configures HTTP Event Collector of the container with the
+// provided token, so that events can be sent to HECPort using a token known
+// in advance. Splunk expects tokens in GUID format, for example
+// "00000000-0000-0000-0000-000000000000". Use NewHECToken to issue additional
+// tokens in a running container.
+func WithHECToken(token string) Option {
+	return func(o *P) {
+		o.HECToken = token
+	}
+}
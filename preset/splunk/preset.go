@@ -27,6 +27,14 @@ const (
 
 	// WebPort is the name of a port exposed by Splunk web UI.
 	WebPort string = "web"
+
+	// HECPort is the name of a port exposed by Splunk HTTP Event Collector.
+	// It is the same port as CollectorPort.
+	HECPort = CollectorPort
+
+	// ManagementPort is the name of a port exposed by Splunk management API.
+	// It is the same port as APIPort.
+	ManagementPort = APIPort
 )
 
 const defaultVersion = "latest"
@@ -50,11 +58,13 @@ func Preset(opts ...Option) gnomock.Preset {
 
 // P is a Gnomock Preset implementation of Splunk.
 type P struct {
-	Values        []Event `json:"values"`
-	ValuesFile    string  `json:"values_file"`
-	AcceptLicense bool    `json:"accept_license"`
-	AdminPassword string  `json:"admin_password"`
-	Version       string  `json:"version"`
+	Values        []Event  `json:"values"`
+	ValuesFile    string   `json:"values_file"`
+	AcceptLicense bool     `json:"accept_license"`
+	AdminPassword string   `json:"admin_password"`
+	Version       string   `json:"version"`
+	Indexes       []string `json:"indexes"`
+	HECToken      string   `json:"hec_token"`
 }
 
 // Image returns an image that should be pulled to create this container.
@@ -87,7 +97,12 @@ func (p *P) Options() []gnomock.Option {
 		)
 	}
 
-	if p.Values != nil || p.ValuesFile != "" {
+	// the image configures HTTP Event Collector with this token on startup
+	if p.HECToken != "" {
+		opts = append(opts, gnomock.WithEnv("SPLUNK_HEC_TOKEN="+p.HECToken))
+	}
+
+	if p.Values != nil || p.ValuesFile != "" || len(p.Indexes) > 0 {
 		init := p.initf()
 		opts = append(opts, gnomock.WithInit(init))
 	}
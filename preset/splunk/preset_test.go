@@ -18,6 +18,8 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+const hecToken = "11111111-2222-3333-4444-555555555555"
+
 func TestPreset(t *testing.T) {
 	events := make([]splunk.Event, 1000)
 
@@ -47,6 +49,8 @@ func TestPreset(t *testing.T) {
 		splunk.WithPassword("12345678"),
 		splunk.WithValues(events),
 		splunk.WithValuesFile("./testdata/events.txt"),
+		splunk.WithIndexes("qux"),
+		splunk.WithHECToken(hecToken),
 	)
 	c, err := gnomock.Start(p)
 
@@ -63,11 +67,7 @@ func TestPreset(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("initial values ingested", func(t *testing.T) {
-		client := &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-		}
+		client := insecureClient()
 
 		data := url.Values{}
 		data.Add("search", "search index=foo some > 900 | stats count")
@@ -98,6 +98,25 @@ func TestPreset(t *testing.T) {
 		require.Equal(t, "99", r.Result.Count)
 	})
 
+	t.Run("hec tokens", func(t *testing.T) {
+		issued, err := splunk.NewHECToken(context.Background(), c, "12345678")
+		require.NoError(t, err)
+		require.NotEmpty(t, issued)
+
+		for _, token := range []string{hecToken, issued} {
+			event := `{"event":"hello","index":"qux"}`
+			addr := fmt.Sprintf("https://%s/services/collector", c.Address(splunk.HECPort))
+			req, err := http.NewRequest(http.MethodPost, addr, bytes.NewBufferString(event))
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Splunk "+token)
+
+			res, err := insecureClient().Do(req)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			require.Equal(t, http.StatusOK, res.StatusCode)
+		}
+	})
+
 	t.Run("context canceled", func(t *testing.T) {
 		events := make([]splunk.Event, 1000)
 
@@ -128,3 +147,11 @@ func TestPreset(t *testing.T) {
 		require.Truef(t, errors.Is(err, context.Canceled), "want context.Canceled, got %v", err)
 	})
 }
+
+func insecureClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package tidb
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithDatabase creates a database with the provided name in the container. If
+// not provided, "mydb" is used by default. WithQueries, if provided, runs
+// against the new database.
+func WithDatabase(db string) Option {
+	return func(o *P) {
+		o.DB = db
+	}
+}
+
+// WithUser creates a new user with the provided credentials, and grants it all
+// privileges on the database. If not used, the default credentials are
+// gnomock:gnomick.
+func WithUser(user, password string) Option {
+	return func(o *P) {
+		o.User = user
+		o.Password = password
+	}
+}
+
+// WithQueries executes the provided queries against the database created with
+// WithDatabase, or against default "mydb" database, as the created user.
+func WithQueries(queries ...string) Option {
+	return func(o *P) {
+		o.Queries = append(o.Queries, queries...)
+	}
+}
+
+// WithQueriesFile sets a file name to read initial queries from. Queries from
+// this file are executed before any other queries provided in WithQueries.
+// Files may include multiple statements. This option can be used multiple
+// times, and the files are executed in the same order.
+func WithQueriesFile(file string) Option {
+	return func(o *P) {
+		o.QueriesFiles = append(o.QueriesFiles, file)
+	}
+}
@@ -0,0 +1,199 @@
+// Package tidb includes TiDB implementation of Gnomock Preset interface. This
+// Preset can be passed to gnomock.Start() function to create a configured TiDB
+// container to use in tests.
+//
+// The container runs a single TiDB server with embedded storage engine, which
+// is enough to validate MySQL protocol code against TiDB SQL layer, but does
+// not include PD and TiKV components. Use any MySQL driver to connect to the
+// default port.
+package tidb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+)
+
+// StatusPort is a name of the port exposed by TiDB status API, which serves
+// server status and metrics over HTTP.
+const StatusPort = "status"
+
+const (
+	defaultVersion  = "v6.5.1"
+	defaultPort     = 4000
+	statusPort      = 10080
+	defaultUser     = "gnomock"
+	defaultPassword = "gnomick"
+	defaultDatabase = "mydb"
+
+	rootUser = "root"
+)
+
+func init() {
+	registry.Register("tidb", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock TiDB preset. This preset includes a TiDB
+// specific healthcheck function and default TiDB image and ports, and allows
+// to optionally set up initial state.
+//
+// When used without any configuration, it creates `mydb` database, and
+// `gnomock` user with `gnomick` password, which has all privileges on this
+// database. Root user without a password is available as well.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for TiDB.
+type P struct {
+	Version      string   `json:"version"`
+	DB           string   `json:"db"`
+	User         string   `json:"user"`
+	Password     string   `json:"password"`
+	Queries      []string `json:"queries"`
+	QueriesFiles []string `json:"queries_files"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/pingcap/tidb:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[StatusPort] = gnomock.Port{Protocol: "tcp", Port: statusPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithInit(p.initf),
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.DB == "" {
+		p.DB = defaultDatabase
+	}
+
+	if p.User == "" && p.Password == "" {
+		p.User = defaultUser
+		p.Password = defaultPassword
+	}
+}
+
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	db, err := connect(c, rootUser, "", "")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	var one int
+
+	return db.QueryRowContext(ctx, `select 1`).Scan(&one)
+}
+
+// initf creates the database and the user, and then executes queries from
+// files and the rest of the queries, in this order, as this user.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	root, err := connect(c, rootUser, "", "")
+	if err != nil {
+		return err
+	}
+
+	err = sqlsetup.Execute(ctx, root, []string{
+		fmt.Sprintf("create database if not exists `%s`", p.DB),
+		fmt.Sprintf("create user if not exists %s@'%%' identified by %s", literal(p.User), literal(p.Password)),
+		fmt.Sprintf("grant all privileges on `%s`.* to %s@'%%'", p.DB, literal(p.User)),
+	})
+
+	_ = root.Close()
+
+	if err != nil {
+		return err
+	}
+
+	db, err := connect(c, p.User, p.Password, p.DB)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	setup := sqlsetup.Setup{Queries: p.Queries, QueriesFiles: p.QueriesFiles}
+
+	queries, err := setup.AllQueries()
+	if err != nil {
+		return err
+	}
+
+	return sqlsetup.Execute(ctx, db, queries)
+}
+
+// ConnString returns a MySQL driver connection string that can be used to
+// connect to the database created in the provided container as the created
+// user. Use the same options that were used to create the preset, so that
+// the connection string includes the right database name and credentials.
+func ConnString(c *gnomock.Container, opts ...Option) string {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.setDefaults()
+
+	return connString(c, p.User, p.Password, p.DB)
+}
+
+func connString(c *gnomock.Container, user, password, db string) string {
+	cfg := mysqldriver.NewConfig()
+	cfg.User = user
+	cfg.Passwd = password
+	cfg.Net = "tcp"
+	cfg.Addr = c.DefaultAddress()
+	cfg.DBName = db
+	cfg.MultiStatements = true
+
+	return cfg.FormatDSN()
+}
+
+func connect(c *gnomock.Container, user, password, db string) (*sql.DB, error) {
+	conn, err := sql.Open("mysql", connString(c, user, password, db))
+	if err != nil {
+		return nil, err
+	}
+
+	return conn, conn.Ping()
+}
+
+// literal returns the provided value as a string literal.
+func literal(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(s) + "'"
+}
@@ -0,0 +1,83 @@
+package tidb_test
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/tidb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"v6.1.5", "v6.5.1"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		opts := []tidb.Option{
+			tidb.WithVersion(version),
+			tidb.WithDatabase("gnomock"),
+			tidb.WithUser("user", "it's secret"),
+			tidb.WithQueriesFile("./testdata/queries.sql"),
+			tidb.WithQueries("insert into things (id, name) values (3, 'baz')"),
+		}
+
+		container, err := gnomock.Start(tidb.Preset(opts...))
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+		require.NotEmpty(t, container.Address(tidb.StatusPort))
+
+		db, err := sql.Open("mysql", tidb.ConnString(container, opts...))
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, db.Close()) }()
+
+		var count int
+
+		require.NoError(t, db.QueryRow("select count(*) from things").Scan(&count))
+		require.Equal(t, 3, count)
+
+		var version string
+
+		require.NoError(t, db.QueryRow("select version()").Scan(&version))
+		require.Contains(t, version, "TiDB")
+	}
+}
+
+func TestPreset_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	container, err := gnomock.Start(tidb.Preset())
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err := sql.Open("mysql", tidb.ConnString(container))
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, db.Close()) }()
+
+	_, err = db.Exec("create table t (a int)")
+	require.NoError(t, err)
+}
+
+func TestPreset_wrongQueriesFile(t *testing.T) {
+	t.Parallel()
+
+	p := tidb.Preset(tidb.WithQueriesFile("./testdata/missing.sql"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read queries file")
+}
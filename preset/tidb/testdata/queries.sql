@@ -0,0 +1,2 @@
+create table things (id int primary key, name varchar(50));
+insert into things (id, name) values (1, 'foo'), (2, 'bar');
@@ -0,0 +1,89 @@
+package trino
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+)
+
+const defaultUser = "gnomock"
+
+// statementResults is a single page of query results returned by Trino REST
+// API.
+type statementResults struct {
+	NextURI string          `json:"nextUri"`
+	Data    [][]interface{} `json:"data"`
+	Error   *struct {
+		Message   string `json:"message"`
+		ErrorName string `json:"errorName"`
+	} `json:"error"`
+}
+
+// Query executes the provided query in the provided container using Trino
+// REST API, and returns all the rows of the result. Values are decoded from
+// JSON, so numbers are returned as float64. Query can be used to verify the
+// state of the catalogs in tests that don't use a Trino driver.
+func Query(ctx context.Context, c *gnomock.Container, query string) ([][]interface{}, error) {
+	addr := fmt.Sprintf("http://%s/v1/statement", c.DefaultAddress())
+
+	results, err := request(ctx, http.MethodPost, addr, strings.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+
+	rows := results.Data
+
+	for results.NextURI != "" {
+		results, err = request(ctx, http.MethodGet, results.NextURI, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, results.Data...)
+	}
+
+	return rows, nil
+}
+
+func request(ctx context.Context, method, addr string, body io.Reader) (*statementResults, error) {
+	req, err := http.NewRequestWithContext(ctx, method, addr, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-Trino-User", defaultUser)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, string(bytes.TrimSpace(bs)))
+	}
+
+	var results statementResults
+
+	if err := json.Unmarshal(bs, &results); err != nil {
+		return nil, fmt.Errorf("can't decode query results: %w", err)
+	}
+
+	if results.Error != nil {
+		return nil, fmt.Errorf("%s: %s", results.Error.ErrorName, results.Error.Message)
+	}
+
+	return &results, nil
+}
@@ -0,0 +1,45 @@
+package trino
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithCatalog configures a catalog with the provided name and connector
+// properties, as they would appear in "etc/catalog/<name>.properties" file.
+// For example, use {"connector.name": "memory"} to add another in-memory
+// catalog. This option can be used multiple times, and replaces the default
+// catalogs of the image with the same name.
+func WithCatalog(name string, properties map[string]string) Option {
+	return func(o *P) {
+		if o.Catalogs == nil {
+			o.Catalogs = make(map[string]map[string]string)
+		}
+
+		o.Catalogs[name] = properties
+	}
+}
+
+// WithCatalogDir adds catalogs from ".properties" files found in the provided
+// host directory, which is mounted into the container. Catalogs configured
+// using WithCatalog take precedence over the files with the same name.
+func WithCatalogDir(dir string) Option {
+	return func(o *P) {
+		o.CatalogDir = dir
+	}
+}
+
+// WithQueries executes the provided queries once the server is ready, for
+// example to create tables in "memory" catalog. Every query should be a
+// single statement without a trailing semicolon.
+func WithQueries(queries ...string) Option {
+	return func(o *P) {
+		o.Queries = append(o.Queries, queries...)
+	}
+}
@@ -0,0 +1,191 @@
+// Package trino includes Trino implementation of Gnomock Preset interface.
+// This Preset can be passed to gnomock.Start() function to create a
+// configured Trino container to use in tests.
+//
+// Trino images include several catalogs by default, such as "memory" and
+// "tpch". Additional catalogs can be configured using WithCatalog and
+// WithCatalogDir options, for example to connect Trino to other containers
+// started by Gnomock.
+package trino
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/hostpath"
+	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/shell"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+)
+
+const (
+	defaultVersion = "410"
+	defaultPort    = 8080
+
+	catalogDir     = "/etc/trino/catalog"
+	hostCatalogDir = "/gnomock/catalog"
+)
+
+// entrypoint starts the server as usual once the catalogs are configured.
+const entrypoint = `exec /usr/lib/trino/bin/run-trino`
+
+func init() {
+	registry.Register("trino", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Trino preset. This preset includes a Trino
+// specific healthcheck function and default Trino image and port, and allows
+// to optionally configure catalogs and set up initial state.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Trino.
+type P struct {
+	Version    string                       `json:"version"`
+	Catalogs   map[string]map[string]string `json:"catalogs"`
+	CatalogDir string                       `json:"catalog_dir"`
+	Queries    []string                     `json:"queries"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/trinodb/trino:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+	}
+
+	if p.CatalogDir != "" {
+		opts = append(opts, gnomock.WithHostMounts(hostpath.Abs(p.CatalogDir), hostCatalogDir))
+	}
+
+	if setup := p.catalogSetup(); setup != "" {
+		opts = append(opts, gnomock.WithEntrypoint("/bin/sh", "-c", setup+entrypoint))
+	}
+
+	if len(p.Queries) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// catalogSetup returns a script that copies catalog files from the mounted
+// directory, and writes the catalogs configured using options, into the
+// catalog directory of the server.
+func (p *P) catalogSetup() string {
+	var setup strings.Builder
+
+	if p.CatalogDir != "" {
+		setup.WriteString("cp " + hostCatalogDir + "/*.properties " + catalogDir + "/\n")
+	}
+
+	names := make([]string, 0, len(p.Catalogs))
+	for name := range p.Catalogs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		props := p.Catalogs[name]
+
+		keys := make([]string, 0, len(props))
+		for k := range props {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		lines := make([]string, 0, len(keys))
+		for _, k := range keys {
+			lines = append(lines, k+"="+props[k])
+		}
+
+		fmt.Fprintf(
+			&setup, "printf '%%s\\n' %s > %s/%s.properties\n",
+			shell.Quote(strings.Join(lines, "\n")), catalogDir, filepath.Base(name),
+		)
+	}
+
+	return setup.String()
+}
+
+// healthcheck waits for the server to finish starting, and then makes sure
+// that it can run queries, which requires the node to be registered.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	addr := fmt.Sprintf("http://%s/v1/info", c.DefaultAddress())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	var info struct {
+		Starting bool `json:"starting"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return fmt.Errorf("can't decode server info: %w", err)
+	}
+
+	if info.Starting {
+		return errors.New("server is starting")
+	}
+
+	_, err = Query(ctx, c, `select 1`)
+
+	return err
+}
+
+// initf executes the provided queries one by one.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	for i, q := range p.Queries {
+		if _, err := Query(ctx, c, q); err != nil {
+			return fmt.Errorf("query %d of %d failed (%s): %w", i+1, len(p.Queries), sqlsetup.Snippet(q), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package trino_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/trino"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"403", "410"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := trino.Preset(
+			trino.WithVersion(version),
+			trino.WithCatalog("gnomock", map[string]string{"connector.name": "memory"}),
+			trino.WithCatalogDir("./testdata/catalog"),
+			trino.WithQueries(
+				"create table gnomock.default.things (id int, name varchar)",
+				"insert into gnomock.default.things values (1, 'foo'), (2, 'bar')",
+			),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		ctx := context.Background()
+
+		rows, err := trino.Query(ctx, container, "select name from gnomock.default.things order by id")
+		require.NoError(t, err)
+		require.Equal(t, [][]interface{}{{"foo"}, {"bar"}}, rows)
+
+		rows, err = trino.Query(ctx, container, "select count(*) from extra.tiny.nation")
+		require.NoError(t, err)
+		require.Equal(t, [][]interface{}{{float64(25)}}, rows)
+
+		_, err = trino.Query(ctx, container, "select * from gnomock.default.missing")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "TABLE_NOT_FOUND")
+	}
+}
+
+func TestPreset_wrongQuery(t *testing.T) {
+	t.Parallel()
+
+	p := trino.Preset(trino.WithQueries("select * from memory.default.missing"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "query 1 of 1 failed")
+}
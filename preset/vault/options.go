@@ -0,0 +1,54 @@
+package vault
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithRootToken sets the root token accepted by the container. If not used,
+// DefaultRootToken is used.
+func WithRootToken(token string) Option {
+	return func(o *P) {
+		o.RootToken = token
+	}
+}
+
+// WithMount enables a secrets engine of the provided type, such as "kv",
+// "transit" or "pki", at the provided path. Options are engine specific, for
+// example {"version": "2"} for "kv" engine, and can be nil. This option can be
+// used multiple times.
+func WithMount(path, engineType string, options map[string]string) Option {
+	return func(o *P) {
+		o.Mounts = append(o.Mounts, Mount{Path: path, Type: engineType, Options: options})
+	}
+}
+
+// WithPolicy writes an ACL policy with the provided name and HCL rules, for
+// example `path "secret/data/app/*" { capabilities = ["read"] }`. This option
+// can be used multiple times.
+func WithPolicy(name, rules string) Option {
+	return func(o *P) {
+		if o.Policies == nil {
+			o.Policies = make(map[string]string)
+		}
+
+		o.Policies[name] = rules
+	}
+}
+
+// WithSecret writes the provided data to the provided path once the engines
+// are mounted, for example WithSecret("secret/app/db", map[string]interface{}{
+// "password": "secret"}). Secrets under version 2 KV engines, such as the
+// default "secret/" engine, are written without "data/" segment in their
+// path. This option can be used multiple times.
+func WithSecret(path string, data map[string]interface{}) Option {
+	return func(o *P) {
+		o.Secrets = append(o.Secrets, Secret{Path: path, Data: data})
+	}
+}
@@ -0,0 +1,298 @@
+// Package vault includes HashiCorp Vault implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured Vault container to use in tests.
+//
+// The container runs Vault server in development mode: it is initialized and
+// unsealed, stores its data in memory, and accepts the configured root token.
+// A version 2 KV secrets engine is mounted at "secret/" by default.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+// DefaultRootToken is the root token accepted by the container unless a
+// different token is configured using WithRootToken.
+const DefaultRootToken = "gnomock-root-token"
+
+const (
+	defaultVersion = "1.13.1"
+	defaultPort    = 8200
+	upgradeTimeout = time.Second * 10
+)
+
+func init() {
+	registry.Register("vault", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Vault preset. This preset includes a Vault
+// specific healthcheck function and default Vault image and port, and allows
+// to optionally mount secrets engines, write policies and secrets.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Vault.
+type P struct {
+	Version   string            `json:"version"`
+	RootToken string            `json:"root_token"`
+	Mounts    []Mount           `json:"mounts"`
+	Policies  map[string]string `json:"policies"`
+	Secrets   []Secret          `json:"secrets"`
+}
+
+// Mount is a secrets engine enabled during container initialization.
+type Mount struct {
+	// Path is the path to mount the engine at, for example "kv/".
+	Path string `json:"path"`
+
+	// Type is the type of the engine, for example "kv", "transit" or "pki".
+	Type string `json:"type"`
+
+	// Options are engine specific options, such as {"version": "2"} for "kv"
+	// engine.
+	Options map[string]string `json:"options"`
+}
+
+// Secret is a secret written during container initialization.
+type Secret struct {
+	// Path is the path of the secret, for example "secret/app/db". For
+	// version 2 KV engines, the path must not include "data/" segment, it is
+	// added automatically.
+	Path string `json:"path"`
+
+	// Data is the secret data, for example {"password": "secret"}.
+	Data map[string]interface{} `json:"data"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/hashicorp/vault:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(p.healthcheck),
+		gnomock.WithEnv("VAULT_DEV_ROOT_TOKEN_ID=" + p.RootToken),
+		gnomock.WithEnv(fmt.Sprintf("VAULT_DEV_LISTEN_ADDRESS=0.0.0.0:%d", defaultPort)),
+		gnomock.WithEnv("SKIP_SETCAP=true"),
+	}
+
+	if len(p.Mounts) > 0 || len(p.Policies) > 0 || len(p.Secrets) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.RootToken == "" {
+		p.RootToken = DefaultRootToken
+	}
+}
+
+// healthcheck makes sure that the server is initialized, unsealed and active.
+// In all the other states, sys/health endpoint responds with an error status.
+func (p *P) healthcheck(ctx context.Context, c *gnomock.Container) error {
+	body, err := p.request(ctx, c, http.MethodGet, "/v1/sys/health", nil)
+	if err != nil {
+		return err
+	}
+
+	var health struct {
+		Initialized bool `json:"initialized"`
+		Sealed      bool `json:"sealed"`
+	}
+
+	if err := json.Unmarshal(body, &health); err != nil {
+		return fmt.Errorf("can't decode health status: %w", err)
+	}
+
+	if !health.Initialized || health.Sealed {
+		return fmt.Errorf("unexpected health status: initialized=%t, sealed=%t", health.Initialized, health.Sealed)
+	}
+
+	return nil
+}
+
+// initf mounts the configured secrets engines, and then writes the policies
+// and the secrets.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	for _, m := range p.Mounts {
+		body, err := json.Marshal(map[string]interface{}{"type": m.Type, "options": m.Options})
+		if err != nil {
+			return err
+		}
+
+		path := "/v1/sys/mounts/" + strings.Trim(m.Path, "/")
+
+		if _, err := p.request(ctx, c, http.MethodPost, path, body); err != nil {
+			return fmt.Errorf("can't mount '%s' engine at '%s': %w", m.Type, m.Path, err)
+		}
+	}
+
+	for name, policy := range p.Policies {
+		body, err := json.Marshal(map[string]string{"policy": policy})
+		if err != nil {
+			return err
+		}
+
+		if _, err := p.request(ctx, c, http.MethodPut, "/v1/sys/policies/acl/"+name, body); err != nil {
+			return fmt.Errorf("can't write policy '%s': %w", name, err)
+		}
+	}
+
+	if len(p.Secrets) == 0 {
+		return nil
+	}
+
+	mounts, err := p.kvMounts(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range p.Secrets {
+		if err := p.writeSecret(ctx, c, mounts, s); err != nil {
+			return fmt.Errorf("can't write secret '%s': %w", s.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// kvMounts returns paths of all KV engines, such as "secret/", and their
+// versions.
+func (p *P) kvMounts(ctx context.Context, c *gnomock.Container) (map[string]string, error) {
+	body, err := p.request(ctx, c, http.MethodGet, "/v1/sys/mounts", nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't list mounts: %w", err)
+	}
+
+	var res struct {
+		Data map[string]struct {
+			Type    string            `json:"type"`
+			Options map[string]string `json:"options"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("can't decode mounts: %w", err)
+	}
+
+	mounts := make(map[string]string)
+
+	for path, m := range res.Data {
+		if m.Type == "kv" {
+			mounts[path] = m.Options["version"]
+		}
+	}
+
+	return mounts, nil
+}
+
+// writeSecret writes the secret to the provided path. Secrets under version
+// 2 KV engines are written to "data/" sub path. Right after such engine is
+// mounted, it is briefly unavailable, so the write is retried for a while.
+func (p *P) writeSecret(ctx context.Context, c *gnomock.Container, mounts map[string]string, s Secret) error {
+	secretPath := strings.TrimPrefix(s.Path, "/")
+
+	var data interface{} = s.Data
+
+	for mount, version := range mounts {
+		if version == "2" && strings.HasPrefix(secretPath, mount) {
+			secretPath = mount + "data/" + strings.TrimPrefix(secretPath, mount)
+			data = map[string]interface{}{"data": s.Data}
+
+			break
+		}
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, upgradeTimeout)
+	defer cancel()
+
+	for {
+		_, err := p.request(ctx, c, http.MethodPost, "/v1/"+secretPath, body)
+		if err == nil || !strings.Contains(err.Error(), "Upgrading from non-versioned to versioned data") {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("kv engine didn't become available: %w", ctx.Err())
+		case <-time.After(time.Millisecond * 250):
+		}
+	}
+}
+
+// request sends an authenticated request to Vault HTTP API, and returns the
+// response body.
+func (p *P) request(ctx context.Context, c *gnomock.Container, method, path string, body []byte) ([]byte, error) {
+	addr := fmt.Sprintf("http://%s%s", c.DefaultAddress(), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-Vault-Token", p.RootToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr struct {
+			Errors []string `json:"errors"`
+		}
+
+		if err := json.Unmarshal(bs, &apiErr); err == nil && len(apiErr.Errors) > 0 {
+			return nil, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, strings.Join(apiErr.Errors, "; "))
+		}
+
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return bs, nil
+}
@@ -0,0 +1,107 @@
+package vault_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"1.12.5", "1.13.1"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := vault.Preset(
+			vault.WithVersion(version),
+			vault.WithRootToken("root"),
+			vault.WithMount("kv1/", "kv", map[string]string{"version": "1"}),
+			vault.WithMount("kv2", "kv", map[string]string{"version": "2"}),
+			vault.WithMount("transit", "transit", nil),
+			vault.WithPolicy("app", `path "kv2/data/app/*" { capabilities = ["read"] }`),
+			vault.WithSecret("secret/app/db", map[string]interface{}{"password": "default"}),
+			vault.WithSecret("kv1/app/db", map[string]interface{}{"password": "v1"}),
+			vault.WithSecret("/kv2/app/db", map[string]interface{}{"password": "v2"}),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		var kv2 struct {
+			Data struct {
+				Data map[string]string `json:"data"`
+			} `json:"data"`
+		}
+
+		require.Equal(t, http.StatusOK, request(t, container, "root", http.MethodGet, "/v1/secret/data/app/db", &kv2))
+		require.Equal(t, "default", kv2.Data.Data["password"])
+
+		require.Equal(t, http.StatusOK, request(t, container, "root", http.MethodGet, "/v1/kv2/data/app/db", &kv2))
+		require.Equal(t, "v2", kv2.Data.Data["password"])
+
+		var kv1 struct {
+			Data map[string]string `json:"data"`
+		}
+
+		require.Equal(t, http.StatusOK, request(t, container, "root", http.MethodGet, "/v1/kv1/app/db", &kv1))
+		require.Equal(t, "v1", kv1.Data["password"])
+
+		var token struct {
+			Auth struct {
+				ClientToken string `json:"client_token"`
+			} `json:"auth"`
+		}
+
+		require.Equal(t, http.StatusOK, request(t, container, "root", http.MethodPost, "/v1/auth/token/create?policies=app", &token))
+
+		appToken := token.Auth.ClientToken
+		require.Equal(t, http.StatusOK, request(t, container, appToken, http.MethodGet, "/v1/kv2/data/app/db", nil))
+		require.Equal(t, http.StatusForbidden, request(t, container, appToken, http.MethodGet, "/v1/kv1/app/db", nil))
+	}
+}
+
+func TestPreset_defaults(t *testing.T) {
+	t.Parallel()
+
+	p := vault.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, request(t, container, vault.DefaultRootToken, http.MethodGet, "/v1/sys/mounts/secret/tune", nil))
+}
+
+func request(t *testing.T, c *gnomock.Container, token, method, path string, v interface{}) int {
+	t.Helper()
+
+	addr := fmt.Sprintf("http://%s%s", c.DefaultAddress(), path)
+
+	req, err := http.NewRequest(method, addr, bytes.NewReader(nil)) // nolint:noctx
+	require.NoError(t, err)
+
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	if v != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	}
+
+	return resp.StatusCode
+}
@@ -0,0 +1,42 @@
+package victoriametrics
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithRetention sets how long the samples are stored, for example "100y".
+// Samples older than the retention period are dropped on ingestion, so use a
+// long period to import samples with old timestamps. Default retention is one
+// month.
+func WithRetention(period string) Option {
+	return func(o *P) {
+		o.Retention = period
+	}
+}
+
+// WithSamples imports the provided samples in Prometheus exposition format
+// during initial setup, for example `up{job="api"} 1 1672531200000`.
+// Timestamps are in milliseconds, and samples without timestamps use the
+// current time. The samples are available for queries once the container is
+// ready. This option can be used multiple times.
+func WithSamples(samples ...string) Option {
+	return func(o *P) {
+		o.Samples = append(o.Samples, samples...)
+	}
+}
+
+// WithSamplesFile sets a file name to read initial samples in Prometheus
+// exposition format from. Samples from files are imported together with the
+// samples provided using WithSamples. This option can be used multiple times.
+func WithSamplesFile(file string) Option {
+	return func(o *P) {
+		o.SamplesFiles = append(o.SamplesFiles, file)
+	}
+}
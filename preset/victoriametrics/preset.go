@@ -0,0 +1,153 @@
+// Package victoriametrics includes VictoriaMetrics implementation of Gnomock
+// Preset interface. This Preset can be passed to gnomock.Start() function to
+// create a configured single-node VictoriaMetrics container to use in tests.
+//
+// The default port serves both ingestion and Prometheus compatible query
+// APIs, for example "/api/v1/query".
+package victoriametrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+const (
+	defaultVersion = "v1.89.1"
+	defaultPort    = 8428
+)
+
+func init() {
+	registry.Register("victoriametrics", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock VictoriaMetrics preset. This preset includes a
+// VictoriaMetrics specific healthcheck function and default VictoriaMetrics
+// image and port, and allows to optionally ingest samples during initial
+// setup.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for VictoriaMetrics.
+type P struct {
+	Version      string   `json:"version"`
+	Retention    string   `json:"retention"`
+	Samples      []string `json:"samples"`
+	SamplesFiles []string `json:"samples_files"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/victoriametrics/victoria-metrics:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+	}
+
+	if p.Retention != "" {
+		opts = append(opts, gnomock.WithCommand("-retentionPeriod="+p.Retention))
+	}
+
+	if len(p.Samples) > 0 || len(p.SamplesFiles) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	body, err := request(ctx, http.MethodGet, c, "/health", nil)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(body) != "OK" {
+		return fmt.Errorf("unexpected health status: %s", body)
+	}
+
+	return nil
+}
+
+// initf imports samples from files and the rest of the samples, and then
+// flushes them, so that they are immediately available for queries.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	samples := make([]string, 0, len(p.Samples))
+
+	for _, f := range p.SamplesFiles {
+		bs, err := os.ReadFile(f) // nolint:gosec
+		if err != nil {
+			return fmt.Errorf("can't read samples file '%s': %w", f, err)
+		}
+
+		samples = append(samples, string(bs))
+	}
+
+	samples = append(samples, p.Samples...)
+	data := strings.Join(samples, "\n") + "\n"
+
+	if _, err := request(ctx, http.MethodPost, c, "/api/v1/import/prometheus", strings.NewReader(data)); err != nil {
+		return fmt.Errorf("can't import samples: %w", err)
+	}
+
+	if _, err := request(ctx, http.MethodGet, c, "/internal/force_flush", nil); err != nil {
+		return fmt.Errorf("can't flush samples: %w", err)
+	}
+
+	return nil
+}
+
+func request(ctx context.Context, method string, c *gnomock.Container, path string, body io.Reader) (string, error) {
+	addr := fmt.Sprintf("http://%s%s", c.DefaultAddress(), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, addr, body)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("can't read response body: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return "", fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, strings.TrimSpace(string(bs)))
+	}
+
+	return string(bs), nil
+}
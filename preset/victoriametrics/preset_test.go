@@ -0,0 +1,74 @@
+package victoriametrics_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/victoriametrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"v1.87.3", "v1.89.1"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := victoriametrics.Preset(
+			victoriametrics.WithVersion(version),
+			victoriametrics.WithRetention("100y"),
+			victoriametrics.WithSamplesFile("./testdata/samples.prom"),
+			victoriametrics.WithSamples(`http_requests_total{job="web",code="200"} 5 1672531200000`),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		params := url.Values{
+			"query": []string{"sum(http_requests_total)"},
+			"time":  []string{"1672531200"},
+		}
+		addr := fmt.Sprintf("http://%s/api/v1/query?%s", container.DefaultAddress(), params.Encode())
+
+		resp, err := http.Get(addr) // nolint:gosec,noctx
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, resp.Body.Close()) }()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data struct {
+				Result []struct {
+					Value []interface{} `json:"value"`
+				} `json:"result"`
+			} `json:"data"`
+		}
+
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result.Data.Result, 1)
+		require.Equal(t, "17", result.Data.Result[0].Value[1])
+	}
+}
+
+func TestPreset_wrongSamplesFile(t *testing.T) {
+	t.Parallel()
+
+	p := victoriametrics.Preset(victoriametrics.WithSamplesFile("./testdata/missing.prom"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read samples file")
+}
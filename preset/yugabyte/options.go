@@ -0,0 +1,66 @@
+package yugabyte
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithDatabase creates a YSQL database with the provided name in the
+// container. If not provided, the default "yugabyte" database is used.
+// WithQueries, if provided, runs against this database.
+func WithDatabase(db string) Option {
+	return func(o *P) {
+		o.DB = db
+	}
+}
+
+// WithQueries executes the provided YSQL queries against the database created
+// with WithDatabase, or against the default database.
+func WithQueries(queries ...string) Option {
+	return func(o *P) {
+		o.Queries = append(o.Queries, queries...)
+	}
+}
+
+// WithQueriesFile sets a file name to read initial YSQL queries from. Queries
+// from this file are executed before any other queries provided in
+// WithQueries.
+func WithQueriesFile(file string) Option {
+	return func(o *P) {
+		o.QueriesFiles = append(o.QueriesFiles, file)
+	}
+}
+
+// WithKeyspace creates a YCQL keyspace with the provided name during initial
+// setup. Statements provided using WithCQLQueries and WithCQLFile are
+// executed in this keyspace.
+func WithKeyspace(name string) Option {
+	return func(o *P) {
+		o.Keyspace = name
+	}
+}
+
+// WithCQLQueries executes the provided YCQL statements during initial setup,
+// after the statements from files provided using WithCQLFile. Every query
+// should be a single statement. This option can be used multiple times.
+func WithCQLQueries(queries ...string) Option {
+	return func(o *P) {
+		o.CQLQueries = append(o.CQLQueries, queries...)
+	}
+}
+
+// WithCQLFile sets a file name to read initial YCQL statements from. The file
+// may include multiple statements separated by semicolons, and comments
+// starting with "--" or "//" on separate lines. This option can be used
+// multiple times, and the files are executed in the same order.
+func WithCQLFile(file string) Option {
+	return func(o *P) {
+		o.CQLFiles = append(o.CQLFiles, file)
+	}
+}
@@ -0,0 +1,220 @@
+// Package yugabyte includes YugabyteDB implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured YugabyteDB container to use in tests.
+//
+// YugabyteDB containers expose two APIs: YSQL, which is compatible with
+// PostgreSQL and is available on the default port, and YCQL, which is
+// compatible with Cassandra Query Language and is available on YCQLPort.
+// Both APIs can be set up separately using this preset options.
+package yugabyte
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/cql"
+	"github.com/orlangure/gnomock/internal/registry"
+	"github.com/orlangure/gnomock/internal/sqlsetup"
+)
+
+// YCQLPort is a name of the port exposed by YCQL API. Use it with any
+// Cassandra driver.
+const YCQLPort = "ycql"
+
+const (
+	defaultVersion  = "2.16.2.0-b41"
+	defaultPort     = 5433
+	ycqlPort        = 9042
+	defaultUser     = "yugabyte"
+	defaultDatabase = "yugabyte"
+)
+
+func init() {
+	registry.Register("yugabyte", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock YugabyteDB preset. This preset includes a
+// YugabyteDB specific healthcheck function, default YugabyteDB image and
+// ports, and allows to optionally set up initial state of both YSQL and YCQL
+// APIs.
+//
+// Containers created using this preset use `yugabyte` user without a
+// password.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for YugabyteDB.
+type P struct {
+	Version      string   `json:"version"`
+	DB           string   `json:"db"`
+	Queries      []string `json:"queries"`
+	QueriesFiles []string `json:"queries_files"`
+	Keyspace     string   `json:"keyspace"`
+	CQLQueries   []string `json:"cql_queries"`
+	CQLFiles     []string `json:"cql_files"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/yugabytedb/yugabyte:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	namedPorts := gnomock.DefaultTCP(defaultPort)
+	namedPorts[YCQLPort] = gnomock.Port{Protocol: "tcp", Port: ycqlPort}
+
+	return namedPorts
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithCommand("bin/yugabyted", "start", "--background=false"),
+		gnomock.WithInit(p.initf),
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.DB == "" {
+		p.DB = defaultDatabase
+	}
+}
+
+// healthcheck makes sure that both APIs accept queries, since they become
+// available independently.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	if err := ysqlHealthcheck(ctx, c); err != nil {
+		return fmt.Errorf("ysql is not ready: %w", err)
+	}
+
+	if err := ycqlHealthcheck(ctx, c); err != nil {
+		return fmt.Errorf("ycql is not ready: %w", err)
+	}
+
+	return nil
+}
+
+func ysqlHealthcheck(ctx context.Context, c *gnomock.Container) error {
+	db, err := connectYSQL(c, defaultDatabase)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	var one int
+
+	return db.QueryRowContext(ctx, `select 1`).Scan(&one)
+}
+
+func ycqlHealthcheck(ctx context.Context, c *gnomock.Container) error {
+	return cql.Node{Addr: c.Address(YCQLPort)}.Healthcheck(ctx)
+}
+
+// initf sets up YSQL and YCQL APIs, in this order.
+func (p *P) initf(ctx context.Context, c *gnomock.Container) error {
+	if err := p.initYSQL(ctx, c); err != nil {
+		return fmt.Errorf("can't set up ysql: %w", err)
+	}
+
+	if p.Keyspace != "" || len(p.CQLQueries) > 0 || len(p.CQLFiles) > 0 {
+		if err := p.initYCQL(c); err != nil {
+			return fmt.Errorf("can't set up ycql: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// initYSQL creates the database, unless it already exists, and executes
+// queries from files and the rest of the queries against it.
+func (p *P) initYSQL(ctx context.Context, c *gnomock.Container) error {
+	setup := sqlsetup.Setup{Queries: p.Queries, QueriesFiles: p.QueriesFiles}
+
+	queries, err := setup.AllQueries()
+	if err != nil {
+		return err
+	}
+
+	if p.DB != defaultDatabase {
+		db, err := connectYSQL(c, defaultDatabase)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, "create database "+pq.QuoteIdentifier(p.DB))
+
+		_ = db.Close()
+
+		if err != nil {
+			return fmt.Errorf("can't create database '%s': %w", p.DB, err)
+		}
+	}
+
+	db, err := connectYSQL(c, p.DB)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	return sqlsetup.Execute(ctx, db, queries)
+}
+
+// initYCQL creates the keyspace, if it is configured, and executes CQL
+// statements from files and queries in it.
+func (p *P) initYCQL(c *gnomock.Container) error {
+	return cql.Node{Addr: c.Address(YCQLPort)}.Init(p.Keyspace, p.CQLFiles, p.CQLQueries)
+}
+
+// ConnString returns a connection string that can be used to connect to the
+// YSQL database created in the provided container. Use the same options that
+// were used to create the preset, so that the connection string includes the
+// right database name.
+func ConnString(c *gnomock.Container, opts ...Option) string {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.setDefaults()
+
+	return connString(c, p.DB)
+}
+
+func connString(c *gnomock.Container, db string) string {
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s dbname=%s sslmode=disable",
+		c.Host, c.Port(gnomock.DefaultPort), defaultUser, db,
+	)
+}
+
+func connectYSQL(c *gnomock.Container, db string) (*sql.DB, error) {
+	conn, err := sql.Open("postgres", connString(c, db))
+	if err != nil {
+		return nil, err
+	}
+
+	return conn, conn.Ping()
+}
@@ -0,0 +1,91 @@
+package yugabyte_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/gocql/gocql"
+	_ "github.com/lib/pq"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/yugabyte"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"2.14.7.0-b51", "2.16.2.0-b41"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		opts := []yugabyte.Option{
+			yugabyte.WithVersion(version),
+			yugabyte.WithDatabase("gnomock"),
+			yugabyte.WithQueriesFile("./testdata/queries.sql"),
+			yugabyte.WithQueries("insert into things (id, name) values (3, 'baz')"),
+			yugabyte.WithKeyspace("gnomock"),
+			yugabyte.WithCQLFile("./testdata/schema.cql"),
+			yugabyte.WithCQLQueries("insert into users (id, name) values (3, 'baz')"),
+		}
+
+		container, err := gnomock.Start(yugabyte.Preset(opts...))
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		db, err := sql.Open("postgres", yugabyte.ConnString(container, opts...))
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, db.Close()) }()
+
+		var count int
+
+		require.NoError(t, db.QueryRow("select count(*) from things").Scan(&count))
+		require.Equal(t, 3, count)
+
+		cluster := gocql.NewCluster(container.Address(yugabyte.YCQLPort))
+		cluster.Keyspace = "gnomock"
+		cluster.DisableInitialHostLookup = true
+
+		session, err := cluster.CreateSession()
+		require.NoError(t, err)
+
+		defer session.Close()
+
+		var name string
+
+		require.NoError(t, session.Query("select name from users where id = 3").Scan(&name))
+		require.Equal(t, "baz", name)
+	}
+}
+
+func TestPreset_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	container, err := gnomock.Start(yugabyte.Preset())
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", yugabyte.ConnString(container))
+	require.NoError(t, err)
+	require.NoError(t, db.Ping())
+	require.NoError(t, db.Close())
+}
+
+func TestPreset_wrongCQLFile(t *testing.T) {
+	t.Parallel()
+
+	p := yugabyte.Preset(yugabyte.WithCQLFile("./testdata/missing.cql"))
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't read cql file")
+}
@@ -0,0 +1,31 @@
+package zipkin
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithCassandraStorage makes Zipkin store spans in Cassandra available at the
+// provided contact points, for example "cassandra:9042", instead of keeping
+// them in memory. The contact points must be reachable from the container,
+// for example over a shared docker network. The schema is created in
+// "zipkin2" keyspace, unless another keyspace is set using
+// WithCassandraKeyspace.
+func WithCassandraStorage(contactPoints ...string) Option {
+	return func(o *P) {
+		o.CassandraContactPoints = append(o.CassandraContactPoints, contactPoints...)
+	}
+}
+
+// WithCassandraKeyspace sets the keyspace to use with Cassandra storage.
+func WithCassandraKeyspace(keyspace string) Option {
+	return func(o *P) {
+		o.CassandraKeyspace = keyspace
+	}
+}
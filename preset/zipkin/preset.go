@@ -0,0 +1,121 @@
+// Package zipkin includes Zipkin implementation of Gnomock Preset interface.
+// This Preset can be passed to gnomock.Start() function to create a configured
+// Zipkin container to use in tests.
+//
+// The default port serves both span ingestion, for example "/api/v2/spans",
+// and query APIs. Spans are stored in memory unless Cassandra storage is
+// configured using WithCassandraStorage.
+package zipkin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+const (
+	defaultVersion  = "2.24"
+	defaultPort     = 9411
+	defaultKeyspace = "zipkin2"
+)
+
+func init() {
+	registry.Register("zipkin", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock Zipkin preset. This preset includes a Zipkin
+// specific healthcheck function and default Zipkin image and port, and allows
+// to optionally use Cassandra storage.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for Zipkin.
+type P struct {
+	Version                string   `json:"version"`
+	CassandraContactPoints []string `json:"cassandra_contact_points"`
+	CassandraKeyspace      string   `json:"cassandra_keyspace"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/openzipkin/zipkin:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+	}
+
+	if len(p.CassandraContactPoints) == 0 {
+		return append(opts, gnomock.WithEnv("STORAGE_TYPE=mem"))
+	}
+
+	return append(
+		opts,
+		gnomock.WithEnv("STORAGE_TYPE=cassandra3"),
+		gnomock.WithEnv("CASSANDRA_CONTACT_POINTS="+strings.Join(p.CassandraContactPoints, ",")),
+		gnomock.WithEnv("CASSANDRA_KEYSPACE="+p.CassandraKeyspace),
+	)
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+
+	if p.CassandraKeyspace == "" {
+		p.CassandraKeyspace = defaultKeyspace
+	}
+}
+
+// healthcheck makes sure that the server and its storage are up. With
+// Cassandra storage, this happens after the schema is created.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	addr := fmt.Sprintf("http://%s/health", c.DefaultAddress())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var health struct {
+		Status string `json:"status"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return fmt.Errorf("can't decode health status (status %d): %w", resp.StatusCode, err)
+	}
+
+	if health.Status != "UP" {
+		return fmt.Errorf("unexpected health status: %s", health.Status)
+	}
+
+	return nil
+}
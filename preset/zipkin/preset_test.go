@@ -0,0 +1,100 @@
+package zipkin_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/cassandra"
+	"github.com/orlangure/gnomock/preset/zipkin"
+	"github.com/stretchr/testify/require"
+)
+
+const traceID = "5b8efff798038103"
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"2.23", "2.24"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := zipkin.Preset(zipkin.WithVersion(version))
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+		requireSpan(t, container)
+	}
+}
+
+func TestPreset_cassandraStorage(t *testing.T) {
+	t.Parallel()
+
+	db, err := gnomock.Start(cassandra.Preset())
+
+	defer func() { require.NoError(t, gnomock.Stop(db)) }()
+
+	require.NoError(t, err)
+
+	p := zipkin.Preset(
+		zipkin.WithCassandraStorage(fmt.Sprintf("host.docker.internal:%d", db.DefaultPort())),
+		zipkin.WithCassandraKeyspace("gnomock"),
+	)
+	container, err := gnomock.Start(
+		p,
+		gnomock.WithExtraHosts([]string{"host.docker.internal:host-gateway"}),
+		gnomock.WithTimeout(time.Minute*3),
+	)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+	requireSpan(t, container)
+}
+
+func requireSpan(t *testing.T, c *gnomock.Container) {
+	t.Helper()
+
+	ts := time.Now().UnixNano() / int64(time.Microsecond)
+	span := fmt.Sprintf(
+		`[{"traceId":%q,"id":%q,"name":"test","timestamp":%d,"duration":1000,"localEndpoint":{"serviceName":"gnomock"}}]`,
+		traceID, traceID, ts,
+	)
+
+	addr := fmt.Sprintf("http://%s/api/v2/spans", c.DefaultAddress())
+
+	resp, err := http.Post(addr, "application/json", strings.NewReader(span)) // nolint:gosec,noctx
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	addr = fmt.Sprintf("http://%s/api/v2/trace/%s", c.DefaultAddress(), traceID)
+
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(addr) // nolint:gosec,noctx
+		if err != nil {
+			return false
+		}
+
+		defer func() { _ = resp.Body.Close() }()
+
+		var spans []struct {
+			Name string `json:"name"`
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&spans); err != nil {
+			return false
+		}
+
+		return len(spans) == 1 && spans[0].Name == "test"
+	}, time.Second*10, time.Millisecond*250)
+}
@@ -0,0 +1,29 @@
+package zookeeper
+
+// Option is an optional configuration of this Gnomock preset. Use available
+// Options to configure the container.
+type Option func(*P)
+
+// WithVersion sets image version.
+func WithVersion(version string) Option {
+	return func(o *P) {
+		o.Version = version
+	}
+}
+
+// WithZnodes creates persistent znodes with the provided absolute paths and
+// data, for example {"/config/app/name": "gnomock"}. Missing parent znodes,
+// such as "/config" and "/config/app", are created without data. This option
+// can be used multiple times; later data of the same znode replaces earlier
+// data.
+func WithZnodes(znodes map[string]string) Option {
+	return func(o *P) {
+		if o.Znodes == nil {
+			o.Znodes = make(map[string]string, len(znodes))
+		}
+
+		for znode, data := range znodes {
+			o.Znodes[znode] = data
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package zookeeper includes Apache ZooKeeper implementation of Gnomock Preset
+// interface. This Preset can be passed to gnomock.Start() function to create a
+// configured standalone ZooKeeper server to use in tests.
+//
+// The default port accepts client connections, and also answers four letter
+// word commands "ruok", "srvr", "stat" and "mntr".
+package zookeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/internal/registry"
+)
+
+const (
+	defaultVersion = "3.8.1"
+	defaultPort    = 2181
+	sessionTimeout = time.Second * 5
+)
+
+func init() {
+	registry.Register("zookeeper", func() gnomock.Preset { return &P{} })
+}
+
+// Preset creates a new Gmomock ZooKeeper preset. This preset includes a
+// ZooKeeper specific healthcheck function and default ZooKeeper image and
+// port, and allows to optionally create initial znodes.
+func Preset(opts ...Option) gnomock.Preset {
+	p := &P{}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// P is a Gnomock Preset implementation for ZooKeeper.
+type P struct {
+	Version string            `json:"version"`
+	Znodes  map[string]string `json:"znodes"`
+}
+
+// Image returns an image that should be pulled to create this container.
+func (p *P) Image() string {
+	return fmt.Sprintf("docker.io/library/zookeeper:%s", p.Version)
+}
+
+// Ports returns ports that should be used to access this container.
+func (p *P) Ports() gnomock.NamedPorts {
+	return gnomock.DefaultTCP(defaultPort)
+}
+
+// Options returns a list of options to configure this container.
+func (p *P) Options() []gnomock.Option {
+	p.setDefaults()
+
+	opts := []gnomock.Option{
+		gnomock.WithHealthCheck(healthcheck),
+		gnomock.WithEnv("ZOO_4LW_COMMANDS_WHITELIST=ruok,srvr,stat,mntr"),
+		gnomock.WithEnv("ZOO_STANDALONE_ENABLED=true"),
+	}
+
+	if len(p.Znodes) > 0 {
+		opts = append(opts, gnomock.WithInit(p.initf))
+	}
+
+	return opts
+}
+
+func (p *P) setDefaults() {
+	if p.Version == "" {
+		p.Version = defaultVersion
+	}
+}
+
+// healthcheck makes sure that the server is running without errors, and that
+// it serves client requests.
+func healthcheck(ctx context.Context, c *gnomock.Container) error {
+	res, err := fourLetterWord(ctx, c.DefaultAddress(), "ruok")
+	if err != nil {
+		return err
+	}
+
+	if res != "imok" {
+		return fmt.Errorf("unexpected ruok response: %s", res)
+	}
+
+	// ruok is answered before the server is ready to serve clients, while
+	// srvr reports an error until then
+	res, err = fourLetterWord(ctx, c.DefaultAddress(), "srvr")
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(res, "Mode: ") {
+		return fmt.Errorf("unexpected srvr response: %s", res)
+	}
+
+	return nil
+}
+
+// fourLetterWord sends the provided four letter word command to the server,
+// and returns its response.
+func fourLetterWord(ctx context.Context, addr, cmd string) (string, error) {
+	d := net.Dialer{}
+
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := conn.Write([]byte(cmd)); err != nil {
+		return "", fmt.Errorf("can't send '%s' command: %w", cmd, err)
+	}
+
+	bs, err := io.ReadAll(conn)
+	if err != nil {
+		return "", fmt.Errorf("can't read '%s' response: %w", cmd, err)
+	}
+
+	return strings.TrimSpace(string(bs)), nil
+}
+
+// initf creates the configured znodes in order of their paths. Missing parent
+// znodes are created without data.
+func (p *P) initf(_ context.Context, c *gnomock.Container) error {
+	conn, _, err := zk.Connect([]string{c.DefaultAddress()}, sessionTimeout, zk.WithLogInfo(false))
+	if err != nil {
+		return fmt.Errorf("can't connect to zookeeper: %w", err)
+	}
+
+	defer conn.Close()
+
+	paths := make([]string, 0, len(p.Znodes))
+
+	for znode := range p.Znodes {
+		paths = append(paths, znode)
+	}
+
+	sort.Strings(paths)
+
+	for _, znode := range paths {
+		if err := createParents(conn, znode); err != nil {
+			return err
+		}
+
+		_, err := conn.Create(znode, []byte(p.Znodes[znode]), 0, zk.WorldACL(zk.PermAll))
+		if err == nil {
+			continue
+		}
+
+		// znodes already exist when an existing container is reused
+		if !errors.Is(err, zk.ErrNodeExists) {
+			return fmt.Errorf("can't create znode '%s': %w", znode, err)
+		}
+
+		if _, err := conn.Set(znode, []byte(p.Znodes[znode]), -1); err != nil {
+			return fmt.Errorf("can't set znode '%s' data: %w", znode, err)
+		}
+	}
+
+	return nil
+}
+
+func createParents(conn *zk.Conn, znode string) error {
+	parent := path.Dir(znode)
+	if parent == "/" || parent == "." {
+		return nil
+	}
+
+	if err := createParents(conn, parent); err != nil {
+		return err
+	}
+
+	_, err := conn.Create(parent, nil, 0, zk.WorldACL(zk.PermAll))
+	if err != nil && !errors.Is(err, zk.ErrNodeExists) {
+		return fmt.Errorf("can't create znode '%s': %w", parent, err)
+	}
+
+	return nil
+}
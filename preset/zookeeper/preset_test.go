@@ -0,0 +1,83 @@
+package zookeeper_test
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/orlangure/gnomock"
+	"github.com/orlangure/gnomock/preset/zookeeper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"3.7.1", "3.8.1"} {
+		t.Run(version, testPreset(version))
+	}
+}
+
+func testPreset(version string) func(t *testing.T) {
+	return func(t *testing.T) {
+		p := zookeeper.Preset(
+			zookeeper.WithVersion(version),
+			zookeeper.WithZnodes(map[string]string{
+				"/config/app/name": "gnomock",
+				"/config":          "root",
+			}),
+			zookeeper.WithZnodes(map[string]string{"/election": ""}),
+		)
+		container, err := gnomock.Start(p)
+
+		defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+		require.NoError(t, err)
+
+		conn, _, err := zk.Connect([]string{container.DefaultAddress()}, time.Second*5, zk.WithLogInfo(false))
+		require.NoError(t, err)
+
+		defer conn.Close()
+
+		data, _, err := conn.Get("/config/app/name")
+		require.NoError(t, err)
+		require.Equal(t, "gnomock", string(data))
+
+		data, _, err = conn.Get("/config")
+		require.NoError(t, err)
+		require.Equal(t, "root", string(data))
+
+		children, _, err := conn.Children("/config/app")
+		require.NoError(t, err)
+		require.Equal(t, []string{"name"}, children)
+
+		exists, _, err := conn.Exists("/election")
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+}
+
+func TestPreset_fourLetterWords(t *testing.T) {
+	t.Parallel()
+
+	p := zookeeper.Preset()
+	container, err := gnomock.Start(p)
+
+	defer func() { require.NoError(t, gnomock.Stop(container)) }()
+
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", container.DefaultAddress())
+	require.NoError(t, err)
+
+	defer func() { _ = conn.Close() }()
+
+	_, err = conn.Write([]byte("srvr"))
+	require.NoError(t, err)
+
+	bs, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	require.Contains(t, string(bs), "Mode: standalone")
+}